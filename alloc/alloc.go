@@ -0,0 +1,109 @@
+// Package alloc provides node allocators for the singly and doubly lists.
+//
+// By default a list allocates every node with new and leaves removed nodes to
+// the garbage collector. Setting a list's Allocator makes it take nodes from
+// the allocator instead and hand back the ones it removes, which cuts
+// allocations for lists that grow and shrink all the time, such as queues.
+package alloc
+
+import "sync"
+
+// DefaultBlockSize is the number of values per block of an Arena created
+// with a block size of zero.
+const DefaultBlockSize = 256
+
+// Allocator hands out zeroed values of type T and takes them back for reuse.
+// Free must zero the value, since the lists leave that to the allocator. A
+// value passed to Free must not be used again by the caller.
+type Allocator[T any] interface {
+	New() *T
+	Free(p *T)
+}
+
+// Pool is an Allocator backed by a sync.Pool. Freed values can be reused by
+// any list sharing the pool, and the garbage collector may still reclaim
+// them while they are unused. The zero value is ready to use, and a Pool is
+// safe for concurrent use.
+type Pool[T any] struct {
+	pool sync.Pool
+}
+
+// NewPool returns an empty Pool.
+func NewPool[T any]() *Pool[T] {
+	return &Pool[T]{}
+}
+
+// New returns a freed value if there is one, or else a new one.
+func (p *Pool[T]) New() *T {
+	if v, ok := p.pool.Get().(*T); ok {
+		return v
+	}
+	return new(T)
+}
+
+// Free zeroes v and keeps it for reuse.
+func (p *Pool[T]) Free(v *T) {
+	var zero T
+	*v = zero
+	p.pool.Put(v)
+}
+
+// Arena is an Allocator that carves values out of contiguous blocks, so a
+// growing list costs one allocation per block instead of one per node. Freed
+// values go on a free list and are handed out again before the current block
+// is used up. A block stays in memory as long as any of its values is
+// reachable. An Arena is not safe for concurrent use.
+type Arena[T any] struct {
+	blockSize int
+	block     []T  // Unused tail of the newest block
+	free      []*T // Freed values, reused last in first out
+	blocks    int
+	live      int
+}
+
+// NewArena returns an Arena that allocates blockSize values at a time. A
+// blockSize of zero or less means DefaultBlockSize.
+func NewArena[T any](blockSize int) *Arena[T] {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	return &Arena[T]{blockSize: blockSize}
+}
+
+// New returns a freed value if there is one, or else the next value of the
+// current block, starting a new block when it is used up.
+func (a *Arena[T]) New() *T {
+	a.live++
+	if n := len(a.free); n > 0 {
+		v := a.free[n-1]
+		a.free[n-1] = nil
+		a.free = a.free[:n-1]
+		return v
+	}
+	if len(a.block) == 0 {
+		a.block = make([]T, a.blockSize)
+		a.blocks++
+	}
+	v := &a.block[0]
+	a.block = a.block[1:]
+	return v
+}
+
+// Free zeroes v and puts it on the free list. Freeing a value twice hands it
+// out twice, corrupting whatever lists then use it.
+func (a *Arena[T]) Free(v *T) {
+	var zero T
+	*v = zero
+	a.free = append(a.free, v)
+	a.live--
+}
+
+// Blocks returns the number of blocks the arena has allocated.
+func (a *Arena[T]) Blocks() int {
+	return a.blocks
+}
+
+// Live returns the number of values handed out and not yet freed.
+func (a *Arena[T]) Live() int {
+	return a.live
+}
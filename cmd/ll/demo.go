@@ -7,7 +7,8 @@ import (
 	"github.com/JustMrNone/ll/singly"
 )
 
-func main() {
+// runDemo walks through the main operations of both list types.
+func runDemo() {
 	fmt.Println("=== Singly Linked List Demo ===")
 	demonstrateSinglyLinkedList()
 
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supported input and output formats.
+const (
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatLines = "lines"
+)
+
+// readValues parses the whole of r in the given format.
+func readValues(r io.Reader, format string) ([]any, error) {
+	switch format {
+	case formatJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		var raw []any
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON input: %v", err)
+		}
+		values := make([]any, len(raw))
+		for i, v := range raw {
+			values[i] = fromJSON(v)
+		}
+		return values, nil
+	case formatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV input: %v", err)
+		}
+		values := []any{}
+		for _, record := range records {
+			for _, field := range record {
+				values = append(values, parseValue(field))
+			}
+		}
+		return values, nil
+	case formatLines:
+		values := []any{}
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if line == "" {
+				continue
+			}
+			values = append(values, parseValue(line))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want json, csv or lines)", format)
+}
+
+// writeValues writes values to w in the given format.
+func writeValues(w io.Writer, values []any, format string) error {
+	switch format {
+	case formatJSON:
+		if values == nil {
+			values = []any{}
+		}
+		data, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case formatCSV:
+		record := make([]string, len(values))
+		for i, v := range values {
+			record[i] = fmt.Sprint(v)
+		}
+		writer := csv.NewWriter(w)
+		writer.Write(record)
+		writer.Flush()
+		return writer.Error()
+	case formatLines:
+		var buf bytes.Buffer
+		for _, v := range values {
+			fmt.Fprintln(&buf, v)
+		}
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+	return fmt.Errorf("unknown format %q (want json, csv or lines)", format)
+}
+
+// writeValue writes a single value to w in the given format.
+func writeValue(w io.Writer, value any, format string) error {
+	if format == formatJSON {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+	_, err := fmt.Fprintln(w, value)
+	return err
+}
+
+// parseValue turns text into an int, a float64 or, failing both, a string,
+// so that numeric input sorts numerically.
+func parseValue(s string) any {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+// fromJSON converts a decoded JSON number into an int when it is integral
+// and a float64 otherwise, matching parseValue.
+func fromJSON(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := strconv.Atoi(n.String()); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
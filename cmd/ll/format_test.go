@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzFormats checks that every input format parses without panicking and
+// that writing is stable: reading the output back and writing it again gives
+// the same bytes.
+func FuzzFormats(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte, choice uint8) {
+		formats := []string{formatJSON, formatCSV, formatLines}
+		format := formats[int(choice)%len(formats)]
+
+		values, err := readValues(bytes.NewReader(data), format)
+		if err != nil {
+			return
+		}
+		var first bytes.Buffer
+		if err := writeValues(&first, values, format); err != nil {
+			t.Fatalf("writeValues failed for %v: %v", values, err)
+		}
+		again, err := readValues(bytes.NewReader(first.Bytes()), format)
+		if err != nil {
+			t.Fatalf("Output does not parse as %s: %v\n%s", format, err, first.Bytes())
+		}
+		var second bytes.Buffer
+		writeValues(&second, again, format)
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Fatalf("%s output changed on a second round trip:\n%q\n%q", format, first.Bytes(), second.Bytes())
+		}
+	})
+}
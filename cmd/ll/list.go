@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// list is the part of the library's API the command line works with. The
+// adapters below paper over the small signature differences between the
+// singly and doubly lists without changing their semantics.
+type list interface {
+	Kind() string
+	Len() int
+	FromSlice(values []any) error
+	IntoSlice() []any
+	Append(value any) error
+	Prepend(value any) error
+	Insert(value any, index int) error
+	Delete(value any) error
+	DeleteAt(index int) error
+	Shift() error
+	Pop() error
+	Clear()
+	Get(index int) (any, error)
+	Search(value any) (int, error)
+	Move(from, to int) error
+	Sort() error
+	Reverse() error
+	Unique() error
+	GetMiddle() (any, error)
+	Validate() error
+}
+
+// newList creates an empty list of the given kind.
+func newList(kind string) (list, error) {
+	switch kind {
+	case "singly":
+		return singlyList{singly.NewSinglyLinkedList()}, nil
+	case "doubly":
+		return doublyList{doubly.NewDoublyLinkedList()}, nil
+	}
+	return nil, fmt.Errorf("unknown list kind %q (want singly or doubly)", kind)
+}
+
+type singlyList struct {
+	*singly.LinkedList
+}
+
+func (l singlyList) Kind() string {
+	return "singly"
+}
+
+func (l singlyList) Len() int {
+	return l.Size
+}
+
+func (l singlyList) Append(value any) error {
+	l.LinkedList.Append(value)
+	return nil
+}
+
+func (l singlyList) Prepend(value any) error {
+	l.LinkedList.Prepend(value)
+	return nil
+}
+
+// Search reports -1 for a missing value without an error, as the singly
+// list does.
+func (l singlyList) Search(value any) (int, error) {
+	return l.LinkedList.Search(value), nil
+}
+
+func (l singlyList) Reverse() error {
+	l.LinkedList.Reverse()
+	return nil
+}
+
+type doublyList struct {
+	*doubly.LinkedList
+}
+
+func (l doublyList) Kind() string {
+	return "doubly"
+}
+
+func (l doublyList) Len() int {
+	return l.Size
+}
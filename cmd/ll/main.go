@@ -0,0 +1,273 @@
+// Command ll applies linked list operations to data from the shell, using the
+// same semantics as the library.
+//
+// Usage:
+//
+//	ll <command> [flags] [arguments]
+//
+// The list is read from -in (standard input by default), the command is
+// applied, and the result is written to standard output.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/singly"
+	"github.com/JustMrNone/ll/viz"
+)
+
+// Exit codes. Library errors map onto their kind so scripts can tell them
+// apart.
+const (
+	exitOK       = 0
+	exitFailure  = 1 // Any error without a more specific code
+	exitUsage    = 2 // Bad command line
+	exitInput    = 3 // Input could not be read or parsed
+	exitEmpty    = 4 // ErrEmpty
+	exitIndex    = 5 // ErrIndexOutOfBounds
+	exitNotFound = 6 // ErrNotFound
+	exitType     = 7 // ErrMismatchedTypes or ErrUnsupportedType
+	exitCorrupt  = 8 // ErrCorrupt
+)
+
+const usage = `Usage: ll <command> [flags] [arguments]
+
+Commands:
+  sort                  sort the list (int, string or float64 values)
+  reverse               reverse the list
+  unique                remove duplicate values
+  insert VALUE INDEX    insert VALUE at INDEX
+  delete-at INDEX       remove the element at INDEX
+  middle                print the middle element
+  validate              check the list structure
+  dot [-mermaid]        draw the list structure as Graphviz DOT or Mermaid
+  repl [-f SCRIPT]      explore lists interactively (see "help" inside)
+  demo                  run the library demo
+
+Flags:
+  -in FILE      read the list from FILE instead of standard input
+  -from FORMAT  input format: json, csv or lines (default lines)
+  -to FORMAT    output format (default: the input format)
+  -list KIND    list implementation: singly or doubly (default doubly)
+
+Exit codes:
+  0 success, 1 other error, 2 usage, 3 bad input, 4 empty list,
+  5 index out of bounds, 6 value not found, 7 type error, 8 corrupt list
+`
+
+// usageError reports a bad command line.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// inputError reports input that could not be read or parsed.
+type inputError struct {
+	err error
+}
+
+func (e inputError) Error() string {
+	return e.err.Error()
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	err := dispatch(args[0], args[1:], stdin, stdout, stderr)
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "ll: %v\n", err)
+	return exitCode(err)
+}
+
+// dispatch runs a single command.
+func dispatch(command string, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	switch command {
+	case "demo":
+		runDemo()
+		return nil
+	case "repl":
+		return runREPL(args, stdin, stdout, stderr)
+	case "sort", "reverse", "unique", "insert", "delete-at", "middle", "validate", "dot":
+		return runListCommand(command, args, stdin, stdout, stderr)
+	}
+	return usageError{fmt.Sprintf("unknown command %q", command)}
+}
+
+// runListCommand loads a list, applies command to it and writes the result.
+func runListCommand(command string, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("ll "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	in := flags.String("in", "", "read the list from `file` instead of standard input")
+	from := flags.String("from", formatLines, "input `format`: json, csv or lines")
+	to := flags.String("to", "", "output `format` (default: the input format)")
+	kind := flags.String("list", "doubly", "list implementation: singly or doubly")
+	var mermaid *bool
+	if command == "dot" {
+		mermaid = flags.Bool("mermaid", false, "write Mermaid instead of DOT")
+	}
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if *to == "" {
+		*to = *from
+	}
+
+	// Check the arguments before touching the input
+	want := map[string]int{"insert": 2, "delete-at": 1}[command]
+	if len(positional) != want {
+		return usageError{fmt.Sprintf("%s takes %d argument(s), got %d", command, want, len(positional))}
+	}
+	var index int
+	if command == "insert" || command == "delete-at" {
+		index, err = strconv.Atoi(positional[len(positional)-1])
+		if err != nil {
+			return usageError{fmt.Sprintf("invalid index %q", positional[len(positional)-1])}
+		}
+	}
+
+	l, err := newList(*kind)
+	if err != nil {
+		return usageError{err.Error()}
+	}
+	values, err := load(*in, *from, stdin)
+	if err != nil {
+		return err
+	}
+	if err := l.FromSlice(values); err != nil {
+		return err
+	}
+
+	switch command {
+	case "sort":
+		err = l.Sort()
+	case "reverse":
+		err = l.Reverse()
+	case "unique":
+		err = l.Unique()
+	case "insert":
+		err = l.Insert(parseValue(positional[0]), index)
+	case "delete-at":
+		err = l.DeleteAt(index)
+	case "middle":
+		middle, err := l.GetMiddle()
+		if err != nil {
+			return err
+		}
+		return writeValue(stdout, middle, *to)
+	case "validate":
+		return l.Validate()
+	case "dot":
+		g := graph(l)
+		if *mermaid {
+			return g.WriteMermaid(stdout)
+		}
+		return g.WriteDOT(stdout)
+	}
+	if err != nil {
+		return err
+	}
+	return writeValues(stdout, l.IntoSlice(), *to)
+}
+
+// parseInterspersed parses flags that may appear before, between or after
+// the positional arguments, which it returns in order. Use -- to pass an
+// argument that starts with a dash.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageError{err.Error()}
+		}
+		rest := flags.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// graph builds the structure diagram of l.
+func graph(l list) *viz.Graph {
+	switch l := l.(type) {
+	case singlyList:
+		return viz.Singly(l.LinkedList)
+	case doublyList:
+		return viz.Doubly(l.LinkedList)
+	}
+	panic(fmt.Sprintf("no diagram for %T", l))
+}
+
+// load reads the input values from path, or from stdin when path is empty.
+func load(path, format string, stdin io.Reader) ([]any, error) {
+	r := stdin
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, inputError{err}
+		}
+		defer f.Close()
+		r = f
+	}
+	values, err := readValues(r, format)
+	if err != nil {
+		return nil, inputError{err}
+	}
+	return values, nil
+}
+
+// exitCode maps an error to the process exit code for its kind.
+func exitCode(err error) int {
+	var usageErr usageError
+	var inputErr inputError
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &inputErr):
+		return exitInput
+	case errors.Is(err, singly.ErrEmpty), errors.Is(err, doubly.ErrEmpty):
+		return exitEmpty
+	case errors.Is(err, singly.ErrIndexOutOfBounds), errors.Is(err, doubly.ErrIndexOutOfBounds):
+		return exitIndex
+	case errors.Is(err, singly.ErrNotFound), errors.Is(err, doubly.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, singly.ErrMismatchedTypes), errors.Is(err, doubly.ErrMismatchedTypes),
+		errors.Is(err, singly.ErrUnsupportedType), errors.Is(err, doubly.ErrUnsupportedType):
+		return exitType
+	case errors.Is(err, singly.ErrCorrupt), errors.Is(err, doubly.ErrCorrupt):
+		return exitCorrupt
+	}
+	return exitFailure
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+		out   string
+	}{
+		{"Sort Lines", []string{"sort"}, "3\n1\n2\n", exitOK, "1\n2\n3\n"},
+		{"JSON To CSV", []string{"reverse", "-from", "json", "-to", "csv"}, `[1, 2.5, "x"]`, exitOK, "x,2.5,1\n"},
+		{"Insert", []string{"insert", "b", "1", "-from", "csv", "-list", "singly"}, "a,c\n", exitOK, "a,b,c\n"},
+		{"Middle", []string{"middle", "-from", "json"}, `[1, 2, 3, 4]`, exitOK, "2\n"},
+		{"Unique", []string{"unique"}, "a\nb\na\n", exitOK, "a\nb\n"},
+		{"Validate", []string{"validate"}, "1\n", exitOK, ""},
+		{"Empty", []string{"middle"}, "", exitEmpty, ""},
+		{"Out Of Bounds", []string{"delete-at", "2"}, "1\n2\n", exitIndex, ""},
+		{"Mixed Types", []string{"sort"}, "1\na\n", exitType, ""},
+		{"Bad JSON", []string{"sort", "-from", "json"}, "{", exitInput, ""},
+		{"Unknown Command", []string{"shuffle"}, "", exitUsage, ""},
+		{"Missing Argument", []string{"insert", "1"}, "", exitUsage, ""},
+		{"Unknown Kind", []string{"sort", "-list", "circular"}, "", exitUsage, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(c.args, strings.NewReader(c.input), &stdout, &stderr)
+			if code != c.code {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", c.code, code, stderr.String())
+			}
+			if code == exitOK && stdout.String() != c.out {
+				t.Errorf("Expected output %q, got %q", c.out, stdout.String())
+			}
+		})
+	}
+}
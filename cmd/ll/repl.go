@@ -0,0 +1,526 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const replHelp = `Session commands:
+  new NAME singly|doubly [VALUE...]   create a list
+  drop NAME                           delete a list
+  lists                               show every list
+  history                             show the commands entered so far
+  undo                                revert the last change
+  save FILE / load FILE               write or read the session as JSON
+  help                                show this help
+  quit                                leave the REPL
+
+List commands (NAME COMMAND [ARGS]):
+  append V, prepend V, insert V I, delete V, delete-at I, move FROM TO,
+  shift, pop, clear, reverse, sort, unique,
+  get I, search V, middle, validate, print
+
+Values are parsed as int, then float64, then string; quote a value to keep
+it a string. Lines starting with # are comments.
+`
+
+// mutating lists the commands that may change the session, and so get an
+// undo snapshot.
+var mutating = map[string]bool{
+	"new": true, "drop": true, "load": true,
+	"append": true, "prepend": true, "insert": true, "delete": true,
+	"delete-at": true, "move": true, "shift": true, "pop": true,
+	"clear": true, "reverse": true, "sort": true, "unique": true,
+}
+
+// errQuit ends the REPL.
+var errQuit = errors.New("quit")
+
+// savedList is the JSON form of one list in a session.
+type savedList struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Values []any  `json:"values"`
+}
+
+// savedSession is the JSON form of a whole session.
+type savedSession struct {
+	Lists   []savedList `json:"lists"`
+	History []string    `json:"history"`
+}
+
+// session holds the named lists of a REPL session.
+type session struct {
+	lists   map[string]list
+	history []string
+	undo    [][]savedList // Snapshots taken before each change
+	out     io.Writer
+}
+
+func newSession(out io.Writer) *session {
+	return &session{lists: make(map[string]list), out: out}
+}
+
+// runREPL implements the repl command. It reads commands from a script given
+// with -f, or from standard input, prompting when that is a terminal.
+func runREPL(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("ll repl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	script := flags.String("f", "", "run commands from `file` non-interactively")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err.Error()}
+	}
+	if flags.NArg() > 0 {
+		return usageError{"repl takes no arguments"}
+	}
+
+	in := stdin
+	interactive := false
+	if *script != "" {
+		f, err := os.Open(*script)
+		if err != nil {
+			return inputError{err}
+		}
+		defer f.Close()
+		in = f
+	} else if f, ok := stdin.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			interactive = true
+		}
+	}
+
+	s := newSession(stdout)
+	var firstErr error
+	scanner := bufio.NewScanner(in)
+	for {
+		if interactive {
+			fmt.Fprint(stdout, "ll> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !interactive {
+			// Echo commands so a script's output reads as a transcript
+			fmt.Fprintf(stdout, "> %s\n", line)
+		}
+
+		err := s.execute(line)
+		if err == errQuit {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "error: %v\n", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return inputError{err}
+	}
+	if interactive {
+		return nil
+	}
+	// A script reports its first failure through the exit code
+	return firstErr
+}
+
+// execute runs one command line, recording it in the history and taking an
+// undo snapshot if it changes anything.
+func (s *session) execute(line string) error {
+	args, err := tokenize(line)
+	if err != nil {
+		return err
+	}
+	command := args[0].text
+	if len(args) > 1 {
+		if _, ok := s.lists[command]; ok {
+			command = args[1].text
+		}
+	}
+	if command != "history" {
+		s.history = append(s.history, line)
+	}
+
+	if !mutating[command] {
+		return s.dispatch(args)
+	}
+	before := s.snapshot()
+	err = s.dispatch(args)
+	if !reflect.DeepEqual(before, s.snapshot()) {
+		s.undo = append(s.undo, before)
+	}
+	return err
+}
+
+// dispatch runs a tokenized command.
+func (s *session) dispatch(args []token) error {
+	switch args[0].text {
+	case "quit", "exit":
+		return errQuit
+	case "help":
+		fmt.Fprint(s.out, replHelp)
+		return nil
+	case "new":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: new NAME singly|doubly [VALUE...]")
+		}
+		name := args[1].text
+		if _, ok := s.lists[name]; ok {
+			return fmt.Errorf("list %q already exists", name)
+		}
+		if isSessionCommand(name) {
+			return fmt.Errorf("%q is a command and cannot name a list", name)
+		}
+		l, err := newList(args[2].text)
+		if err != nil {
+			return err
+		}
+		if err := l.FromSlice(values(args[3:])); err != nil {
+			return err
+		}
+		s.lists[name] = l
+		s.print(name)
+		return nil
+	case "drop":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: drop NAME")
+		}
+		if _, ok := s.lists[args[1].text]; !ok {
+			return fmt.Errorf("no list named %q", args[1].text)
+		}
+		delete(s.lists, args[1].text)
+		return nil
+	case "lists":
+		s.printAll()
+		return nil
+	case "history":
+		for i, line := range s.history {
+			fmt.Fprintf(s.out, "%4d  %s\n", i+1, line)
+		}
+		return nil
+	case "undo":
+		if len(s.undo) == 0 {
+			return fmt.Errorf("nothing to undo")
+		}
+		last := s.undo[len(s.undo)-1]
+		s.undo = s.undo[:len(s.undo)-1]
+		if err := s.restore(last); err != nil {
+			return err
+		}
+		s.printAll()
+		return nil
+	case "save":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: save FILE")
+		}
+		return s.save(args[1].text)
+	case "load":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: load FILE")
+		}
+		if err := s.load(args[1].text); err != nil {
+			return err
+		}
+		s.printAll()
+		return nil
+	}
+
+	name := args[0].text
+	l, ok := s.lists[name]
+	if !ok {
+		return fmt.Errorf("unknown command or list %q (try help)", name)
+	}
+	if len(args) < 2 {
+		s.print(name)
+		return nil
+	}
+	err := s.listCommand(l, args[1].text, args[2:])
+	if err == nil {
+		s.print(name)
+	}
+	return err
+}
+
+// listCommand applies a command to a single list.
+func (s *session) listCommand(l list, command string, args []token) error {
+	want := map[string]int{
+		"append": 1, "prepend": 1, "insert": 2, "delete": 1, "delete-at": 1,
+		"move": 2, "get": 1, "search": 1,
+	}[command]
+	if len(args) != want {
+		return fmt.Errorf("%s takes %d argument(s), got %d", command, want, len(args))
+	}
+	index := func(t token) (int, error) {
+		i, err := strconv.Atoi(t.text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid index %q", t.text)
+		}
+		return i, nil
+	}
+
+	switch command {
+	case "append":
+		return l.Append(args[0].value())
+	case "prepend":
+		return l.Prepend(args[0].value())
+	case "insert":
+		i, err := index(args[1])
+		if err != nil {
+			return err
+		}
+		return l.Insert(args[0].value(), i)
+	case "delete":
+		return l.Delete(args[0].value())
+	case "delete-at":
+		i, err := index(args[0])
+		if err != nil {
+			return err
+		}
+		return l.DeleteAt(i)
+	case "move":
+		from, err := index(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := index(args[1])
+		if err != nil {
+			return err
+		}
+		return l.Move(from, to)
+	case "shift":
+		return l.Shift()
+	case "pop":
+		return l.Pop()
+	case "clear":
+		l.Clear()
+		return nil
+	case "reverse":
+		return l.Reverse()
+	case "sort":
+		return l.Sort()
+	case "unique":
+		return l.Unique()
+	case "get":
+		i, err := index(args[0])
+		if err != nil {
+			return err
+		}
+		v, err := l.Get(i)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%v\n", v)
+		return nil
+	case "search":
+		i, err := l.Search(args[0].value())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%d\n", i)
+		return nil
+	case "middle":
+		v, err := l.GetMiddle()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%v\n", v)
+		return nil
+	case "validate":
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		fmt.Fprintln(s.out, "valid")
+		return nil
+	case "print":
+		return nil
+	}
+	return fmt.Errorf("unknown list command %q (try help)", command)
+}
+
+// print pretty-prints one list.
+func (s *session) print(name string) {
+	l := s.lists[name]
+	sep := " -> "
+	if l.Kind() == "doubly" {
+		sep = " <-> "
+	}
+	parts := make([]string, 0, l.Len())
+	for _, v := range l.IntoSlice() {
+		// Quote strings so "7" and 7 look different
+		if str, ok := v.(string); ok {
+			parts = append(parts, strconv.Quote(str))
+		} else {
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	body := strings.Join(parts, sep)
+	if body == "" {
+		body = "(empty)"
+	}
+	fmt.Fprintf(s.out, "%s (%s, size %d): %s\n", name, l.Kind(), l.Len(), body)
+}
+
+// printAll pretty-prints every list in name order.
+func (s *session) printAll() {
+	if len(s.lists) == 0 {
+		fmt.Fprintln(s.out, "(no lists)")
+		return
+	}
+	for _, name := range s.names() {
+		s.print(name)
+	}
+}
+
+// names returns the list names in sorted order.
+func (s *session) names() []string {
+	names := make([]string, 0, len(s.lists))
+	for name := range s.lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// snapshot captures every list's kind and contents.
+func (s *session) snapshot() []savedList {
+	saved := []savedList{}
+	for _, name := range s.names() {
+		l := s.lists[name]
+		saved = append(saved, savedList{Name: name, Kind: l.Kind(), Values: l.IntoSlice()})
+	}
+	return saved
+}
+
+// restore replaces every list with the saved ones.
+func (s *session) restore(saved []savedList) error {
+	lists := make(map[string]list, len(saved))
+	for _, sl := range saved {
+		l, err := newList(sl.Kind)
+		if err != nil {
+			return err
+		}
+		values := sl.Values
+		if values == nil {
+			values = []any{}
+		}
+		if err := l.FromSlice(values); err != nil {
+			return err
+		}
+		lists[sl.Name] = l
+	}
+	s.lists = lists
+	return nil
+}
+
+// save writes the session to path as JSON.
+func (s *session) save(path string) error {
+	data, err := json.MarshalIndent(savedSession{Lists: s.snapshot(), History: s.history}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// load replaces the session's lists and history with the ones saved in path.
+func (s *session) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	var saved savedSession
+	if err := dec.Decode(&saved); err != nil {
+		return fmt.Errorf("invalid session file: %v", err)
+	}
+	for i := range saved.Lists {
+		for j, v := range saved.Lists[i].Values {
+			saved.Lists[i].Values[j] = fromJSON(v)
+		}
+	}
+	if err := s.restore(saved.Lists); err != nil {
+		return err
+	}
+	// Keep the load itself at the end of the restored history
+	s.history = append(saved.History, s.history[len(s.history)-1])
+	return nil
+}
+
+// isSessionCommand reports whether name is a session command.
+func isSessionCommand(name string) bool {
+	switch name {
+	case "new", "drop", "lists", "history", "undo", "save", "load", "help", "quit", "exit":
+		return true
+	}
+	return false
+}
+
+// token is a word of a command line.
+type token struct {
+	text   string
+	quoted bool // Written in double quotes, so always a string
+}
+
+// value converts the token into a list value.
+func (t token) value() any {
+	if t.quoted {
+		return t.text
+	}
+	return parseValue(t.text)
+}
+
+// values converts tokens into list values.
+func values(tokens []token) []any {
+	vs := make([]any, len(tokens))
+	for i, t := range tokens {
+		vs[i] = t.value()
+	}
+	return vs
+}
+
+// tokenize splits a command line on spaces, honouring Go-style double
+// quoted strings.
+func tokenize(line string) ([]token, error) {
+	var tokens []token
+	for rest := strings.TrimSpace(line); rest != ""; rest = strings.TrimSpace(rest) {
+		if rest[0] != '"' {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, token{text: rest[:end]})
+			rest = rest[end:]
+			continue
+		}
+		prefix, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated string in %q", line)
+		}
+		text, _ := strconv.Unquote(prefix)
+		tokens = append(tokens, token{text: text, quoted: true})
+		rest = rest[len(prefix):]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return tokens, nil
+}
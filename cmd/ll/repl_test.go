@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestREPLScript(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := filepath.Join(dir, "session.json")
+	script := strings.Join([]string{
+		"# build a list",
+		"new a doubly 1 2",
+		"a insert 1.5 2",
+		`a append "x y"`,
+		"a reverse",
+		"undo",
+		"save " + sessionFile,
+		"drop a",
+		"load " + sessionFile,
+		"a delete-at 9",
+		"a validate",
+	}, "\n")
+	scriptFile := filepath.Join(dir, "repro.ll")
+	os.WriteFile(scriptFile, []byte(script), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"repl", "-f", scriptFile}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitIndex {
+		t.Errorf("Expected the script's first error to set exit code %d, got %d", exitIndex, code)
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"> a insert 1.5 2\na (doubly, size 3): 1 <-> 2 <-> 1.5\n",
+		`a (doubly, size 4): "x y" <-> 1.5 <-> 2 <-> 1`,
+		"> undo\na (doubly, size 4): 1 <-> 2 <-> 1.5 <-> \"x y\"\n",
+		"> load " + sessionFile + "\na (doubly, size 4): 1 <-> 2 <-> 1.5 <-> \"x y\"\n",
+		"> a delete-at 9\nerror: index out of bounds\n",
+		"> a validate\nvalid\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize(`a append "two words" 3`)
+	if err != nil {
+		t.Fatalf("tokenize failed: %v", err)
+	}
+	if len(tokens) != 4 || tokens[2].value() != "two words" || tokens[3].value() != 3 {
+		t.Errorf("Unexpected tokens %v", tokens)
+	}
+	if _, err := tokenize(`a append "open`); err == nil {
+		t.Error("Expected error for an unterminated string")
+	}
+}
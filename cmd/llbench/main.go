@@ -0,0 +1,350 @@
+// Command llbench benchmarks the singly and doubly lists against
+// container/list and []T baselines and prints a comparison table.
+//
+// Usage:
+//
+//	llbench [-format md|csv] [-benchtime 200ms] [-run REGEXP] [-sizes 10,100,1000]
+//	        [-baseline FILE] [-threshold 0.1] [-v]
+//
+// Every operation is run for every element type, size and implementation.
+// -run limits that to the cases whose name, Op/type/size/impl, matches.
+// Each row reports ns/op, B/op and allocs/op, and how the time compares to
+// the []T baseline for the same case.
+//
+// Save a CSV report and pass it as -baseline to a later run to compare the
+// two: every case more than -threshold slower, or with more allocations, is
+// marked as a regression and makes llbench exit with status 1.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JustMrNone/ll/internal/bench"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// initTesting registers the testing flags, which testing.Benchmark reads.
+var initTesting sync.Once
+
+// run is main without the process exit, so tests can call it.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("llbench", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "md", "output format: md or csv")
+	benchtime := flags.String("benchtime", "200ms", "run each case for this long, or Nx for N iterations")
+	pattern := flags.String("run", "", "only run cases whose name matches this regexp")
+	sizeList := flags.String("sizes", joinInts(bench.Sizes), "comma-separated list sizes")
+	baselineFile := flags.String("baseline", "", "compare against this earlier CSV report")
+	threshold := flags.Float64("threshold", 0.1, "slowdown beyond which a case counts as a regression")
+	verbose := flags.Bool("v", false, "print each case to standard error as it runs")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "llbench: unexpected arguments %q\n", flags.Args())
+		return 2
+	}
+	if *format != "md" && *format != "csv" {
+		fmt.Fprintf(stderr, "llbench: unknown format %q\n", *format)
+		return 2
+	}
+	match, err := regexp.Compile(*pattern)
+	if err != nil {
+		fmt.Fprintln(stderr, "llbench:", err)
+		return 2
+	}
+	sizes, err := parseSizes(*sizeList)
+	if err != nil {
+		fmt.Fprintln(stderr, "llbench:", err)
+		return 2
+	}
+	initTesting.Do(testing.Init)
+	if err := flag.Set("test.benchtime", *benchtime); err != nil {
+		fmt.Fprintf(stderr, "llbench: invalid -benchtime: %v\n", err)
+		return 2
+	}
+
+	var baseline map[string]result
+	if *baselineFile != "" {
+		if baseline, err = readBaseline(*baselineFile); err != nil {
+			fmt.Fprintln(stderr, "llbench:", err)
+			return 1
+		}
+	}
+
+	var results []result
+	for _, c := range bench.Cases(bench.Ops, bench.Types, sizes, bench.Impls) {
+		if !match.MatchString(c.Name()) {
+			continue
+		}
+		if *verbose {
+			fmt.Fprintln(stderr, c.Name())
+		}
+		r := testing.Benchmark(c.Benchmark)
+		results = append(results, result{
+			op:       c.Op.Name,
+			typ:      c.Type.Name,
+			size:     c.Size,
+			impl:     c.Impl.Name,
+			nsPerOp:  float64(r.T.Nanoseconds()) / float64(r.N),
+			bytes:    r.AllocedBytesPerOp(),
+			allocs:   r.AllocsPerOp(),
+			measured: true,
+		})
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(stderr, "llbench: no cases match -run")
+		return 2
+	}
+
+	rows, regressions := compare(results, baseline, *threshold)
+	if *format == "csv" {
+		err = writeCSV(stdout, rows, baseline != nil)
+	} else {
+		err = writeMarkdown(stdout, rows, baseline != nil)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "llbench:", err)
+		return 1
+	}
+	if regressions > 0 {
+		fmt.Fprintf(stderr, "llbench: %d regressions against %s\n", regressions, *baselineFile)
+		return 1
+	}
+	return 0
+}
+
+// result is the measurement of one case.
+type result struct {
+	op, typ, impl string
+	size          int
+	nsPerOp       float64
+	bytes, allocs int64
+	measured      bool // False for a zero value found missing from a map
+}
+
+// key identifies the case a result belongs to.
+func (r result) key() string {
+	return fmt.Sprintf("%s/%s/%d/%s", r.op, r.typ, r.size, r.impl)
+}
+
+// row is a result with its comparisons.
+type row struct {
+	result
+	vsSlice   float64 // Time relative to the slice baseline; 0 when unknown
+	base      result  // The baseline measurement, if any
+	regressed bool
+}
+
+// compare relates every result to the slice baseline of the same case and to
+// the earlier report, and counts the regressions.
+func compare(results []result, baseline map[string]result, threshold float64) ([]row, int) {
+	byKey := make(map[string]result, len(results))
+	for _, r := range results {
+		byKey[r.key()] = r
+	}
+
+	rows := make([]row, len(results))
+	regressions := 0
+	for i, r := range results {
+		rows[i].result = r
+		slice := r
+		slice.impl = "slice"
+		if s := byKey[slice.key()]; s.measured && s.nsPerOp > 0 {
+			rows[i].vsSlice = r.nsPerOp / s.nsPerOp
+		}
+		if base := baseline[r.key()]; base.measured {
+			rows[i].base = base
+			rows[i].regressed = r.nsPerOp > base.nsPerOp*(1+threshold) || r.allocs > base.allocs
+			if rows[i].regressed {
+				regressions++
+			}
+		}
+	}
+	return rows, regressions
+}
+
+var csvHeader = []string{"operation", "type", "size", "implementation", "ns_per_op", "bytes_per_op", "allocs_per_op", "vs_slice"}
+
+// writeCSV writes one record per case, with the baseline columns when there
+// is a baseline.
+func writeCSV(w io.Writer, rows []row, withBaseline bool) error {
+	out := csv.NewWriter(w)
+	header := csvHeader
+	if withBaseline {
+		header = append(header[:len(header):len(header)], "base_ns_per_op", "base_allocs_per_op", "regressed")
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{
+			r.op, r.typ, strconv.Itoa(r.size), r.impl,
+			strconv.FormatFloat(r.nsPerOp, 'f', 2, 64),
+			strconv.FormatInt(r.bytes, 10),
+			strconv.FormatInt(r.allocs, 10),
+			"",
+		}
+		if r.vsSlice > 0 {
+			record[7] = strconv.FormatFloat(r.vsSlice, 'f', 2, 64)
+		}
+		if withBaseline {
+			if r.base.measured {
+				record = append(record,
+					strconv.FormatFloat(r.base.nsPerOp, 'f', 2, 64),
+					strconv.FormatInt(r.base.allocs, 10),
+					strconv.FormatBool(r.regressed))
+			} else {
+				record = append(record, "", "", "")
+			}
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeMarkdown writes a table per operation, preceded by what each
+// operation's iteration does.
+func writeMarkdown(w io.Writer, rows []row, withBaseline bool) error {
+	var b strings.Builder
+	b.WriteString("# List benchmarks\n")
+	for _, op := range bench.Ops {
+		var opRows []row
+		for _, r := range rows {
+			if r.op == op.Name {
+				opRows = append(opRows, r)
+			}
+		}
+		if len(opRows) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n## %s\n\nOne op: %s.\n\n", op.Name, op.Doc)
+		b.WriteString("| Type | Size | Implementation | ns/op | B/op | allocs/op | vs slice |")
+		if withBaseline {
+			b.WriteString(" baseline ns/op | change |")
+		}
+		b.WriteString("\n|------|-----:|----------------|------:|-----:|----------:|---------:|")
+		if withBaseline {
+			b.WriteString("---------------:|-------:|")
+		}
+		b.WriteString("\n")
+		for _, r := range opRows {
+			vs := ""
+			if r.vsSlice > 0 {
+				vs = fmt.Sprintf("%.2fx", r.vsSlice)
+			}
+			fmt.Fprintf(&b, "| %s | %d | %s | %s | %d | %d | %s |", r.typ, r.size, r.impl, formatNs(r.nsPerOp), r.bytes, r.allocs, vs)
+			if withBaseline {
+				if r.base.measured {
+					change := fmt.Sprintf("%+.1f%%", (r.nsPerOp/r.base.nsPerOp-1)*100)
+					if r.allocs != r.base.allocs {
+						change += fmt.Sprintf(", %+d allocs", r.allocs-r.base.allocs)
+					}
+					if r.regressed {
+						change = "**" + change + "**"
+					}
+					fmt.Fprintf(&b, " %s | %s |", formatNs(r.base.nsPerOp), change)
+				} else {
+					b.WriteString(" | new |")
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatNs prints a time with precision to match its size.
+func formatNs(ns float64) string {
+	if ns < 100 {
+		return strconv.FormatFloat(ns, 'f', 2, 64)
+	}
+	return strconv.FormatFloat(ns, 'f', 0, 64)
+}
+
+// readBaseline reads a CSV report written by an earlier run.
+func readBaseline(name string) (map[string]result, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", name, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("baseline %s is empty", name)
+	}
+	column := map[string]int{}
+	for i, col := range records[0] {
+		column[col] = i
+	}
+	for _, col := range csvHeader[:7] {
+		if _, ok := column[col]; !ok {
+			return nil, fmt.Errorf("baseline %s has no %s column", name, col)
+		}
+	}
+
+	baseline := make(map[string]result, len(records)-1)
+	for i, record := range records[1:] {
+		r := result{
+			op:       record[column["operation"]],
+			typ:      record[column["type"]],
+			impl:     record[column["implementation"]],
+			measured: true,
+		}
+		var errs [4]error
+		r.size, errs[0] = strconv.Atoi(record[column["size"]])
+		r.nsPerOp, errs[1] = strconv.ParseFloat(record[column["ns_per_op"]], 64)
+		r.bytes, errs[2] = strconv.ParseInt(record[column["bytes_per_op"]], 10, 64)
+		r.allocs, errs[3] = strconv.ParseInt(record[column["allocs_per_op"]], 10, 64)
+		for _, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("baseline %s line %d: %w", name, i+2, err)
+			}
+		}
+		baseline[r.key()] = r
+	}
+	return baseline, nil
+}
+
+// parseSizes parses the -sizes flag.
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, field := range strings.Split(s, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid size %q in -sizes: sizes must be positive integers", field)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// joinInts formats sizes the way -sizes takes them.
+func joinInts(sizes []int) string {
+	fields := make([]string, len(sizes))
+	for i, size := range sizes {
+		fields[i] = strconv.Itoa(size)
+	}
+	return strings.Join(fields, ",")
+}
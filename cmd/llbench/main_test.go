@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCSVReport(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-format", "csv", "-benchtime", "10x", "-run", "^Get/int/", "-sizes", "10"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("Expected a header and 9 records, got:\n%s", stdout.String())
+	}
+	if lines[0] != strings.Join(csvHeader, ",") {
+		t.Errorf("Expected header %v, got %s", csvHeader, lines[0])
+	}
+	for i, impl := range []string{"singly", "singly-pool", "singly-arena", "doubly", "doubly-pool", "doubly-arena", "indexed", "stdlist", "slice"} {
+		if !strings.HasPrefix(lines[i+1], "Get,int,10,"+impl+",") {
+			t.Errorf("Expected record %d for %s, got %s", i+1, impl, lines[i+1])
+		}
+	}
+	if !strings.HasSuffix(lines[9], ",1.00") {
+		t.Errorf("Expected the slice baseline to compare as 1.00 to itself, got %s", lines[9])
+	}
+}
+
+func TestMarkdownReport(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-benchtime", "10x", "-run", "^(Append|Pop)/string/10/", "-sizes", "10"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"## Append\n\nOne op: append Size values to an empty list.",
+		"## Pop\n",
+		"| Type | Size | Implementation | ns/op | B/op | allocs/op | vs slice |",
+		"| string | 10 | doubly |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Get") {
+		t.Errorf("Expected only the matching operations, got:\n%s", out)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "base.csv")
+	// The singly list looks impossibly fast in the baseline and the doubly
+	// list impossibly slow, so only the singly row is a regression
+	err := os.WriteFile(baseline, []byte(strings.Join([]string{
+		"operation,type,size,implementation,ns_per_op,bytes_per_op,allocs_per_op",
+		"Walk,int,10,singly,0.01,0,0",
+		"Walk,int,10,doubly,1000000,0,0",
+	}, "\n")+"\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-format", "csv", "-benchtime", "10x", "-run", "^Walk/int/10/(singly|doubly)$", "-sizes", "10", "-baseline", baseline}
+	if code := run(args, &stdout, &stderr); code != 1 {
+		t.Fatalf("Expected exit code 1 for a regression, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "1 regressions") {
+		t.Errorf("Expected one regression, got %q", stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], ",regressed") {
+		t.Fatalf("Expected a header with baseline columns and 2 records, got:\n%s", stdout.String())
+	}
+	if !strings.HasSuffix(lines[1], ",true") || !strings.HasSuffix(lines[2], ",false") {
+		t.Errorf("Expected singly to regress and doubly not to, got:\n%s", stdout.String())
+	}
+}
+
+func TestBadFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-format", "xml"},
+		{"-sizes", "10,0"},
+		{"-run", "("},
+		{"-benchtime", "soon"},
+		{"-run", "NoSuchOp"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2 for %v, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+// Command llfix moves code from the v1 singly and doubly packages to the v2
+// API, where list fields are unexported and nodes are read-only Element
+// handles.
+//
+// Usage:
+//
+//	llfix [-diff] [path ...]
+//
+// Each path is a package directory, a Go file, or a directory followed by
+// "/..." to include everything below it. Files are rewritten in place unless
+// -diff is given. llfix changes import paths to github.com/JustMrNone/ll/v2,
+// turns reads of Head, Tail, Size, Value, Next and Prev into method calls and
+// renames Node to Element. Code it cannot move automatically, such as
+// assignments to those fields, is reported as file:line:col: message and
+// makes llfix exit with status 1.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JustMrNone/ll/diff"
+	"github.com/JustMrNone/ll/doubly"
+)
+
+const (
+	v1Path = "github.com/JustMrNone/ll/"
+	v2Path = "github.com/JustMrNone/ll/v2/"
+)
+
+// fields maps the exported fields of the v1 types to their v2 accessors.
+var fields = map[string]map[string]string{
+	"LinkedList": {"Head": "Front()", "Tail": "Back()", "Size": "Len()"},
+	"Node":       {"Value": "Value()", "Next": "Next()", "Prev": "Prev()"},
+}
+
+// removedFields lists v1 fields that have no v2 counterpart.
+var removedFields = map[string]string{
+	"Allocator": "Allocator has no v2 equivalent: v2 lists allocate every element",
+}
+
+// changedMethods lists v1 methods whose callers need manual attention.
+var changedMethods = map[string]string{
+	"InsertAfter":     "InsertAfter now takes an *Element and also returns an error",
+	"Remove":          "Remove now takes an *Element and rejects elements of other lists",
+	"DetectCycle":     "DetectCycle has no v2 equivalent: v2 lists cannot be corrupted from outside the package",
+	"DetectPrevCycle": "DetectPrevCycle has no v2 equivalent: v2 lists cannot be corrupted from outside the package",
+	"BreakCycle":      "BreakCycle has no v2 equivalent: v2 lists cannot be corrupted from outside the package",
+	"BreakPrevCycle":  "BreakPrevCycle has no v2 equivalent: v2 lists cannot be corrupted from outside the package",
+	"Repair":          "Repair has no v2 equivalent: v2 lists cannot be corrupted from outside the package",
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is main without the process exit, so tests can call it.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("llfix", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	showDiff := flags.Bool("diff", false, "print a diff instead of rewriting files")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	dirs, err := expand(paths)
+	if err != nil {
+		fmt.Fprintln(stderr, "llfix:", err)
+		return 1
+	}
+
+	status := 0
+	for _, dir := range dirs {
+		results, err := fixDir(dir.path, dir.files)
+		if err != nil {
+			fmt.Fprintln(stderr, "llfix:", err)
+			status = 1
+			continue
+		}
+		for _, r := range results {
+			for _, w := range r.warnings {
+				fmt.Fprintln(stderr, w)
+				status = 1
+			}
+			if bytes.Equal(r.src, r.fixed) {
+				continue
+			}
+			if *showDiff {
+				fmt.Fprint(stdout, unified(r.name, r.src, r.fixed))
+				continue
+			}
+			if err := os.WriteFile(r.name, r.fixed, 0o644); err != nil {
+				fmt.Fprintln(stderr, "llfix:", err)
+				status = 1
+			}
+		}
+	}
+	return status
+}
+
+// dir is a package directory to fix, limited to files when it is not nil.
+type dir struct {
+	path  string
+	files map[string]bool
+}
+
+// expand turns the command-line paths into package directories.
+func expand(paths []string) ([]dir, error) {
+	var dirs []dir
+	for _, path := range paths {
+		if root, ok := strings.CutSuffix(path, "/..."); ok {
+			err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() {
+					name := d.Name()
+					if p != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+						return filepath.SkipDir
+					}
+					dirs = append(dirs, dir{path: p})
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			dirs = append(dirs, dir{path: path})
+		} else {
+			dirs = append(dirs, dir{path: filepath.Dir(path), files: map[string]bool{path: true}})
+		}
+	}
+	return dirs, nil
+}
+
+// result is the outcome of fixing one file.
+type result struct {
+	name       string
+	src, fixed []byte
+	warnings   []string
+}
+
+// fixDir type-checks the Go files in dir, one package at a time, and
+// rewrites the ones that use the v1 packages. When only is not nil, files
+// outside it are type-checked but not rewritten.
+func fixDir(path string, only map[string]bool) ([]result, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []result
+	for _, name := range names {
+		var files []*ast.File
+		for _, file := range pkgs[name].Files {
+			files = append(files, file)
+		}
+		sort.Slice(files, func(i, j int) bool {
+			return fset.File(files[i].Pos()).Name() < fset.File(files[j].Pos()).Name()
+		})
+
+		// Type errors are expected while code is half migrated; whatever
+		// information the checker recovers is enough to find v1 uses.
+		info := &types.Info{
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+			Types:      map[ast.Expr]types.TypeAndValue{},
+		}
+		conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil), Error: func(error) {}}
+		conf.Check(path, fset, files, info)
+
+		for _, file := range files {
+			filename := fset.File(file.Pos()).Name()
+			if only != nil && !only[filename] {
+				continue
+			}
+			if !importsV1(file) {
+				continue
+			}
+			r, err := fixFile(fset, file, info)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// importsV1 reports whether file imports the v1 singly or doubly package.
+func importsV1(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		if _, ok := v1Package(spec); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// v1Package returns the v2 import path for an import of a v1 list package.
+func v1Package(spec *ast.ImportSpec) (string, bool) {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return "", false
+	}
+	switch path {
+	case v1Path + "singly", v1Path + "doubly":
+		return v2Path + strings.TrimPrefix(path, v1Path), true
+	}
+	return "", false
+}
+
+// edit replaces the source between two offsets.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// fixFile rewrites a single file and collects warnings for the code it
+// leaves alone.
+func fixFile(fset *token.FileSet, file *ast.File, info *types.Info) (result, error) {
+	tokFile := fset.File(file.Pos())
+	r := result{name: tokFile.Name()}
+	src, err := os.ReadFile(r.name)
+	if err != nil {
+		return r, err
+	}
+	r.src = src
+
+	var edits []edit
+	replace := func(node ast.Node, text string) {
+		edits = append(edits, edit{tokFile.Offset(node.Pos()), tokFile.Offset(node.End()), text})
+	}
+	warn := func(node ast.Node, format string, args ...any) {
+		r.warnings = append(r.warnings, fmt.Sprintf("%s: %s", fset.Position(node.Pos()), fmt.Sprintf(format, args...)))
+	}
+
+	for _, spec := range file.Imports {
+		if path, ok := v1Package(spec); ok {
+			replace(spec.Path, strconv.Quote(path))
+		}
+	}
+
+	assigned := assignedSelectors(file)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if sel, ok := info.Selections[n]; ok {
+				owner, ok := v1Type(sel.Recv())
+				if !ok {
+					return true
+				}
+				name := sel.Obj().Name()
+				switch sel.Kind() {
+				case types.FieldVal:
+					if msg, ok := removedFields[name]; ok && owner == "LinkedList" {
+						warn(n, "%s", msg)
+						return true
+					}
+					accessor, ok := fields[owner][name]
+					if !ok {
+						return true
+					}
+					if assigned[n] {
+						warn(n, "cannot rewrite assignment to %s.%s; v2 lists only change through their methods", owner, name)
+					} else {
+						replace(n.Sel, accessor)
+					}
+				case types.MethodVal:
+					if msg, ok := changedMethods[name]; ok {
+						warn(n, "%s", msg)
+					}
+				}
+				return true
+			}
+			if obj, ok := info.Uses[n.Sel].(*types.TypeName); ok && isV1(obj.Pkg()) && obj.Name() == "Node" {
+				replace(n.Sel, "Element")
+			}
+		case *ast.CompositeLit:
+			if tv, ok := info.Types[n]; ok {
+				if owner, ok := v1Type(tv.Type); ok {
+					warn(n, "cannot rewrite %s literal; create lists with the package constructor and elements through list methods", owner)
+				}
+			}
+		}
+		return true
+	})
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	fixed := append([]byte(nil), src...)
+	for _, e := range edits {
+		fixed = append(fixed[:e.start], append([]byte(e.text), fixed[e.end:]...)...)
+	}
+	if r.fixed, err = format.Source(fixed); err != nil {
+		return r, fmt.Errorf("%s: formatting rewritten source: %w", r.name, err)
+	}
+	return r, nil
+}
+
+// assignedSelectors returns the selector expressions in file that are
+// assigned to, incremented or have their address taken.
+func assignedSelectors(file *ast.File) map[*ast.SelectorExpr]bool {
+	assigned := map[*ast.SelectorExpr]bool{}
+	mark := func(expr ast.Expr) {
+		if sel, ok := ast.Unparen(expr).(*ast.SelectorExpr); ok {
+			assigned[sel] = true
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				mark(lhs)
+			}
+		case *ast.IncDecStmt:
+			mark(n.X)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				mark(n.X)
+			}
+		}
+		return true
+	})
+	return assigned
+}
+
+// v1Type returns the name of a v1 LinkedList or Node type, looking through
+// pointers.
+func v1Type(t types.Type) (string, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || !isV1(named.Obj().Pkg()) {
+		return "", false
+	}
+	name := named.Obj().Name()
+	_, ok = fields[name]
+	return name, ok
+}
+
+// isV1 reports whether pkg is the v1 singly or doubly package.
+func isV1(pkg *types.Package) bool {
+	return pkg != nil && (pkg.Path() == v1Path+"singly" || pkg.Path() == v1Path+"doubly")
+}
+
+// unified renders the change to one file as a unified diff.
+func unified(name string, before, after []byte) string {
+	lines := func(b []byte) *doubly.LinkedList {
+		list := doubly.NewDoublyLinkedList()
+		for _, line := range strings.SplitAfter(string(b), "\n") {
+			if line != "" {
+				list.Append(strings.TrimSuffix(line, "\n"))
+			}
+		}
+		return list
+	}
+	patch := diff.Diff(lines(before), lines(after), nil)
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", name, name, diff.Unified(patch, 3))
+}
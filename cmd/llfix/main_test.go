@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	// The copy must stay inside the module so its imports resolve
+	dir, err := os.MkdirTemp("testdata", "fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src, err := os.ReadFile(filepath.Join("testdata", "caller", "caller.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "caller.go")
+	if err := os.WriteFile(file, src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{dir}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for code needing manual changes, got %d", code)
+	}
+
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "caller.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Rewritten source differs from caller.golden:\n%s", got)
+	}
+
+	warnings := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	for i, want := range []string{
+		"caller.go:34:2: cannot rewrite assignment to LinkedList.Size",
+		"caller.go:35:2: cannot rewrite assignment to LinkedList.Head",
+		"caller.go:35:15: cannot rewrite Node literal",
+		"caller.go:36:2: Repair has no v2 equivalent",
+		"caller.go:41:2: Allocator has no v2 equivalent",
+	} {
+		if i >= len(warnings) || !strings.Contains(warnings[i], want) {
+			t.Errorf("Expected warning %d to contain %q, got:\n%s", i, want, stderr.String())
+		}
+	}
+
+	// A second run finds nothing left to rewrite
+	stdout.Reset()
+	run([]string{"-diff", dir}, &stdout, &stderr)
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no further changes, got:\n%s", stdout.String())
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run([]string{"-diff", "./testdata/caller"}, &stdout, &stderr)
+	for _, want := range []string{
+		`+	"github.com/JustMrNone/ll/v2/doubly"`,
+		"+	for node := list.Front(); node != nil; node = node.Next() {",
+		"+	var node *doubly.Element = list.Back()",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package caller uses the v1 list API; llfix tests rewrite it.
+package caller
+
+import (
+	"fmt"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// Sum walks a doubly linked list through its nodes.
+func Sum(list *doubly.LinkedList) int {
+	total := 0
+	for node := list.Head; node != nil; node = node.Next {
+		total += node.Value.(int)
+	}
+	return total
+}
+
+// Last returns the value before the tail.
+func Last(list *doubly.LinkedList) any {
+	var node *doubly.Node = list.Tail
+	return node.Prev.Value
+}
+
+// Describe prints the size of a singly linked list.
+func Describe(list *singly.LinkedList) {
+	fmt.Println(list.Size, list.Head.Next.Value)
+}
+
+// Corrupt changes the list structure directly.
+func Corrupt(list *singly.LinkedList) {
+	list.Size++
+	list.Head = &singly.Node{Value: 1}
+	list.Repair()
+}
+
+// Unpooled drops the list's node allocator.
+func Unpooled(list *doubly.LinkedList) {
+	list.Allocator = nil
+}
+
+// Watch prints every change to a doubly linked list.
+func Watch(list *doubly.LinkedList) (unsubscribe func()) {
+	return list.Subscribe(func(e event.Event) { fmt.Println(e) })
+}
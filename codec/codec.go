@@ -0,0 +1,28 @@
+// Package codec converts list values to and from bytes for on-disk storage.
+package codec
+
+import "encoding/json"
+
+// Codec encodes and decodes single list values.
+type Codec interface {
+	Encode(value any) ([]byte, error)
+	Decode(data []byte) (any, error)
+}
+
+// JSON encodes values with encoding/json. Like any JSON round trip, numbers
+// decode as float64 and structs decode as map[string]any.
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Decode(data []byte) (any, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,295 @@
+// Package crdt implements a Replicated Growable Array (RGA), a sequence CRDT
+// that lets several replicas edit the same ordered list independently and
+// converge once they have exchanged operations.
+package crdt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JustMrNone/ll/doubly"
+)
+
+var (
+	// ErrCollected is returned by Apply for an insert anchored to an element
+	// that GC has already dropped. The op can never be applied here; GC ran
+	// before every replica had seen the element's delete.
+	ErrCollected = errors.New("anchor was garbage collected")
+
+	// ErrPendingFull is returned by Apply when an op has to wait for its
+	// dependencies but MaxPending ops are already waiting. The op is dropped
+	// and may be delivered again later.
+	ErrPendingFull = errors.New("too many pending ops")
+)
+
+// DefaultMaxPending is the MaxPending of a new replica.
+const DefaultMaxPending = 10000
+
+// ID identifies an element by its Lamport timestamp and the replica that
+// created it. The zero ID stands for the start of the sequence.
+type ID struct {
+	Counter uint64 `json:"counter"` // Lamport timestamp
+	Replica string `json:"replica"` // Replica that created the element
+}
+
+// IsZero reports whether id is the start-of-sequence ID.
+func (id ID) IsZero() bool {
+	return id == ID{}
+}
+
+// Less orders IDs by timestamp, breaking ties by replica name.
+func (id ID) Less(other ID) bool {
+	if id.Counter != other.Counter {
+		return id.Counter < other.Counter
+	}
+	return id.Replica < other.Replica
+}
+
+// String formats the ID as counter@replica.
+func (id ID) String() string {
+	return fmt.Sprintf("%d@%s", id.Counter, id.Replica)
+}
+
+// OpKind identifies what an Op does.
+type OpKind string
+
+const (
+	OpInsert OpKind = "insert" // Insert Value after the element After
+	OpDelete OpKind = "delete" // Tombstone the element ID
+)
+
+// Op is an operation exchanged between replicas. It encodes to JSON with
+// encoding/json; numeric values come back as float64 on the receiving side.
+type Op struct {
+	Kind  OpKind `json:"kind"`
+	ID    ID     `json:"id"`              // Element inserted or deleted
+	After ID     `json:"after"`           // Anchor of an insert, zero for the start
+	Value any    `json:"value,omitempty"` // Value of an insert
+}
+
+// element is what the underlying list stores for every insert, including
+// deleted ones, which stay behind as tombstones.
+type element struct {
+	id      ID
+	value   any
+	deleted bool
+}
+
+// RGA is one replica of a replicated sequence. It is not safe for concurrent
+// use.
+type RGA struct {
+	// MaxPending caps the number of remote ops held back waiting for their
+	// dependencies. Zero means no limit.
+	MaxPending int
+
+	replica   string
+	clock     uint64
+	list      *doubly.LinkedList  // Elements in document order, tombstones included
+	nodes     map[ID]*doubly.Node // Index of every element by ID
+	collected map[ID]struct{}     // Elements dropped by GC
+	pending   []Op                // Remote ops waiting for their dependencies
+	visible   int                 // Number of elements that are not deleted
+}
+
+// NewRGA creates an empty replica with the given unique name.
+func NewRGA(replica string) *RGA {
+	return &RGA{
+		MaxPending: DefaultMaxPending,
+		replica:    replica,
+		list:       doubly.NewDoublyLinkedList(),
+		nodes:      make(map[ID]*doubly.Node),
+		collected:  make(map[ID]struct{}),
+	}
+}
+
+// Replica returns the name of the replica.
+func (r *RGA) Replica() string {
+	return r.replica
+}
+
+// InsertAfter inserts value after the element with the given ID, or at the
+// start when after is the zero ID, and returns the op to send to the other
+// replicas.
+func (r *RGA) InsertAfter(after ID, value any) (Op, error) {
+	if !after.IsZero() {
+		if _, ok := r.nodes[after]; !ok {
+			return Op{}, fmt.Errorf("unknown element %v", after)
+		}
+	}
+	r.clock++
+	op := Op{Kind: OpInsert, ID: ID{Counter: r.clock, Replica: r.replica}, After: after, Value: value}
+	r.integrate(op)
+	return op, nil
+}
+
+// Delete removes the element with the given ID and returns the op to send to
+// the other replicas.
+func (r *RGA) Delete(id ID) (Op, error) {
+	node, ok := r.nodes[id]
+	if !ok {
+		return Op{}, fmt.Errorf("unknown element %v", id)
+	}
+	if node.Value.(*element).deleted {
+		return Op{}, fmt.Errorf("element %v is already deleted", id)
+	}
+	op := Op{Kind: OpDelete, ID: id}
+	r.tombstone(node)
+	return op, nil
+}
+
+// Apply merges an op received from another replica. Ops may arrive in any
+// order and more than once; an op whose dependencies have not arrived yet is
+// held back and applied as soon as they do. Ops for elements GC has dropped
+// are ignored, and an insert anchored to one returns ErrCollected.
+func (r *RGA) Apply(op Op) error {
+	switch op.Kind {
+	case OpInsert, OpDelete:
+	default:
+		return fmt.Errorf("unknown op kind %q", op.Kind)
+	}
+	if op.ID.IsZero() {
+		return fmt.Errorf("op has no element ID")
+	}
+
+	if op.Kind == OpInsert {
+		if _, ok := r.collected[op.After]; ok {
+			return fmt.Errorf("%w: insert %v after %v", ErrCollected, op.ID, op.After)
+		}
+	}
+
+	if !r.tryApply(op) {
+		if r.MaxPending > 0 && len(r.pending) >= r.MaxPending {
+			return fmt.Errorf("%w: %d ops are waiting", ErrPendingFull, len(r.pending))
+		}
+		r.pending = append(r.pending, op)
+		return nil
+	}
+
+	// Every applied op may unblock some of the held-back ones
+	for progress := true; progress; {
+		progress = false
+		remaining := r.pending[:0]
+		for _, p := range r.pending {
+			if r.tryApply(p) {
+				progress = true
+			} else {
+				remaining = append(remaining, p)
+			}
+		}
+		r.pending = remaining
+	}
+	return nil
+}
+
+// Pending returns the number of remote ops still waiting for dependencies.
+func (r *RGA) Pending() int {
+	return len(r.pending)
+}
+
+// Len returns the number of visible elements.
+func (r *RGA) Len() int {
+	return r.visible
+}
+
+// Values returns the visible values in order.
+func (r *RGA) Values() []any {
+	values := make([]any, 0, r.visible)
+	for current := r.list.Head; current != nil; current = current.Next {
+		if e := current.Value.(*element); !e.deleted {
+			values = append(values, e.value)
+		}
+	}
+	return values
+}
+
+// IDs returns the IDs of the visible elements in order, for use as anchors
+// and delete targets.
+func (r *RGA) IDs() []ID {
+	ids := make([]ID, 0, r.visible)
+	for current := r.list.Head; current != nil; current = current.Next {
+		if e := current.Value.(*element); !e.deleted {
+			ids = append(ids, e.id)
+		}
+	}
+	return ids
+}
+
+// GC drops the tombstones for which stable returns true and reports how many
+// were removed. A tombstone may only be collected once every replica has seen
+// its delete and no op concurrent with it can still arrive; otherwise
+// replicas may diverge. The replica remembers the IDs it dropped, so a
+// redelivered insert or delete of one is ignored rather than reapplied.
+func (r *RGA) GC(stable func(id ID) bool) int {
+	removed := 0
+	current := r.list.Head
+	for current != nil {
+		next := current.Next
+		if e := current.Value.(*element); e.deleted && stable(e.id) {
+			r.list.Remove(current)
+			delete(r.nodes, e.id)
+			r.collected[e.id] = struct{}{}
+			removed++
+		}
+		current = next
+	}
+	return removed
+}
+
+// tryApply applies op if its dependencies are present and reports whether it
+// could. Ops that were already applied count as applied.
+func (r *RGA) tryApply(op Op) bool {
+	if op.ID.Counter > r.clock {
+		r.clock = op.ID.Counter
+	}
+	if _, ok := r.collected[op.ID]; ok {
+		return true
+	}
+	switch op.Kind {
+	case OpInsert:
+		if _, ok := r.nodes[op.ID]; ok {
+			return true
+		}
+		if !op.After.IsZero() {
+			if _, ok := r.nodes[op.After]; !ok {
+				return false
+			}
+		}
+		r.integrate(op)
+	case OpDelete:
+		node, ok := r.nodes[op.ID]
+		if !ok {
+			return false
+		}
+		if !node.Value.(*element).deleted {
+			r.tombstone(node)
+		}
+	}
+	return true
+}
+
+// integrate places an insert. Concurrent inserts after the same anchor are
+// ordered by descending ID, so the new element skips past every following
+// element with a greater ID; those elements' descendants always carry greater
+// IDs still, so they are skipped along with them.
+func (r *RGA) integrate(op Op) {
+	var prev *doubly.Node
+	if !op.After.IsZero() {
+		prev = r.nodes[op.After]
+	}
+	next := r.list.Head
+	if prev != nil {
+		next = prev.Next
+	}
+	for next != nil && op.ID.Less(next.Value.(*element).id) {
+		prev = next
+		next = next.Next
+	}
+	r.nodes[op.ID] = r.list.InsertAfter(prev, &element{id: op.ID, value: op.Value})
+	r.visible++
+}
+
+// tombstone marks the element stored in node as deleted.
+func (r *RGA) tombstone(node *doubly.Node) {
+	node.Value.(*element).deleted = true
+	r.visible--
+}
@@ -0,0 +1,227 @@
+// Package diff computes, renders and replays minimal edit scripts between
+// doubly linked lists.
+package diff
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// ErrMismatch is returned by Apply when a patch was not computed against the
+// list it is applied to. It may be wrapped with more detail, so check for it
+// with errors.Is.
+var ErrMismatch = errors.New("patch does not match list")
+
+// Kind identifies what an Op does.
+type Kind int
+
+const (
+	Keep   Kind = iota // Element is present in both lists
+	Insert             // Element is only in the target list
+	Delete             // Element is only in the original list
+)
+
+// String returns the lower-case name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Keep:
+		return "keep"
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// MarshalText encodes the kind by name.
+func (k Kind) MarshalText() ([]byte, error) {
+	switch k {
+	case Keep, Insert, Delete:
+		return []byte(k.String()), nil
+	}
+	return nil, fmt.Errorf("unknown op kind %d", int(k))
+}
+
+// UnmarshalText decodes a kind from its name.
+func (k *Kind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "keep":
+		*k = Keep
+	case "insert":
+		*k = Insert
+	case "delete":
+		*k = Delete
+	default:
+		return fmt.Errorf("unknown op kind %q", text)
+	}
+	return nil
+}
+
+// Op is a single step of an edit script.
+type Op struct {
+	Kind     Kind `json:"kind"`
+	OldIndex int  `json:"old"`   // Index in the original list, -1 for inserts
+	NewIndex int  `json:"new"`   // Index in the target list, -1 for deletes
+	Value    any  `json:"value"` // Element kept, inserted or deleted
+}
+
+// Patch is an edit script that turns one list into another. It encodes to
+// JSON as an array of ops; note that encoding/json decodes numeric values
+// as float64.
+type Patch []Op
+
+// Diff returns the shortest edit script that turns a into b, computed with
+// Myers' O(ND) algorithm. Elements are compared with eq, or with == when eq
+// is nil; values that can't be compared with ==, such as slices, then never
+// match. A nil list counts as empty.
+func Diff(a, b *doubly.LinkedList, eq func(x, y any) bool) Patch {
+	if eq == nil {
+		eq = equality.Equal
+	}
+	return myers(values(a), values(b), eq)
+}
+
+// Apply replays the edit script on list in place, checking the values it
+// keeps and deletes with ==. It is ApplyFunc with a nil eq.
+func Apply(list *doubly.LinkedList, patch Patch) error {
+	return ApplyFunc(list, patch, nil)
+}
+
+// ApplyFunc replays the edit script on list in place. Every kept or deleted
+// value in the patch must match the list's value at that position under eq,
+// or == when eq is nil, and together they must cover the list exactly;
+// otherwise it returns ErrMismatch. The patch is checked before any change is
+// made. Pass an eq that tolerates the difference for a patch decoded from
+// JSON, whose numbers are float64.
+func ApplyFunc(list *doubly.LinkedList, patch Patch, eq func(x, y any) bool) error {
+	if list == nil {
+		return fmt.Errorf("cannot apply patch to nil list")
+	}
+	if eq == nil {
+		eq = equality.Equal
+	}
+
+	// Keeps and deletes consume the original list in order
+	current := list.Head
+	consumed := 0
+	for i, op := range patch {
+		switch op.Kind {
+		case Keep, Delete:
+			if current == nil {
+				return fmt.Errorf("%w: op %d goes past the end of a list of length %d", ErrMismatch, i, list.Size)
+			}
+			if !eq(op.Value, current.Value) {
+				return fmt.Errorf("%w: op %d expects %v at index %d, got %v", ErrMismatch, i, op.Value, consumed, current.Value)
+			}
+			current = current.Next
+			consumed++
+		case Insert:
+		default:
+			return fmt.Errorf("unknown op kind %d", int(op.Kind))
+		}
+	}
+	if consumed != list.Size {
+		return fmt.Errorf("%w: patch expects a list of length %d, got %d", ErrMismatch, consumed, list.Size)
+	}
+
+	pos := 0
+	for _, op := range patch {
+		switch op.Kind {
+		case Keep:
+			pos++
+		case Delete:
+			if err := list.DeleteAt(pos); err != nil {
+				return fmt.Errorf("delete at %d failed: %w", pos, err)
+			}
+		case Insert:
+			if err := list.Insert(op.Value, pos); err != nil {
+				return fmt.Errorf("insert at %d failed: %w", pos, err)
+			}
+			pos++
+		}
+	}
+	return nil
+}
+
+// values returns the values of list, or nil for a nil list.
+func values(list *doubly.LinkedList) []any {
+	if list == nil {
+		return nil
+	}
+	return list.IntoSlice()
+}
+
+// myers computes the edit script between a and b.
+func myers(a, b []any, eq func(x, y any) bool) Patch {
+	n, m := len(a), len(b)
+	bound := n + m
+	offset := bound + 1
+	v := make([]int, 2*bound+3)
+
+	// trace[d] holds the furthest reaching x before round d for diagonals
+	// -d-1 through d+1, the only ones the backtrack reads for that round, so
+	// the trace takes O(D²) space rather than O(D·(N+M)).
+	var trace [][]int
+	at := func(d, k int) int { return trace[d][k+d+1] }
+search:
+	for d := 0; d <= bound; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && eq(a[x], b[y]) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk back from the end to recover the path
+	var reversed Patch
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(d, k-1) < at(d, k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(d, prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Op{Kind: Keep, OldIndex: x, NewIndex: y, Value: a[x]})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			reversed = append(reversed, Op{Kind: Insert, OldIndex: -1, NewIndex: y - 1, Value: b[y-1]})
+		} else {
+			reversed = append(reversed, Op{Kind: Delete, OldIndex: x - 1, NewIndex: -1, Value: a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+
+	patch := make(Patch, len(reversed))
+	for i, op := range reversed {
+		patch[len(reversed)-1-i] = op
+	}
+	return patch
+}
@@ -0,0 +1,71 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unified renders the patch in the style of a unified diff, one element per
+// line, with the given number of unchanged elements around each change.
+// Values are formatted with %v.
+func Unified(patch Patch, context int) string {
+	if context < 0 {
+		context = 0
+	}
+
+	// Position in each list before every op, used for hunk headers
+	oldPos := make([]int, len(patch)+1)
+	newPos := make([]int, len(patch)+1)
+	var changes []int
+	for i, op := range patch {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if op.Kind != Insert {
+			oldPos[i+1]++
+		}
+		if op.Kind != Delete {
+			newPos[i+1]++
+		}
+		if op.Kind != Keep {
+			changes = append(changes, i)
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(changes); {
+		// Grow the hunk while the next change is close enough that the
+		// context around both would overlap.
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*context+1 {
+			j++
+		}
+		start := max(changes[i]-context, 0)
+		end := min(changes[j]+context+1, len(patch))
+
+		oldLen := oldPos[end] - oldPos[start]
+		newLen := newPos[end] - newPos[start]
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n",
+			hunkStart(oldPos[start], oldLen), oldLen, hunkStart(newPos[start], newLen), newLen)
+		for _, op := range patch[start:end] {
+			switch op.Kind {
+			case Keep:
+				sb.WriteByte(' ')
+			case Insert:
+				sb.WriteByte('+')
+			case Delete:
+				sb.WriteByte('-')
+			}
+			fmt.Fprintf(&sb, "%v\n", op.Value)
+		}
+		i = j + 1
+	}
+	return sb.String()
+}
+
+// hunkStart converts a zero-based position into the one-based line number
+// used in hunk headers. Empty ranges name the line before them.
+func hunkStart(pos, length int) int {
+	if length == 0 {
+		return pos
+	}
+	return pos + 1
+}
@@ -0,0 +1,108 @@
+package doubly
+
+// Cycle describes a loop in the Next or Prev chain of a corrupted list.
+type Cycle struct {
+	Start      int // Number of steps from the starting end to the first node on the cycle
+	Length     int // Number of nodes on the cycle
+	TailLength int // Number of nodes before the cycle; always equal to Start
+}
+
+// next and prev step along the two chains.
+func next(n *Node) *Node { return n.Next }
+func prev(n *Node) *Node { return n.Prev }
+
+// DetectCycle reports whether following Next from Head loops back on itself
+// and, if it does, where the cycle starts and how long it is. It uses Brent's
+// algorithm, so it runs in linear time and constant space.
+func (ll *LinkedList) DetectCycle() (Cycle, bool) {
+	return detect(ll.Head, next)
+}
+
+// DetectPrevCycle is like DetectCycle for the Prev chain: it follows Prev
+// from Tail, and Start counts steps back from Tail.
+func (ll *LinkedList) DetectPrevCycle() (Cycle, bool) {
+	return detect(ll.Tail, prev)
+}
+
+// BreakCycle cuts the back-edge that closes a cycle in the Next chain, points
+// Tail at the new last node and recomputes Size. Prev links are left as they
+// are; Repair rebuilds them. It reports whether there was a cycle to break.
+func (ll *LinkedList) BreakCycle() bool {
+	start, length, entry := findCycle(ll.Head, next)
+	if length == 0 {
+		return false
+	}
+	last := entry
+	for i := 1; i < length; i++ {
+		last = last.Next
+	}
+	last.Next = nil
+	ll.Tail = last
+	ll.Size = start + length
+	ll.finger = finger{}
+	return true
+}
+
+// BreakPrevCycle cuts the back-edge that closes a cycle in the Prev chain. It
+// reports whether there was a cycle to break.
+func (ll *LinkedList) BreakPrevCycle() bool {
+	_, length, entry := findCycle(ll.Tail, prev)
+	if length == 0 {
+		return false
+	}
+	first := entry
+	for i := 1; i < length; i++ {
+		first = first.Prev
+	}
+	first.Prev = nil
+	ll.finger = finger{}
+	return true
+}
+
+// detect wraps findCycle in a Cycle.
+func detect(from *Node, step func(*Node) *Node) (Cycle, bool) {
+	start, length, _ := findCycle(from, step)
+	if length == 0 {
+		return Cycle{}, false
+	}
+	return Cycle{Start: start, Length: length, TailLength: start}, true
+}
+
+// findCycle runs Brent's algorithm on the chain that step follows from the
+// given node. It returns the index of the cycle's first node, the cycle
+// length and the first node itself, or a zero length if the chain ends.
+func findCycle(from *Node, step func(*Node) *Node) (int, int, *Node) {
+	if from == nil {
+		return 0, 0, nil
+	}
+
+	// Find the cycle length: the hare runs ahead in powers of two while the
+	// tortoise waits at the start of each run.
+	power, length := 1, 1
+	tortoise, hare := from, step(from)
+	for hare != nil && tortoise != hare {
+		if power == length {
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+		hare = step(hare)
+		length++
+	}
+	if hare == nil {
+		return 0, 0, nil
+	}
+
+	// With the hare a cycle length ahead, both meet at the cycle's start
+	tortoise, hare = from, from
+	for i := 0; i < length; i++ {
+		hare = step(hare)
+	}
+	start := 0
+	for tortoise != hare {
+		tortoise = step(tortoise)
+		hare = step(hare)
+		start++
+	}
+	return start, length, tortoise
+}
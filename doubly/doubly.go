@@ -1,7 +1,31 @@
 // Package doubly implements a doubly linked list data structure.
+//
+// Contains keeps its v1 signature, which has no error, so unlike Search it
+// can't report ErrNotComparable. It treats a value that == can't compare,
+// such as a slice, as not found.
 package doubly
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/JustMrNone/ll/alloc"
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// Errors returned by list operations. They may be wrapped with more
+// detail, so check for them with errors.Is.
+var (
+	ErrEmpty            = errors.New("list is empty")
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrNotFound         = errors.New("value not found")
+	ErrMismatchedTypes  = errors.New("mismatched types in list")
+	ErrUnsupportedType  = errors.New("unsupported type for sorting")
+	ErrCorrupt          = errors.New("corrupt list")
+	ErrNotComparable    = errors.New("value is not comparable")
+)
 
 // Node represents a node in the doubly linked list.
 type Node struct {
@@ -15,6 +39,27 @@ type LinkedList struct {
 	Head *Node // First node in the list
 	Tail *Node // Last node in the list
 	Size int   // Number of nodes in the list
+
+	// Allocator supplies new nodes and takes back removed ones. When it is
+	// nil, nodes are allocated with new and left to the garbage collector.
+	// Either way, a node removed by the list is zeroed, so it must not be
+	// used afterwards. Remove is the exception: it leaves the node to the
+	// caller, who passed it in.
+	Allocator alloc.Allocator[Node]
+
+	observers *event.Hub // Subscribers to change events, created on demand
+	finger    finger     // Last node reached by index
+}
+
+// finger remembers the node last reached by index, so that index operations
+// near it can walk from there instead of from Head or Tail. It is stale, and
+// ignored, once Head or Size differ from when it was set; mutations that
+// reorder nodes without changing either drop it explicitly.
+type finger struct {
+	node  *Node
+	index int
+	head  *Node
+	size  int
 }
 
 // NewDoublyLinkedList creates and returns an empty doubly linked list.
@@ -28,39 +73,43 @@ func NewDoublyLinkedList() *LinkedList {
 
 // Prepend adds a new node with the given value at the beginning of the list.
 func (ll *LinkedList) Prepend(value any) error {
-	newNode := &Node{Value: value, Next: ll.Head, Prev: nil}
+	if ll.Head != nil && ll.Head.Prev != nil {
+		return fmt.Errorf("%w: head node's prev pointer is not nil", ErrCorrupt)
+	}
+	if ll.Head == nil && ll.Tail != nil {
+		return fmt.Errorf("%w: inconsistent list state: head is nil but tail is not", ErrCorrupt)
+	}
+	newNode := ll.newNode(value)
+	newNode.Next = ll.Head
 	if ll.Head != nil {
-		if ll.Head.Prev != nil {
-			return fmt.Errorf("head node's prev pointer is not nil")
-		}
 		ll.Head.Prev = newNode
 	} else {
-		if ll.Tail != nil {
-			return fmt.Errorf("inconsistent list state: head is nil but tail is not")
-		}
 		ll.Tail = newNode
 	}
 	ll.Head = newNode
 	ll.Size++
+	emit(ll, event.Inserted{Index: 0, Value: value})
 	return nil
 }
 
 // Append adds a new node with the given value at the end of the list.
 func (ll *LinkedList) Append(value any) error {
-	newNode := &Node{Value: value, Next: nil, Prev: ll.Tail}
+	if ll.Tail != nil && ll.Tail.Next != nil {
+		return fmt.Errorf("%w: tail node's next pointer is not nil", ErrCorrupt)
+	}
+	if ll.Tail == nil && ll.Head != nil {
+		return fmt.Errorf("%w: inconsistent list state: tail is nil but head is not", ErrCorrupt)
+	}
+	newNode := ll.newNode(value)
+	newNode.Prev = ll.Tail
 	if ll.Tail != nil {
-		if ll.Tail.Next != nil {
-			return fmt.Errorf("tail node's next pointer is not nil")
-		}
 		ll.Tail.Next = newNode
 	} else {
-		if ll.Head != nil {
-			return fmt.Errorf("inconsistent list state: tail is nil but head is not")
-		}
 		ll.Head = newNode
 	}
 	ll.Tail = newNode
 	ll.Size++
+	emit(ll, event.Inserted{Index: ll.Size - 1, Value: value})
 	return nil
 }
 
@@ -94,10 +143,12 @@ func (ll *LinkedList) FromSlice(slice []any) error {
 		return fmt.Errorf("cannot create list from nil slice")
 	}
 
+	ll.beginBatch()
+	defer ll.endBatch()
 	ll.Clear()
 	for _, value := range slice {
 		if err := ll.Append(value); err != nil {
-			return fmt.Errorf("failed to append value: %v", err)
+			return fmt.Errorf("failed to append value: %w", err)
 		}
 	}
 	return nil
@@ -118,34 +169,68 @@ func (ll *LinkedList) FromArray(arr []any) error {
 		return fmt.Errorf("cannot create list from nil array")
 	}
 
+	ll.beginBatch()
+	defer ll.endBatch()
 	ll.Clear()
 	for _, value := range arr {
 		if err := ll.Append(value); err != nil {
-			return fmt.Errorf("failed to append value: %v", err)
+			return fmt.Errorf("failed to append value: %w", err)
 		}
 	}
 	return nil
 }
 
-// Search finds the first occurrence of a value in the list and returns its index.
+// Search finds the first occurrence of a value in the list and returns its
+// index. Values such as slices and maps can't be compared with ==, so
+// searching for one returns ErrNotComparable; use SearchFunc instead.
 func (ll *LinkedList) Search(value any) (int, error) {
+	if !equality.Comparable(value) {
+		return -1, fmt.Errorf("%w: %T; use SearchFunc", ErrNotComparable, value)
+	}
+	return ll.SearchFunc(func(v any) bool { return v == value })
+}
+
+// SearchFunc returns the index of the first value for which match returns
+// true, or ErrNotFound.
+func (ll *LinkedList) SearchFunc(match func(any) bool) (int, error) {
+	if index := ll.IndexFunc(match); index >= 0 {
+		return index, nil
+	}
+	return -1, ErrNotFound
+}
+
+// IndexFunc returns the index of the first value for which match returns
+// true, or -1.
+func (ll *LinkedList) IndexFunc(match func(any) bool) int {
 	index := 0
-	current := ll.Head
-	for current != nil {
-		if current.Value == value {
-			return index, nil
+	for current := ll.Head; current != nil; current = current.Next {
+		if match(current.Value) {
+			return index
 		}
-		current = current.Next
 		index++
 	}
-	return -1, fmt.Errorf("value not found")
+	return -1
+}
+
+// LastIndexFunc returns the index of the last value for which match returns
+// true, or -1. It walks from Tail.
+func (ll *LinkedList) LastIndexFunc(match func(any) bool) int {
+	index := ll.Size - 1
+	for current := ll.Tail; current != nil; current = current.Prev {
+		if match(current.Value) {
+			return index
+		}
+		index--
+	}
+	return -1
 }
 
 // Shift removes the first element from the list.
 func (ll *LinkedList) Shift() error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty nothing to delete")
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
 	}
+	removed := ll.Head
 	ll.Head = ll.Head.Next
 	if ll.Head != nil {
 		ll.Head.Prev = nil
@@ -153,14 +238,17 @@ func (ll *LinkedList) Shift() error {
 		ll.Tail = nil // List is now empty
 	}
 	ll.Size--
+	emit(ll, event.Removed{Index: 0, Value: removed.Value})
+	ll.release(removed)
 	return nil
 }
 
 // Pop removes the last element from the list.
 func (ll *LinkedList) Pop() error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty nothing to delete")
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
 	}
+	removed := ll.Tail
 	if ll.Head == ll.Tail {
 		ll.Head = nil
 		ll.Tail = nil
@@ -169,37 +257,41 @@ func (ll *LinkedList) Pop() error {
 		ll.Tail.Next = nil
 	}
 	ll.Size--
+	emit(ll, event.Removed{Index: ll.Size, Value: removed.Value})
+	ll.release(removed)
 	return nil
 }
 
 // Delete removes the first occurrence of the specified value from the list.
+// Deleting a value that can't be compared with ==, such as a slice, returns
+// ErrNotComparable; use DeleteFunc instead.
 func (ll *LinkedList) Delete(value any) error {
-	if ll.Head == nil {
-		return fmt.Errorf("list is empty nothing to delete")
-	}
-
-	if ll.Head.Value == value {
-		return ll.Shift()
+	if !equality.Comparable(value) {
+		return fmt.Errorf("%w: %T; use DeleteFunc", ErrNotComparable, value)
 	}
+	return ll.DeleteFunc(func(v any) bool { return v == value })
+}
 
-	if ll.Tail.Value == value {
-		return ll.Pop()
+// DeleteFunc removes the first value for which match returns true.
+func (ll *LinkedList) DeleteFunc(match func(any) bool) error {
+	if ll.Head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
 	}
 
+	index := 0
 	current := ll.Head
-	for current != nil && current.Value != value {
+	for current != nil && !match(current.Value) {
 		current = current.Next
+		index++
 	}
 
 	if current == nil {
-		return fmt.Errorf("value not found in the list")
+		return fmt.Errorf("%w in the list", ErrNotFound)
 	}
 
-	current.Prev.Next = current.Next
-	if current.Next != nil {
-		current.Next.Prev = current.Prev
-	}
-	ll.Size--
+	ll.unlink(current)
+	emit(ll, event.Removed{Index: index, Value: current.Value})
+	ll.release(current)
 	return nil
 }
 
@@ -208,30 +300,69 @@ func (ll *LinkedList) IsEmpty() bool {
 	return ll.Size == 0
 }
 
-// Clear removes all elements from the list.
+// Clear removes all elements from the list and releases their nodes.
 func (ll *LinkedList) Clear() {
+	count := ll.Size
+	if ll.Allocator != nil {
+		// Stop after Size nodes in case the list is corrupt and has a cycle
+		current := ll.Head
+		for i := 0; current != nil && i < count; i++ {
+			next := current.Next
+			ll.release(current)
+			current = next
+		}
+	}
 	ll.Head = nil
 	ll.Tail = nil
 	ll.Size = 0
+	ll.finger = finger{}
+	if count > 0 {
+		emit(ll, event.Cleared{Count: count})
+	}
 }
 
-// Get returns the value at the specified index.
+// Get returns the value at the specified index. It walks from Head, Tail or
+// the last index accessed, whichever is closest, so reading indices in order
+// takes constant time per call.
 func (ll *LinkedList) Get(index int) (any, error) {
 	if index < 0 || index >= ll.Size {
-		return nil, fmt.Errorf("index out of bounds")
+		return nil, ErrIndexOutOfBounds
 	}
-	current := ll.Head
+	return ll.nodeAt(index).Value, nil
+}
 
-	for i := 0; i < index; i++ {
-		current = current.Next
+// Set replaces the value at the specified index.
+func (ll *LinkedList) Set(index int, value any) error {
+	if index < 0 || index >= ll.Size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.Size)
+	}
+	node := ll.nodeAt(index)
+	old := node.Value
+	node.Value = value
+	emit(ll, event.Updated{Index: index, Old: old, Value: value})
+	return nil
+}
+
+// GetMiddle returns the middle element of the list. For an even number of
+// elements it returns the first of the two middle ones, like the singly list.
+func (ll *LinkedList) GetMiddle() (any, error) {
+	if ll.Head == nil {
+		return nil, ErrEmpty
+	}
+
+	// Walk in from both ends until the pointers meet
+	front, back := ll.Head, ll.Tail
+	for front != back && front.Next != back {
+		front = front.Next
+		back = back.Prev
 	}
-	return current.Value, nil
+	return front.Value, nil
 }
 
 // Insert adds a new value at the specified index.
 func (ll *LinkedList) Insert(value any, index int) error {
 	if index < 0 || index > ll.Size {
-		return fmt.Errorf("index out of bounds: index %d, size %d", index, ll.Size)
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.Size)
 	}
 	if index == 0 {
 		ll.Prepend(value)
@@ -242,28 +373,24 @@ func (ll *LinkedList) Insert(value any, index int) error {
 		return nil
 	}
 
-	current := ll.Head
-	for i := 0; i < index; i++ {
-		current = current.Next
-	}
-
-	newNode := &Node{
-		Value: value,
-		Next:  current,
-		Prev:  current.Prev,
-	}
+	current := ll.nodeAt(index)
+	newNode := ll.newNode(value)
+	newNode.Next = current
+	newNode.Prev = current.Prev
 
 	current.Prev.Next = newNode
 	current.Prev = newNode
 	ll.Size++
+	ll.setFinger(newNode, index)
+	emit(ll, event.Inserted{Index: index, Value: value})
 
 	return nil
 }
 
-// DeleteAt removes the element at the specified index..
+// DeleteAt removes the element at the specified index.
 func (ll *LinkedList) DeleteAt(index int) error {
-	if index < 0 || index > ll.Size {
-		return fmt.Errorf("index out of bounds")
+	if index < 0 || index >= ll.Size {
+		return ErrIndexOutOfBounds
 	}
 
 	if index == 0 {
@@ -276,23 +403,23 @@ func (ll *LinkedList) DeleteAt(index int) error {
 		return nil
 	}
 
-	current := ll.Head
-	for i := 0; i < index; i++ {
-		current = current.Next
-	}
-
+	current := ll.nodeAt(index)
 	current.Prev.Next = current.Next
 	if current.Next != nil {
 		current.Next.Prev = current.Prev
 	}
 	ll.Size--
+	emit(ll, event.Removed{Index: index, Value: current.Value})
+	next := current.Next
+	ll.release(current)
+	ll.setFinger(next, index)
 	return nil
 }
 
 // Reverse reverses the order of elements in the list.
 func (ll *LinkedList) Reverse() error {
 	if ll.Head == nil {
-		return fmt.Errorf("cannot reverse empty list")
+		return fmt.Errorf("cannot reverse: %w", ErrEmpty)
 	}
 	if ll.Size <= 1 {
 		return nil
@@ -311,19 +438,38 @@ func (ll *LinkedList) Reverse() error {
 		}
 		current = nextTemp
 	}
+	ll.finger = finger{}
+	emit(ll, event.Reordered{})
 	return nil
 }
 
-// Contains checks if a value exists in the list.
-func (ll *LinkedList) Contains(value any) bool {
-	current := ll.Head
-	for current != nil {
-		if current.Value == value {
-			return true
+// All returns an iterator over the indices and values of the list from head
+// to tail.
+func (ll *LinkedList) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		index := 0
+		for current := ll.Head; current != nil; current = current.Next {
+			if !yield(index, current.Value) {
+				return
+			}
+			index++
 		}
-		current = current.Next
 	}
-	return false
+}
+
+// Contains checks if a value exists in the list. Where Search returns
+// ErrNotComparable, Contains returns false; use ContainsFunc for such
+// values.
+func (ll *LinkedList) Contains(value any) bool {
+	if !equality.Comparable(value) {
+		return false
+	}
+	return ll.ContainsFunc(func(v any) bool { return v == value })
+}
+
+// ContainsFunc reports whether match returns true for any value.
+func (ll *LinkedList) ContainsFunc(match func(any) bool) bool {
+	return ll.IndexFunc(match) >= 0
 }
 
 // PrintReverse displays the list elements from tail to head.
@@ -339,49 +485,151 @@ func (ll *LinkedList) PrintReverse() {
 	fmt.Print("\n")
 }
 
+// Clone returns a shallow copy of the list: a new list holding the same
+// values, with the same Allocator but without the subscribers.
+func (ll *LinkedList) Clone() *LinkedList {
+	return ll.CloneFunc(identity)
+}
+
+// CloneFunc returns a copy of the list holding copy(v) for every value v,
+// for deep copies of values such as slices and pointers.
+func (ll *LinkedList) CloneFunc(copy func(any) any) *LinkedList {
+	clone := &LinkedList{Allocator: ll.Allocator}
+	for current := ll.Head; current != nil; current = current.Next {
+		clone.Append(copy(current.Value))
+	}
+	return clone
+}
+
+// Equal reports whether other holds the same values in the same order,
+// compared with ==. Values that can't be compared with ==, such as slices,
+// are never equal; use EqualFunc for them. A nil list counts as empty.
+func (ll *LinkedList) Equal(other *LinkedList) bool {
+	return ll.EqualFunc(other, equality.Equal)
+}
+
+// EqualFunc reports whether other holds the same number of values as the
+// list and eq returns true for every pair of values at the same index. A nil
+// list counts as empty.
+func (ll *LinkedList) EqualFunc(other *LinkedList, eq func(a, b any) bool) bool {
+	if other == nil {
+		return ll.Size == 0
+	}
+	if ll.Size != other.Size {
+		return false
+	}
+	for a, b := ll.Head, other.Head; a != nil && b != nil; a, b = a.Next, b.Next {
+		if !eq(a.Value, b.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares the list with other lexicographically, using cmp to
+// compare values. It returns the result of cmp for the first pair of values
+// that differ, or, when one list is a prefix of the other, -1 if the list is
+// the shorter one and +1 if other is. It returns 0 if the lists are equal. A
+// nil list counts as empty.
+func (ll *LinkedList) Compare(other *LinkedList, cmp func(a, b any) int) int {
+	a := ll.Head
+	var b *Node
+	if other != nil {
+		b = other.Head
+	}
+	for ; a != nil && b != nil; a, b = a.Next, b.Next {
+		if c := cmp(a.Value, b.Value); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a != nil:
+		return 1
+	case b != nil:
+		return -1
+	}
+	return 0
+}
+
 // Merge combines the current list with another list.
 func (ll *LinkedList) Merge(list *LinkedList) error {
 	if list == nil {
 		return fmt.Errorf("cannot merge with nil list")
 	}
 
+	ll.beginBatch()
+	defer ll.endBatch()
+
+	// Take the count first so merging a list into itself terminates
 	current := list.Head
-	for current != nil {
+	for count := list.Size; count > 0; count-- {
 		if err := ll.Append(current.Value); err != nil {
-			return fmt.Errorf("merge failed: %v", err)
+			return fmt.Errorf("merge failed: %w", err)
 		}
 		current = current.Next
 	}
 	return nil
 }
 
-// Unique removes duplicate values from the list.
+// Unique removes duplicate values from the list. If any value can't be
+// compared with ==, such as a slice, it returns ErrNotComparable and leaves
+// the list unchanged; use UniqueBy instead.
 func (ll *LinkedList) Unique() error {
+	return ll.UniqueBy(nil)
+}
+
+// UniqueBy removes every value whose key, as returned by key, matches the
+// key of an earlier value. Keys must be comparable with ==; a nil key
+// function uses the values themselves.
+func (ll *LinkedList) UniqueBy(key func(any) any) error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty")
+		return ErrEmpty
+	}
+	if key == nil {
+		key = identity
+	}
+
+	// Compute every key first, so a bad one leaves the list unchanged
+	keys := make([]any, 0, ll.Size)
+	for current := ll.Head; current != nil; current = current.Next {
+		k := key(current.Value)
+		if !equality.Comparable(k) {
+			return fmt.Errorf("%w: %T at index %d", ErrNotComparable, k, len(keys))
+		}
+		keys = append(keys, k)
 	}
 	if ll.Size <= 1 {
 		return nil
 	}
 
+	ll.beginBatch()
+	defer ll.endBatch()
+
 	visited := make(map[any]bool)
 	current := ll.Head
-	visited[current.Value] = true
-
-	for current.Next != nil {
-		if visited[current.Next.Value] {
-			current.Next = current.Next.Next
+	visited[keys[0]] = true
+	index := 1
+
+	for i := 1; current.Next != nil; i++ {
+		if visited[keys[i]] {
+			removed := current.Next
+			emit(ll, event.Removed{Index: index, Value: removed.Value})
+			current.Next = removed.Next
 			if current.Next != nil {
 				current.Next.Prev = current
 			} else {
 				ll.Tail = current
 			}
 			ll.Size--
+			ll.release(removed)
 		} else {
-			visited[current.Next.Value] = true
+			visited[keys[i]] = true
 			current = current.Next
+			index++
 		}
 	}
+	// Appending could restore the old size with the finger's index stale
+	ll.finger = finger{}
 	return nil
 }
 
@@ -391,6 +639,13 @@ func (ll *LinkedList) Sort() error {
 		return nil
 	}
 
+	changed := false
+	defer func() {
+		if changed {
+			emit(ll, event.Reordered{})
+		}
+	}()
+
 	swapped := true
 	for swapped {
 		swapped = false
@@ -401,32 +656,35 @@ func (ll *LinkedList) Sort() error {
 			case int:
 				y, ok := current.Next.Value.(int)
 				if !ok {
-					return fmt.Errorf("mismatched types in list")
+					return ErrMismatchedTypes
 				}
 				if x > y {
 					current.Value, current.Next.Value = current.Next.Value, current.Value
 					swapped = true
+					changed = true
 				}
 			case string:
 				y, ok := current.Next.Value.(string)
 				if !ok {
-					return fmt.Errorf("mismatched types in list")
+					return ErrMismatchedTypes
 				}
 				if x > y {
 					current.Value, current.Next.Value = current.Next.Value, current.Value
 					swapped = true
+					changed = true
 				}
 			case float64:
 				y, ok := current.Next.Value.(float64)
 				if !ok {
-					return fmt.Errorf("mismatched types in list")
+					return ErrMismatchedTypes
 				}
 				if x > y {
 					current.Value, current.Next.Value = current.Next.Value, current.Value
 					swapped = true
+					changed = true
 				}
 			default:
-				return fmt.Errorf("unsupported type for sorting")
+				return ErrUnsupportedType
 			}
 			current = current.Next
 		}
@@ -439,22 +697,34 @@ func (ll *LinkedList) Validate() error {
 	// Check if empty list is valid
 	if ll.Head == nil {
 		if ll.Tail != nil {
-			return fmt.Errorf("head is nil but tail is not")
+			return fmt.Errorf("%w: head is nil but tail is not", ErrCorrupt)
 		}
 		if ll.Size != 0 {
-			return fmt.Errorf("empty list has non-zero size")
+			return fmt.Errorf("%w: empty list has non-zero size", ErrCorrupt)
 		}
 		return nil
 	}
 
+	if ll.Tail == nil {
+		return fmt.Errorf("%w: tail is nil but head is not", ErrCorrupt)
+	}
+
 	// Check head node's prev pointer
 	if ll.Head.Prev != nil {
-		return fmt.Errorf("head node has non-nil prev pointer")
+		return fmt.Errorf("%w: head node has non-nil prev pointer", ErrCorrupt)
 	}
 
 	// Check tail node's next pointer
 	if ll.Tail.Next != nil {
-		return fmt.Errorf("tail node has non-nil next pointer")
+		return fmt.Errorf("%w: tail node has non-nil next pointer", ErrCorrupt)
+	}
+
+	// Check both chains for cycles
+	if cycle, ok := ll.DetectCycle(); ok {
+		return fmt.Errorf("%w: list contains a cycle of length %d starting at index %d", ErrCorrupt, cycle.Length, cycle.Start)
+	}
+	if cycle, ok := ll.DetectPrevCycle(); ok {
+		return fmt.Errorf("%w: prev chain contains a cycle of length %d starting %d steps from tail", ErrCorrupt, cycle.Length, cycle.Start)
 	}
 
 	// Count nodes and verify links
@@ -465,12 +735,12 @@ func (ll *LinkedList) Validate() error {
 	for current != nil {
 		count++
 		if count > ll.Size {
-			return fmt.Errorf("list contains more nodes than Size indicates")
+			return fmt.Errorf("%w: list contains more nodes than Size indicates", ErrCorrupt)
 		}
 
 		// Verify prev/next links
 		if current.Next != nil && current.Next.Prev != current {
-			return fmt.Errorf("broken bidirectional link found")
+			return fmt.Errorf("%w: broken bidirectional link found", ErrCorrupt)
 		}
 
 		lastNode = current
@@ -479,13 +749,260 @@ func (ll *LinkedList) Validate() error {
 
 	// Verify size
 	if count != ll.Size {
-		return fmt.Errorf("actual node count (%d) differs from Size (%d)", count, ll.Size)
+		return fmt.Errorf("%w: actual node count (%d) differs from Size (%d)", ErrCorrupt, count, ll.Size)
 	}
 
 	// Verify tail pointer
 	if lastNode != ll.Tail {
-		return fmt.Errorf("tail pointer does not point to last node")
+		return fmt.Errorf("%w: tail pointer does not point to last node", ErrCorrupt)
 	}
 
 	return nil
 }
+
+// Move relocates the element at index from so that it ends up at index to.
+func (ll *LinkedList) Move(from, to int) error {
+	if from < 0 || from >= ll.Size || to < 0 || to >= ll.Size {
+		return ErrIndexOutOfBounds
+	}
+	if from == to {
+		return nil
+	}
+
+	node := ll.nodeAt(from)
+	ll.unlink(node)
+
+	// Find the node that will follow it once moved
+	var at *Node
+	if to < ll.Size {
+		at = ll.nodeAt(to)
+	}
+	ll.linkBefore(node, at)
+	ll.setFinger(node, to)
+	emit(ll, event.Moved{From: from, To: to, Value: node.Value})
+	return nil
+}
+
+// InsertAfter adds a new node with the given value right after node and
+// returns it. A nil node inserts at the beginning of the list. The node must
+// belong to the list.
+func (ll *LinkedList) InsertAfter(node *Node, value any) *Node {
+	newNode := ll.newNode(value)
+	if node == nil {
+		ll.linkBefore(newNode, ll.Head)
+	} else {
+		ll.linkBefore(newNode, node.Next)
+	}
+	if ll.observers.Active() {
+		emit(ll, event.Inserted{Index: ll.indexOf(newNode), Value: value})
+	}
+	return newNode
+}
+
+// Remove detaches node from the list. The node must belong to the list. It
+// is not released, so the caller can still read its Value.
+func (ll *LinkedList) Remove(node *Node) error {
+	if node == nil {
+		return fmt.Errorf("cannot remove nil node")
+	}
+	if ll.Head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	index := -1
+	if ll.observers.Active() {
+		index = ll.indexOf(node)
+	}
+	ll.unlink(node)
+	if index >= 0 {
+		emit(ll, event.Removed{Index: index, Value: node.Value})
+	}
+	return nil
+}
+
+// MoveToBack relinks node at the end of the list without allocating. The
+// node must belong to the list.
+func (ll *LinkedList) MoveToBack(node *Node) {
+	if node == ll.Tail {
+		return
+	}
+	from := -1
+	if ll.observers.Active() {
+		from = ll.indexOf(node)
+	}
+	ll.unlink(node)
+	ll.linkBefore(node, nil)
+	if from >= 0 {
+		emit(ll, event.Moved{From: from, To: ll.Size - 1, Value: node.Value})
+	}
+}
+
+// Subscribe registers fn to be called after every change to the list and
+// returns a function that unsubscribes it. Bulk operations such as FromSlice,
+// Merge and Unique deliver their changes as a single event.Batch.
+//
+// Subscribe creates the list's event hub on first use, so like the other
+// methods it must not run concurrently with changes to the list. The
+// function it returns may be called from any goroutine.
+func (ll *LinkedList) Subscribe(fn func(event.Event)) (unsubscribe func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.Subscribe(fn)
+}
+
+// SubscribeChan is like Subscribe but delivers events on a channel with the
+// given buffer size. Mutations block while the buffer is full. Unsubscribing
+// closes the channel.
+func (ll *LinkedList) SubscribeChan(buffer int) (<-chan event.Event, func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.SubscribeChan(buffer)
+}
+
+// unlink detaches node from the list without emitting an event.
+func (ll *LinkedList) unlink(node *Node) {
+	if node.Prev != nil {
+		node.Prev.Next = node.Next
+	} else {
+		ll.Head = node.Next
+	}
+	if node.Next != nil {
+		node.Next.Prev = node.Prev
+	} else {
+		ll.Tail = node.Prev
+	}
+	node.Next = nil
+	node.Prev = nil
+	ll.Size--
+	ll.finger = finger{}
+}
+
+// linkBefore attaches node in front of at, or at the end of the list when at
+// is nil, without emitting an event.
+func (ll *LinkedList) linkBefore(node, at *Node) {
+	if at == nil {
+		node.Prev = ll.Tail
+		node.Next = nil
+		if ll.Tail != nil {
+			ll.Tail.Next = node
+		} else {
+			ll.Head = node
+		}
+		ll.Tail = node
+	} else {
+		node.Prev = at.Prev
+		node.Next = at
+		if at.Prev != nil {
+			at.Prev.Next = node
+		} else {
+			ll.Head = node
+		}
+		at.Prev = node
+	}
+	ll.Size++
+	ll.finger = finger{}
+}
+
+// nodeAt returns the node at index, which must be in range, and leaves the
+// finger on it.
+func (ll *LinkedList) nodeAt(index int) *Node {
+	node := ll.findNode(index)
+	ll.setFinger(node, index)
+	return node
+}
+
+// findNode returns the node at index, which must be in range, without moving
+// the finger. It walks from Head, Tail or the finger, whichever is closest.
+func (ll *LinkedList) findNode(index int) *Node {
+	node, at := ll.Head, 0
+	if index > ll.Size/2 {
+		node, at = ll.Tail, ll.Size-1
+	}
+	if f := ll.finger; f.node != nil && f.head == ll.Head && f.size == ll.Size && distance(f.index, index) < distance(at, index) {
+		node, at = f.node, f.index
+	}
+	for ; at < index; at++ {
+		node = node.Next
+	}
+	for ; at > index; at-- {
+		node = node.Prev
+	}
+	return node
+}
+
+// setFinger records node as the one at index.
+func (ll *LinkedList) setFinger(node *Node, index int) {
+	ll.finger = finger{node: node, index: index, head: ll.Head, size: ll.Size}
+}
+
+// identity returns v.
+func identity(v any) any {
+	return v
+}
+
+// distance returns how many steps apart two indices are.
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// indexOf returns the position of node in the list, or -1 if it is not there.
+func (ll *LinkedList) indexOf(node *Node) int {
+	index := 0
+	for current := ll.Head; current != nil; current = current.Next {
+		if current == node {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// newNode returns a node holding value, taken from the allocator if there
+// is one.
+func (ll *LinkedList) newNode(value any) *Node {
+	if ll.Allocator == nil {
+		return &Node{Value: value}
+	}
+	node := ll.Allocator.New()
+	node.Value = value
+	return node
+}
+
+// release hands a node the list no longer uses to the allocator, which
+// zeroes it for reuse. Without an allocator the node is left as it is, since
+// a caller may still be holding it and walking on from it.
+func (ll *LinkedList) release(node *Node) {
+	if node == ll.finger.node {
+		ll.finger = finger{}
+	}
+	if ll.Allocator != nil {
+		ll.Allocator.Free(node)
+	}
+}
+
+// emit sends e to the list's subscribers, if there are any. It is generic
+// so that e is only boxed into an event.Event, which allocates, when someone
+// is listening.
+func emit[E event.Event](ll *LinkedList, e E) {
+	if ll.observers.Active() {
+		ll.observers.Emit(e)
+	}
+}
+
+// beginBatch starts collecting events for a bulk operation.
+func (ll *LinkedList) beginBatch() {
+	if ll.observers != nil {
+		ll.observers.Begin()
+	}
+}
+
+// endBatch delivers the events collected since beginBatch.
+func (ll *LinkedList) endBatch() {
+	if ll.observers != nil {
+		ll.observers.End()
+	}
+}
@@ -0,0 +1,162 @@
+package doubly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FixKind identifies what a single repair changed.
+type FixKind int
+
+const (
+	FixCycle    FixKind = iota // A Next or Prev back-edge closing a cycle was cut
+	FixHead                    // Head was restored from the Prev chain
+	FixNext                    // A Next link was rebuilt from the Prev chain
+	FixHeadPrev                // The head node's Prev pointer was cleared
+	FixPrev                    // A Prev link was rebuilt from the Next chain
+	FixTail                    // Tail was pointed at the last node
+	FixSize                    // Size was recomputed
+)
+
+// String returns a short name for the fix kind.
+func (k FixKind) String() string {
+	switch k {
+	case FixCycle:
+		return "cycle"
+	case FixHead:
+		return "head"
+	case FixNext:
+		return "next"
+	case FixHeadPrev:
+		return "head-prev"
+	case FixPrev:
+		return "prev"
+	case FixTail:
+		return "tail"
+	case FixSize:
+		return "size"
+	}
+	return fmt.Sprintf("FixKind(%d)", int(k))
+}
+
+// Fix records one change made by Repair.
+type Fix struct {
+	Kind   FixKind
+	Index  int // Index of the affected node, or -1 when the fix is not about one node
+	Detail string
+}
+
+// String formats the fix for logs.
+func (f Fix) String() string {
+	if f.Index < 0 {
+		return fmt.Sprintf("%s: %s", f.Kind, f.Detail)
+	}
+	return fmt.Sprintf("%s at %d: %s", f.Kind, f.Index, f.Detail)
+}
+
+// RepairReport lists every fix applied by Repair, in order.
+type RepairReport struct {
+	Fixes []Fix
+}
+
+// Changed reports whether Repair had to fix anything.
+func (r RepairReport) Changed() bool {
+	return len(r.Fixes) > 0
+}
+
+// String formats the report one fix per line.
+func (r RepairReport) String() string {
+	if !r.Changed() {
+		return "no repairs needed"
+	}
+	lines := make([]string, len(r.Fixes))
+	for i, fix := range r.Fixes {
+		lines[i] = fix.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add appends a fix to the report.
+func (r *RepairReport) add(kind FixKind, index int, format string, args ...any) {
+	r.Fixes = append(r.Fixes, Fix{Kind: kind, Index: index, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Repair rebuilds a consistent list from its most trustworthy chain and
+// reports every fix it applied. The Next chain from Head is trusted; only
+// when Head is nil is the Prev chain from Tail used instead. Cycles are cut,
+// Prev links are rebuilt from Next, and Tail and Size are recomputed. Nodes
+// that are no longer reachable from the trusted chain are dropped. Validate
+// returns nil after Repair.
+func (ll *LinkedList) Repair() RepairReport {
+	var report RepairReport
+	ll.finger = finger{}
+
+	if ll.Head == nil && ll.Tail != nil {
+		ll.restoreFromPrev(&report)
+	}
+
+	// Cut a cycle in the Next chain at its back-edge
+	if start, length, entry := findCycle(ll.Head, next); length > 0 {
+		last := entry
+		for i := 1; i < length; i++ {
+			last = last.Next
+		}
+		last.Next = nil
+		report.add(FixCycle, start+length-1, "cut next link closing a cycle of length %d back to index %d", length, start)
+	}
+
+	if ll.Head != nil && ll.Head.Prev != nil {
+		ll.Head.Prev = nil
+		report.add(FixHeadPrev, 0, "cleared prev pointer of head node")
+	}
+
+	count := 0
+	var last *Node
+	for current := ll.Head; current != nil; current = current.Next {
+		if current.Next != nil && current.Next.Prev != current {
+			current.Next.Prev = current
+			report.add(FixPrev, count+1, "rebuilt prev link from next chain")
+		}
+		count++
+		last = current
+	}
+
+	if ll.Tail != last {
+		ll.Tail = last
+		report.add(FixTail, -1, "pointed tail at the last of %d nodes", count)
+	}
+	if ll.Size != count {
+		report.add(FixSize, -1, "recomputed size from %d to %d", ll.Size, count)
+		ll.Size = count
+	}
+
+	return report
+}
+
+// restoreFromPrev recovers Head and the Next chain by walking Prev from Tail,
+// cutting a cycle in the Prev chain first if there is one.
+func (ll *LinkedList) restoreFromPrev(report *RepairReport) {
+	if start, length, entry := findCycle(ll.Tail, prev); length > 0 {
+		first := entry
+		for i := 1; i < length; i++ {
+			first = first.Prev
+		}
+		first.Prev = nil
+		report.add(FixCycle, -1, "cut prev link closing a cycle of length %d, %d steps from tail", length, start)
+	}
+
+	current := ll.Tail
+	if current.Next != nil {
+		current.Next = nil
+		report.add(FixNext, -1, "cleared next pointer of tail node")
+	}
+	for current.Prev != nil {
+		if current.Prev.Next != current {
+			current.Prev.Next = current
+			report.add(FixNext, -1, "rebuilt next link from prev chain")
+		}
+		current = current.Prev
+	}
+	ll.Head = current
+	report.add(FixHead, -1, "restored head from the prev chain")
+}
@@ -0,0 +1,433 @@
+// Package durable implements a doubly linked list whose contents survive a
+// process restart. Every mutation is appended to a checksummed write-ahead
+// log, which is periodically compacted into a snapshot.
+package durable
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/JustMrNone/ll/codec"
+	"github.com/JustMrNone/ll/doubly"
+)
+
+// File names inside the list directory.
+const (
+	logName      = "wal.log"
+	snapshotName = "snapshot"
+)
+
+// SyncPolicy controls when the log is flushed to stable storage.
+type SyncPolicy int
+
+const (
+	SyncAlways SyncPolicy = iota // fsync after every record
+	SyncBatch                    // fsync after every Options.SyncEvery records
+	SyncNever                    // leave flushing to the operating system
+)
+
+// Options configures a durable list.
+type Options struct {
+	Sync         SyncPolicy  // When to fsync the log
+	SyncEvery    int         // Records between fsyncs with SyncBatch
+	CompactEvery int         // Records after which the log is compacted; 0 disables
+	Codec        codec.Codec // Value encoding; nil means codec.JSON
+
+	// OnCompactError is called when a compaction started by CompactEvery
+	// fails. The mutation that triggered it has already been applied and
+	// logged, so it still returns nil, and compaction is tried again on the
+	// next mutation. Nil ignores the error.
+	OnCompactError func(err error)
+}
+
+// List is a doubly linked list backed by a write-ahead log. Values are kept
+// in memory exactly as the codec decodes them, so the list looks the same
+// before and after a restart. It is not safe for concurrent use.
+type List struct {
+	dir      string
+	opts     Options
+	list     *doubly.LinkedList
+	wal      *os.File
+	walSize  int64  // Bytes of complete records in the log
+	seq      uint64 // Sequence number of the last record written
+	records  int    // Records written since the last compaction
+	unsynced int    // Records written since the last fsync
+	err      error  // Sticky error after a failed write
+}
+
+// Open opens the durable list stored in dir, creating it if needed. It loads
+// the latest snapshot, replays the log on top of it and truncates a torn
+// final record left behind by a crash.
+func Open(dir string, opts Options) (*List, error) {
+	if opts.Codec == nil {
+		opts.Codec = codec.JSON
+	}
+	if opts.Sync == SyncBatch && opts.SyncEvery <= 0 {
+		return nil, fmt.Errorf("SyncBatch requires a positive SyncEvery")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &List{dir: dir, opts: opts, list: doubly.NewDoublyLinkedList()}
+	if err := l.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := l.replay(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, logName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l.wal = wal
+	return l, nil
+}
+
+// Append adds a value at the end of the list.
+func (l *List) Append(value any) error {
+	return l.mutate(entry{op: opAppend}, value)
+}
+
+// Prepend adds a value at the beginning of the list.
+func (l *List) Prepend(value any) error {
+	return l.mutate(entry{op: opPrepend}, value)
+}
+
+// Insert adds a value at the specified index.
+func (l *List) Insert(value any, index int) error {
+	if index < 0 || index > l.list.Size {
+		return fmt.Errorf("%w: index %d, size %d", doubly.ErrIndexOutOfBounds, index, l.list.Size)
+	}
+	return l.mutate(entry{op: opInsert, index: index}, value)
+}
+
+// DeleteAt removes the element at the specified index.
+func (l *List) DeleteAt(index int) error {
+	if index < 0 || index >= l.list.Size {
+		return fmt.Errorf("%w: index %d, size %d", doubly.ErrIndexOutOfBounds, index, l.list.Size)
+	}
+	return l.mutate(entry{op: opDeleteAt, index: index}, nil)
+}
+
+// Shift removes the first element from the list.
+func (l *List) Shift() error {
+	if l.list.Size == 0 {
+		return fmt.Errorf("%w, nothing to delete", doubly.ErrEmpty)
+	}
+	return l.mutate(entry{op: opShift}, nil)
+}
+
+// Pop removes the last element from the list.
+func (l *List) Pop() error {
+	if l.list.Size == 0 {
+		return fmt.Errorf("%w, nothing to delete", doubly.ErrEmpty)
+	}
+	return l.mutate(entry{op: opPop}, nil)
+}
+
+// Clear removes all elements from the list.
+func (l *List) Clear() error {
+	return l.mutate(entry{op: opClear}, nil)
+}
+
+// Get returns the value at the specified index.
+func (l *List) Get(index int) (any, error) {
+	return l.list.Get(index)
+}
+
+// Len returns the number of elements in the list.
+func (l *List) Len() int {
+	return l.list.Size
+}
+
+// IntoSlice converts the list into a slice.
+func (l *List) IntoSlice() []any {
+	return l.list.IntoSlice()
+}
+
+// Sync flushes the log to stable storage regardless of the sync policy.
+func (l *List) Sync() error {
+	if l.err != nil {
+		return l.err
+	}
+	if err := l.wal.Sync(); err != nil {
+		return err
+	}
+	l.unsynced = 0
+	return nil
+}
+
+// Compact writes the current contents to a new snapshot and empties the log.
+func (l *List) Compact() error {
+	if l.err != nil {
+		return l.err
+	}
+
+	header := make([]byte, 16)
+	binary.LittleEndian.PutUint64(header[0:8], l.seq)
+	binary.LittleEndian.PutUint64(header[8:16], uint64(l.list.Size))
+	buf := frame(header)
+	for current := l.list.Head; current != nil; current = current.Next {
+		data, err := l.opts.Codec.Encode(current.Value)
+		if err != nil {
+			return fmt.Errorf("failed to encode value: %w", err)
+		}
+		if len(data) > maxRecordSize {
+			return fmt.Errorf("encoded value of %d bytes is too large", len(data))
+		}
+		buf = append(buf, frame(data)...)
+	}
+
+	// Write the snapshot under a temporary name and rename it into place, so
+	// a crash leaves either the old snapshot or the new one.
+	tmp := filepath.Join(l.dir, snapshotName+".tmp")
+	if err := writeFileSync(tmp, buf); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(l.dir, snapshotName)); err != nil {
+		return err
+	}
+	if err := syncDir(l.dir); err != nil {
+		return err
+	}
+
+	// Records up to l.seq are now in the snapshot. A crash before the
+	// truncate is harmless because replay skips them.
+	if err := l.wal.Truncate(0); err != nil {
+		return l.fail(err)
+	}
+	if err := l.wal.Sync(); err != nil {
+		return l.fail(err)
+	}
+	l.walSize = 0
+	l.records = 0
+	l.unsynced = 0
+	return nil
+}
+
+// Close flushes the log and closes it.
+func (l *List) Close() error {
+	syncErr := l.Sync()
+	closeErr := l.wal.Close()
+	if syncErr != nil {
+		return syncErr
+	}
+	return closeErr
+}
+
+// mutate logs e and then applies it to the in-memory list. Callers have
+// already checked that the op will succeed.
+func (l *List) mutate(e entry, value any) error {
+	if l.err != nil {
+		return l.err
+	}
+
+	switch e.op {
+	case opAppend, opPrepend, opInsert:
+		data, err := l.opts.Codec.Encode(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode value: %w", err)
+		}
+		e.value = data
+	}
+	if len(e.value) > maxRecordSize-entryHeaderSize {
+		return fmt.Errorf("encoded value of %d bytes is too large", len(e.value))
+	}
+	e.seq = l.seq + 1
+
+	rec := frame(e.encode())
+	if _, err := l.wal.Write(rec); err != nil {
+		// Drop whatever part of the record made it out, so the next
+		// record doesn't land behind garbage.
+		if terr := l.wal.Truncate(l.walSize); terr != nil {
+			return l.fail(fmt.Errorf("write failed: %w; truncate failed: %w", err, terr))
+		}
+		return err
+	}
+	l.walSize += int64(len(rec))
+	l.seq = e.seq
+	l.records++
+	l.unsynced++
+
+	if err := l.apply(e); err != nil {
+		return l.fail(err)
+	}
+
+	switch l.opts.Sync {
+	case SyncAlways:
+		if err := l.Sync(); err != nil {
+			return err
+		}
+	case SyncBatch:
+		if l.unsynced >= l.opts.SyncEvery {
+			if err := l.Sync(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if l.opts.CompactEvery > 0 && l.records >= l.opts.CompactEvery {
+		if err := l.Compact(); err != nil && l.opts.OnCompactError != nil {
+			l.opts.OnCompactError(err)
+		}
+	}
+	return nil
+}
+
+// apply performs a logged op on the in-memory list.
+func (l *List) apply(e entry) error {
+	var value any
+	switch e.op {
+	case opAppend, opPrepend, opInsert:
+		v, err := l.opts.Codec.Decode(e.value)
+		if err != nil {
+			return fmt.Errorf("failed to decode value: %w", err)
+		}
+		value = v
+	}
+
+	switch e.op {
+	case opAppend:
+		return l.list.Append(value)
+	case opPrepend:
+		return l.list.Prepend(value)
+	case opInsert:
+		return l.list.Insert(value, e.index)
+	case opDeleteAt:
+		if e.index < 0 || e.index >= l.list.Size {
+			return fmt.Errorf("%w: index %d, size %d", doubly.ErrIndexOutOfBounds, e.index, l.list.Size)
+		}
+		return l.list.DeleteAt(e.index)
+	case opShift:
+		return l.list.Shift()
+	case opPop:
+		return l.list.Pop()
+	case opClear:
+		l.list.Clear()
+		return nil
+	}
+	return fmt.Errorf("unknown op code %d", e.op)
+}
+
+// loadSnapshot reads the snapshot file, if there is one.
+func (l *List) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(l.dir, snapshotName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	header, offset, err := readRecord(data, 0)
+	if err != nil || len(header) != 16 {
+		return fmt.Errorf("%w: bad snapshot header", ErrCorrupt)
+	}
+	l.seq = binary.LittleEndian.Uint64(header[0:8])
+	count := binary.LittleEndian.Uint64(header[8:16])
+
+	for i := uint64(0); i < count; i++ {
+		var payload []byte
+		payload, offset, err = readRecord(data, offset)
+		if err != nil {
+			if err == errTorn || err == io.EOF {
+				err = fmt.Errorf("%w: snapshot value %d is cut short", ErrCorrupt, i)
+			}
+			return fmt.Errorf("snapshot value %d: %w", i, err)
+		}
+		value, err := l.opts.Codec.Decode(payload)
+		if err != nil {
+			return fmt.Errorf("failed to decode snapshot value %d: %w", i, err)
+		}
+		l.list.Append(value)
+	}
+	if offset != len(data) {
+		return fmt.Errorf("%w: %d trailing bytes in snapshot", ErrCorrupt, len(data)-offset)
+	}
+	return nil
+}
+
+// replay applies the log records that are newer than the snapshot and cuts
+// off a torn final record.
+func (l *List) replay() error {
+	path := filepath.Join(l.dir, logName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	offset := 0
+	for {
+		payload, next, err := readRecord(data, offset)
+		if err == io.EOF {
+			break
+		}
+		if err == errTorn {
+			if err := os.Truncate(path, int64(offset)); err != nil {
+				return fmt.Errorf("failed to truncate torn record: %w", err)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		e, err := decodeEntry(payload)
+		if err != nil {
+			return fmt.Errorf("log record at offset %d: %w", offset, err)
+		}
+		// Records already folded into the snapshot are skipped
+		if e.seq > l.seq {
+			if err := l.apply(e); err != nil {
+				return fmt.Errorf("failed to replay log record at offset %d: %w", offset, err)
+			}
+			l.seq = e.seq
+			l.records++
+		}
+		offset = next
+	}
+	l.walSize = int64(offset)
+	return nil
+}
+
+// fail records a write error after which the log can no longer be trusted.
+func (l *List) fail(err error) error {
+	l.err = fmt.Errorf("durable list is unusable: %w", err)
+	return l.err
+}
+
+// writeFileSync writes data to path and flushes it to stable storage.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes directory metadata so a rename survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,118 @@
+package durable
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Every record on disk is framed as a 4-byte payload length, a 4-byte CRC-32C
+// of the payload, and the payload itself, all little-endian.
+const headerSize = 8
+
+// maxRecordSize bounds the payload of a record. A length field above it can
+// only come from damage, never from a write that was cut short.
+const maxRecordSize = 1 << 30
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrCorrupt is returned by Open when the log or snapshot is damaged in a
+// way an interrupted write can't explain.
+var ErrCorrupt = errors.New("corrupt data")
+
+// errTorn reports a record that was cut short, or whose checksum fails, at
+// the very end of the file, which is what an interrupted write leaves behind.
+var errTorn = errors.New("torn record")
+
+// frame wraps payload in a record header.
+func frame(payload []byte) []byte {
+	buf := make([]byte, headerSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, crcTable))
+	copy(buf[headerSize:], payload)
+	return buf
+}
+
+// readRecord decodes the record starting at offset in data and returns its
+// payload and the offset of the next record. Damage anywhere but in the last
+// record wraps ErrCorrupt.
+func readRecord(data []byte, offset int) ([]byte, int, error) {
+	rest := data[offset:]
+	if len(rest) == 0 {
+		return nil, offset, io.EOF
+	}
+	if len(rest) < headerSize {
+		return nil, offset, errTorn
+	}
+	length := int(binary.LittleEndian.Uint32(rest[0:4]))
+	sum := binary.LittleEndian.Uint32(rest[4:8])
+	if length > maxRecordSize {
+		return nil, offset, fmt.Errorf("%w: record at offset %d claims %d bytes", ErrCorrupt, offset, length)
+	}
+	if length > len(rest)-headerSize {
+		return nil, offset, errTorn
+	}
+	payload := rest[headerSize : headerSize+length]
+	end := offset + headerSize + length
+	if crc32.Checksum(payload, crcTable) != sum {
+		if end == len(data) {
+			return nil, offset, errTorn
+		}
+		return nil, offset, fmt.Errorf("%w: checksum mismatch in record at offset %d", ErrCorrupt, offset)
+	}
+	return payload, end, nil
+}
+
+// opCode identifies the mutation a log record replays.
+type opCode byte
+
+const (
+	opAppend opCode = iota + 1
+	opPrepend
+	opInsert
+	opDeleteAt
+	opShift
+	opPop
+	opClear
+)
+
+// entry is a decoded log record.
+type entry struct {
+	seq   uint64
+	op    opCode
+	index int
+	value []byte // Encoded value, for ops that add one
+}
+
+// Log payloads hold the sequence number, the op code and the index, followed
+// by the encoded value.
+const entryHeaderSize = 8 + 1 + 8
+
+// encode serializes the entry into a log payload.
+func (e entry) encode() []byte {
+	buf := make([]byte, entryHeaderSize+len(e.value))
+	binary.LittleEndian.PutUint64(buf[0:8], e.seq)
+	buf[8] = byte(e.op)
+	binary.LittleEndian.PutUint64(buf[9:17], uint64(int64(e.index)))
+	copy(buf[entryHeaderSize:], e.value)
+	return buf
+}
+
+// decodeEntry parses a log payload.
+func decodeEntry(payload []byte) (entry, error) {
+	if len(payload) < entryHeaderSize {
+		return entry{}, fmt.Errorf("%w: log record too short: %d bytes", ErrCorrupt, len(payload))
+	}
+	e := entry{
+		seq:   binary.LittleEndian.Uint64(payload[0:8]),
+		op:    opCode(payload[8]),
+		index: int(int64(binary.LittleEndian.Uint64(payload[9:17]))),
+		value: payload[entryHeaderSize:],
+	}
+	if e.op < opAppend || e.op > opClear {
+		return entry{}, fmt.Errorf("%w: unknown op code %d", ErrCorrupt, e.op)
+	}
+	return e, nil
+}
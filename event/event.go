@@ -0,0 +1,56 @@
+// Package event defines the change notifications emitted by list mutations.
+package event
+
+// Event is a change notification emitted by a list after a mutation.
+type Event interface {
+	event()
+}
+
+// Inserted reports that Value was inserted at Index.
+type Inserted struct {
+	Index int // Position of the new element
+	Value any // Value that was inserted
+}
+
+// Removed reports that Value was removed from Index.
+type Removed struct {
+	Index int // Position the element occupied before removal
+	Value any // Value that was removed
+}
+
+// Updated reports that the value at Index was replaced.
+type Updated struct {
+	Index int // Position of the element
+	Old   any // Value before the update
+	Value any // Value after the update
+}
+
+// Moved reports that Value moved from index From to index To.
+type Moved struct {
+	From  int // Position before the move
+	To    int // Position after the move
+	Value any // Value that was moved
+}
+
+// Cleared reports that every element was removed from the list.
+type Cleared struct {
+	Count int // Number of elements removed
+}
+
+// Reordered reports that the order of the elements changed wholesale,
+// for example after Sort or Reverse.
+type Reordered struct{}
+
+// Batch groups the events produced by a single bulk operation such as
+// FromSlice or Merge.
+type Batch struct {
+	Events []Event // Events in the order they happened
+}
+
+func (Inserted) event()  {}
+func (Removed) event()   {}
+func (Updated) event()   {}
+func (Moved) event()     {}
+func (Cleared) event()   {}
+func (Reordered) event() {}
+func (Batch) event()     {}
@@ -0,0 +1,162 @@
+package event
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// subscriber is a single registered listener.
+type subscriber struct {
+	deliver func(Event)
+	active  bool // Guarded by the hub's mutex
+}
+
+// Hub fans events out to subscribers. Its zero value is ready to use.
+//
+// Emit, Begin and End must be called from one goroutine at a time, the one
+// that mutates the list owning the hub. Subscribing, unsubscribing and
+// Active are safe to call from any goroutine, at any time, including while
+// an event is being delivered. The lists create their hub on the first call
+// to their own Subscribe, which therefore needs the same synchronization as
+// their other methods; the unsubscribe functions are free to run anywhere.
+//
+// Channel subscribers are delivered to with a blocking send, so a full
+// buffer stalls the mutating goroutine until the consumer catches up or
+// unsubscribes. Unsubscribing releases a send that is waiting and drops its
+// event, then closes the channel.
+type Hub struct {
+	mu      sync.Mutex
+	subs    []*subscriber
+	count   atomic.Int32 // len(subs), for Active without the lock
+	pending []Event      // Events collected while a batch is open
+	depth   int          // Nesting level of open batches
+}
+
+// Subscribe registers fn to be called synchronously for every event and
+// returns a function that unsubscribes it. Unsubscribing more than once has
+// no further effect.
+func (h *Hub) Subscribe(fn func(Event)) (unsubscribe func()) {
+	s := &subscriber{deliver: fn, active: true}
+	h.add(s)
+	return func() { h.remove(s) }
+}
+
+// SubscribeChan registers a channel subscriber with the given buffer size and
+// returns the channel together with a function that unsubscribes and closes
+// it. The function may be called from the goroutine reading the channel.
+func (h *Hub) SubscribeChan(buffer int) (<-chan Event, func()) {
+	if buffer < 0 {
+		buffer = 0
+	}
+	ch := make(chan Event, buffer)
+	done := make(chan struct{})
+	var (
+		mu      sync.Mutex // Guards closed and the Add of sending
+		closed  bool
+		sending sync.WaitGroup
+		once    sync.Once
+	)
+	s := &subscriber{deliver: func(e Event) {
+		mu.Lock()
+		if closed {
+			mu.Unlock()
+			return
+		}
+		sending.Add(1)
+		mu.Unlock()
+		defer sending.Done()
+		select {
+		case ch <- e:
+		case <-done:
+		}
+	}, active: true}
+	h.add(s)
+	return ch, func() {
+		once.Do(func() {
+			h.remove(s)
+			mu.Lock()
+			closed = true
+			mu.Unlock()
+			close(done)
+			sending.Wait()
+			close(ch)
+		})
+	}
+}
+
+// Active reports whether anyone is listening. Callers use it to skip the
+// work of building events nobody will receive.
+func (h *Hub) Active() bool {
+	return h != nil && h.count.Load() > 0
+}
+
+// Emit delivers e to every subscriber, or queues it if a batch is open.
+func (h *Hub) Emit(e Event) {
+	if h.depth > 0 {
+		h.pending = append(h.pending, e)
+		return
+	}
+	h.deliver(e)
+}
+
+// Begin opens a batch. Events emitted until the matching End are delivered
+// together as a single Batch. Batches may nest; only the outermost End
+// delivers.
+func (h *Hub) Begin() {
+	h.depth++
+}
+
+// End closes the batch opened by the matching Begin.
+func (h *Hub) End() {
+	if h.depth == 0 {
+		return
+	}
+	h.depth--
+	if h.depth > 0 || len(h.pending) == 0 {
+		return
+	}
+	events := h.pending
+	h.pending = nil
+	h.deliver(Batch{Events: events})
+}
+
+// deliver calls every active subscriber with e. The lock is not held while
+// subscribers run, so they may subscribe or unsubscribe.
+func (h *Hub) deliver(e Event) {
+	h.mu.Lock()
+	subs := append([]*subscriber(nil), h.subs...)
+	h.mu.Unlock()
+	for _, s := range subs {
+		h.mu.Lock()
+		active := s.active
+		h.mu.Unlock()
+		if active {
+			s.deliver(e)
+		}
+	}
+}
+
+// add registers s.
+func (h *Hub) add(s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs = append(h.subs, s)
+	h.count.Store(int32(len(h.subs)))
+}
+
+// remove unregisters s if it is still registered.
+func (h *Hub) remove(s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !s.active {
+		return
+	}
+	s.active = false
+	for i, sub := range h.subs {
+		if sub == s {
+			h.subs = append(h.subs[:i], h.subs[i+1:]...)
+			break
+		}
+	}
+	h.count.Store(int32(len(h.subs)))
+}
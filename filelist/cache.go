@@ -0,0 +1,56 @@
+package filelist
+
+import "github.com/JustMrNone/ll/doubly"
+
+// cacheEntry is what the cache's recency list stores.
+type cacheEntry struct {
+	offset int64
+	rec    record
+}
+
+// pageCache keeps the most recently used records in memory. The most recent
+// entry is at the tail of the recency list.
+type pageCache struct {
+	capacity int
+	order    *doubly.LinkedList
+	entries  map[int64]*doubly.Node
+}
+
+func newPageCache(capacity int) *pageCache {
+	return &pageCache{
+		capacity: capacity,
+		order:    doubly.NewDoublyLinkedList(),
+		entries:  make(map[int64]*doubly.Node),
+	}
+}
+
+// get returns the cached record at offset, if any.
+func (c *pageCache) get(offset int64) (record, bool) {
+	node, ok := c.entries[offset]
+	if !ok {
+		return record{}, false
+	}
+	c.touch(node)
+	return node.Value.(*cacheEntry).rec, true
+}
+
+// put stores rec for offset, evicting the least recently used entry when
+// the cache is full.
+func (c *pageCache) put(offset int64, rec record) {
+	if node, ok := c.entries[offset]; ok {
+		node.Value.(*cacheEntry).rec = rec
+		c.touch(node)
+		return
+	}
+	if c.order.Size >= c.capacity {
+		oldest := c.order.Head
+		delete(c.entries, oldest.Value.(*cacheEntry).offset)
+		c.order.Remove(oldest)
+	}
+	c.entries[offset] = c.order.InsertAfter(c.order.Tail, &cacheEntry{offset: offset, rec: rec})
+}
+
+// touch marks node as the most recently used entry.
+func (c *pageCache) touch(node *doubly.Node) {
+	c.order.MoveToBack(node)
+}
@@ -0,0 +1,427 @@
+// Package filelist implements a doubly linked list that lives in a file, for
+// lists that are bigger than memory. Nodes are fixed-size records addressed
+// by their file offset, and deleted records are kept on a free list for
+// reuse.
+package filelist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+
+	"github.com/JustMrNone/ll/codec"
+)
+
+// Errors returned by list operations. They may be wrapped with more
+// detail, so check for them with errors.Is.
+var (
+	ErrEmpty            = errors.New("list is empty")
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrNotFound         = errors.New("value not found")
+	ErrCorrupt          = errors.New("corrupt list file")
+)
+
+// DefaultRecordSize is the payload capacity of a record when Options leaves
+// it unset.
+const DefaultRecordSize = 256
+
+// Options configures a file-backed list.
+type Options struct {
+	RecordSize int         // Payload bytes per record for new files; existing files keep theirs
+	CacheSize  int         // Number of records kept in the page cache; 0 disables it
+	Codec      codec.Codec // Value encoding; nil means codec.JSON
+}
+
+// List is a doubly linked list stored in a file. Every change is written
+// through to the file immediately, but multi-record updates are not atomic:
+// after a crash, run Validate to find damage. It is not safe for concurrent
+// use.
+type List struct {
+	file  *os.File
+	hdr   header
+	codec codec.Codec
+	cache *pageCache
+	err   error // First error hit during iteration
+}
+
+// Open opens the list stored at path, creating the file if it does not exist.
+func Open(path string, opts Options) (*List, error) {
+	if opts.Codec == nil {
+		opts.Codec = codec.JSON
+	}
+	if opts.RecordSize == 0 {
+		opts.RecordSize = DefaultRecordSize
+	}
+	if opts.RecordSize < 0 {
+		return nil, fmt.Errorf("invalid record size %d", opts.RecordSize)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &List{file: file, codec: opts.Codec}
+	if opts.CacheSize > 0 {
+		l.cache = newPageCache(opts.CacheSize)
+	}
+
+	buf := make([]byte, headerSize)
+	n, err := file.ReadAt(buf, 0)
+	switch {
+	case n == 0 && err == io.EOF:
+		err = l.commit(header{recordSize: opts.RecordSize, end: headerSize})
+	case err != nil:
+		err = fmt.Errorf("failed to read header: %w", err)
+	default:
+		l.hdr, err = decodeHeader(buf)
+		if err == nil {
+			var info os.FileInfo
+			if info, err = file.Stat(); err == nil {
+				err = l.hdr.check(info.Size())
+			}
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append adds a value at the end of the list.
+func (l *List) Append(value any) error {
+	return l.insertBefore(0, value)
+}
+
+// Prepend adds a value at the beginning of the list.
+func (l *List) Prepend(value any) error {
+	return l.insertBefore(l.hdr.head, value)
+}
+
+// Insert adds a value at the specified index.
+func (l *List) Insert(value any, index int) error {
+	if index < 0 || int64(index) > l.hdr.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, l.hdr.size)
+	}
+	if int64(index) == l.hdr.size {
+		return l.Append(value)
+	}
+	offset, _, err := l.at(index)
+	if err != nil {
+		return err
+	}
+	return l.insertBefore(offset, value)
+}
+
+// Delete removes the first occurrence of the specified value from the list.
+// Values are compared by their encoding.
+func (l *List) Delete(value any) error {
+	if l.hdr.size == 0 {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	want, err := l.codec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	offset := l.hdr.head
+	for i := int64(0); i < l.hdr.size && offset != 0; i++ {
+		rec, err := l.read(offset)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(rec.payload, want) {
+			return l.remove(offset, rec)
+		}
+		offset = rec.next
+	}
+	return ErrNotFound
+}
+
+// DeleteAt removes the element at the specified index.
+func (l *List) DeleteAt(index int) error {
+	if index < 0 || int64(index) >= l.hdr.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, l.hdr.size)
+	}
+	offset, rec, err := l.at(index)
+	if err != nil {
+		return err
+	}
+	return l.remove(offset, rec)
+}
+
+// Get returns the value at the specified index.
+func (l *List) Get(index int) (any, error) {
+	if index < 0 || int64(index) >= l.hdr.size {
+		return nil, fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, l.hdr.size)
+	}
+	_, rec, err := l.at(index)
+	if err != nil {
+		return nil, err
+	}
+	return l.codec.Decode(rec.payload)
+}
+
+// Len returns the number of elements in the list.
+func (l *List) Len() int {
+	return int(l.hdr.size)
+}
+
+// IsEmpty returns true if the list has no elements.
+func (l *List) IsEmpty() bool {
+	return l.hdr.size == 0
+}
+
+// All returns an iterator over the indices and values of the list from head
+// to tail. Iteration stops at the first read or decode error, which Err
+// then reports.
+func (l *List) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		l.err = nil
+		index := 0
+		for offset := l.hdr.head; offset != 0; index++ {
+			if int64(index) >= l.hdr.size {
+				l.err = fmt.Errorf("%w: list has more records than its size %d", ErrCorrupt, l.hdr.size)
+				return
+			}
+			rec, err := l.read(offset)
+			if err != nil {
+				l.err = err
+				return
+			}
+			value, err := l.codec.Decode(rec.payload)
+			if err != nil {
+				l.err = fmt.Errorf("failed to decode record at offset %d: %w", offset, err)
+				return
+			}
+			if !yield(index, value) {
+				return
+			}
+			offset = rec.next
+		}
+	}
+}
+
+// Err returns the error that ended the last iteration, if any.
+func (l *List) Err() error {
+	return l.err
+}
+
+// IntoSlice converts the list into a slice.
+func (l *List) IntoSlice() ([]any, error) {
+	var values []any
+	for _, value := range l.All() {
+		values = append(values, value)
+	}
+	return values, l.Err()
+}
+
+// Sync flushes the file to stable storage.
+func (l *List) Sync() error {
+	return l.file.Sync()
+}
+
+// Close closes the underlying file.
+func (l *List) Close() error {
+	return l.file.Close()
+}
+
+// insertBefore stores value in a new record linked in front of the record at
+// offset, or at the end of the list when offset is 0.
+func (l *List) insertBefore(offset int64, value any) error {
+	payload, err := l.codec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	if len(payload) > l.hdr.recordSize {
+		return fmt.Errorf("encoded value is %d bytes, record size is %d", len(payload), l.hdr.recordSize)
+	}
+
+	newOffset, hdr, err := l.allocate()
+	if err != nil {
+		return err
+	}
+	rec := record{flags: flagUsed, next: offset, payload: payload}
+
+	if offset == 0 {
+		rec.prev = hdr.tail
+	} else {
+		at, err := l.read(offset)
+		if err != nil {
+			return err
+		}
+		rec.prev = at.prev
+		at.prev = newOffset
+		if err := l.write(offset, at); err != nil {
+			return err
+		}
+	}
+	if err := l.write(newOffset, rec); err != nil {
+		return err
+	}
+
+	if rec.prev == 0 {
+		hdr.head = newOffset
+	} else {
+		prev, err := l.read(rec.prev)
+		if err != nil {
+			return err
+		}
+		prev.next = newOffset
+		if err := l.write(rec.prev, prev); err != nil {
+			return err
+		}
+	}
+	if rec.next == 0 {
+		hdr.tail = newOffset
+	}
+	hdr.size++
+	return l.commit(hdr)
+}
+
+// remove unlinks the record at offset and puts it on the free list.
+func (l *List) remove(offset int64, rec record) error {
+	hdr := l.hdr
+	if rec.prev == 0 {
+		hdr.head = rec.next
+	} else {
+		prev, err := l.read(rec.prev)
+		if err != nil {
+			return err
+		}
+		prev.next = rec.next
+		if err := l.write(rec.prev, prev); err != nil {
+			return err
+		}
+	}
+	if rec.next == 0 {
+		hdr.tail = rec.prev
+	} else {
+		next, err := l.read(rec.next)
+		if err != nil {
+			return err
+		}
+		next.prev = rec.prev
+		if err := l.write(rec.next, next); err != nil {
+			return err
+		}
+	}
+
+	if err := l.write(offset, record{flags: flagFree, next: hdr.free}); err != nil {
+		return err
+	}
+	hdr.free = offset
+	hdr.size--
+	return l.commit(hdr)
+}
+
+// allocate returns the offset of an unused record, reusing one from the free
+// list when possible, along with a copy of the header that claims it. The
+// list's header is left alone until the caller commits the copy.
+func (l *List) allocate() (int64, header, error) {
+	hdr := l.hdr
+	if hdr.free == 0 {
+		offset := hdr.end
+		hdr.end += l.recordTotal()
+		return offset, hdr, nil
+	}
+	offset := hdr.free
+	rec, err := l.read(offset)
+	if err != nil {
+		return 0, header{}, err
+	}
+	if rec.flags != flagFree {
+		return 0, header{}, fmt.Errorf("%w: free list entry at offset %d is in use", ErrCorrupt, offset)
+	}
+	hdr.free = rec.next
+	return offset, hdr, nil
+}
+
+// at walks to the record at index from whichever end is closer.
+func (l *List) at(index int) (int64, record, error) {
+	fromTail := int64(index) > l.hdr.size/2
+	offset := l.hdr.head
+	steps := index
+	if fromTail {
+		offset = l.hdr.tail
+		steps = int(l.hdr.size) - 1 - index
+	}
+	for {
+		rec, err := l.read(offset)
+		if err != nil {
+			return 0, record{}, err
+		}
+		if steps == 0 {
+			return offset, rec, nil
+		}
+		if fromTail {
+			offset = rec.prev
+		} else {
+			offset = rec.next
+		}
+		steps--
+	}
+}
+
+// recordTotal is the on-disk size of a record including its header.
+func (l *List) recordTotal() int64 {
+	return int64(recordHeaderSize + l.hdr.recordSize)
+}
+
+// checkOffset reports whether offset addresses a record slot in the file.
+func (l *List) checkOffset(offset int64) error {
+	if offset < headerSize || offset >= l.hdr.end || (offset-headerSize)%l.recordTotal() != 0 {
+		return fmt.Errorf("%w: invalid record offset %d", ErrCorrupt, offset)
+	}
+	return nil
+}
+
+// read loads the record at offset, from the cache when possible.
+func (l *List) read(offset int64) (record, error) {
+	if err := l.checkOffset(offset); err != nil {
+		return record{}, err
+	}
+	if l.cache != nil {
+		if rec, ok := l.cache.get(offset); ok {
+			return rec, nil
+		}
+	}
+	buf := make([]byte, l.recordTotal())
+	if _, err := l.file.ReadAt(buf, offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return record{}, fmt.Errorf("%w: record at offset %d is past the end of the file", ErrCorrupt, offset)
+		}
+		return record{}, err
+	}
+	rec, err := decodeRecord(buf, l.hdr.recordSize)
+	if err != nil {
+		return record{}, fmt.Errorf("record at offset %d: %w", offset, err)
+	}
+	if l.cache != nil {
+		l.cache.put(offset, rec)
+	}
+	return rec, nil
+}
+
+// write stores rec at offset, keeping the cache in step.
+func (l *List) write(offset int64, rec record) error {
+	if _, err := l.file.WriteAt(rec.encode(l.hdr.recordSize), offset); err != nil {
+		return err
+	}
+	if l.cache != nil {
+		l.cache.put(offset, rec)
+	}
+	return nil
+}
+
+// commit stores hdr and makes it the list's header once it is on disk, so a
+// failed write leaves the header as it was before the change.
+func (l *List) commit(hdr header) error {
+	if _, err := l.file.WriteAt(hdr.encode(), 0); err != nil {
+		return err
+	}
+	l.hdr = hdr
+	return nil
+}
@@ -0,0 +1,116 @@
+package filelist
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// The file starts with a fixed header followed by fixed-size records. A
+// record's address is its byte offset in the file; offset 0 is the header,
+// so it doubles as the nil address.
+//
+//	header: magic[4] version[4] recordSize[4] reserved[4]
+//	        head[8] tail[8] size[8] free[8] end[8] reserved[8]
+//	record: flags[1] next[8] prev[8] length[4] payload[recordSize]
+const (
+	magic      = "LLFL"
+	version    = 1
+	headerSize = 64
+
+	recordHeaderSize = 1 + 8 + 8 + 4
+)
+
+// Record flags.
+const (
+	flagUsed byte = 1 // Record holds a list node
+	flagFree byte = 2 // Record is on the free list
+)
+
+// header is the in-memory copy of the file header.
+type header struct {
+	recordSize int
+	head       int64 // First node
+	tail       int64 // Last node
+	size       int64 // Number of nodes
+	free       int64 // First record on the free list
+	end        int64 // Offset where the next new record goes
+}
+
+func (h header) encode() []byte {
+	buf := make([]byte, headerSize)
+	copy(buf[0:4], magic)
+	binary.LittleEndian.PutUint32(buf[4:8], version)
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(h.recordSize))
+	binary.LittleEndian.PutUint64(buf[16:24], uint64(h.head))
+	binary.LittleEndian.PutUint64(buf[24:32], uint64(h.tail))
+	binary.LittleEndian.PutUint64(buf[32:40], uint64(h.size))
+	binary.LittleEndian.PutUint64(buf[40:48], uint64(h.free))
+	binary.LittleEndian.PutUint64(buf[48:56], uint64(h.end))
+	return buf
+}
+
+func decodeHeader(buf []byte) (header, error) {
+	if len(buf) < headerSize || string(buf[0:4]) != magic {
+		return header{}, fmt.Errorf("%w: not a list file", ErrCorrupt)
+	}
+	if v := binary.LittleEndian.Uint32(buf[4:8]); v != version {
+		return header{}, fmt.Errorf("unsupported list file version %d", v)
+	}
+	h := header{
+		recordSize: int(binary.LittleEndian.Uint32(buf[8:12])),
+		head:       int64(binary.LittleEndian.Uint64(buf[16:24])),
+		tail:       int64(binary.LittleEndian.Uint64(buf[24:32])),
+		size:       int64(binary.LittleEndian.Uint64(buf[32:40])),
+		free:       int64(binary.LittleEndian.Uint64(buf[40:48])),
+		end:        int64(binary.LittleEndian.Uint64(buf[48:56])),
+	}
+	if h.recordSize <= 0 {
+		return header{}, fmt.Errorf("%w: invalid record size %d", ErrCorrupt, h.recordSize)
+	}
+	return h, nil
+}
+
+// check verifies that the header's record area fits a file of the given
+// size, so a damaged header cannot make reads or scans run away.
+func (h header) check(fileSize int64) error {
+	total := int64(recordHeaderSize + h.recordSize)
+	if h.end < headerSize || h.end > fileSize || (h.end-headerSize)%total != 0 {
+		return fmt.Errorf("%w: header says the record area ends at %d in a file of %d bytes", ErrCorrupt, h.end, fileSize)
+	}
+	if h.size < 0 || h.size > (h.end-headerSize)/total {
+		return fmt.Errorf("%w: header size %d does not fit the record area", ErrCorrupt, h.size)
+	}
+	return nil
+}
+
+// record is a decoded node or free-list entry.
+type record struct {
+	flags   byte
+	next    int64
+	prev    int64
+	payload []byte
+}
+
+func (r record) encode(recordSize int) []byte {
+	buf := make([]byte, recordHeaderSize+recordSize)
+	buf[0] = r.flags
+	binary.LittleEndian.PutUint64(buf[1:9], uint64(r.next))
+	binary.LittleEndian.PutUint64(buf[9:17], uint64(r.prev))
+	binary.LittleEndian.PutUint32(buf[17:21], uint32(len(r.payload)))
+	copy(buf[recordHeaderSize:], r.payload)
+	return buf
+}
+
+func decodeRecord(buf []byte, recordSize int) (record, error) {
+	r := record{
+		flags: buf[0],
+		next:  int64(binary.LittleEndian.Uint64(buf[1:9])),
+		prev:  int64(binary.LittleEndian.Uint64(buf[9:17])),
+	}
+	length := int(binary.LittleEndian.Uint32(buf[17:21]))
+	if length > recordSize {
+		return record{}, fmt.Errorf("%w: payload length %d exceeds record size %d", ErrCorrupt, length, recordSize)
+	}
+	r.payload = append([]byte(nil), buf[recordHeaderSize:recordHeaderSize+length]...)
+	return r, nil
+}
@@ -0,0 +1,107 @@
+package filelist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate checks the integrity of the file like fsck: it walks the list and
+// the free list, verifies every Next/Prev pair, the header's Head, Tail and
+// Size, and reports records that are reachable from neither chain.
+func (l *List) Validate() error {
+	var problems []string
+	seen := newSlotSet(l.slots())
+
+	// Walk the list
+	count := int64(0)
+	var last int64
+	for offset := l.hdr.head; offset != 0; {
+		rec, err := l.read(offset)
+		if err != nil {
+			problems = append(problems, err.Error())
+			break
+		}
+		if !seen.add(l.slot(offset)) {
+			problems = append(problems, fmt.Sprintf("list contains a cycle at offset %d", offset))
+			break
+		}
+		count++
+		if rec.flags != flagUsed {
+			problems = append(problems, fmt.Sprintf("list record at offset %d is not marked in use", offset))
+		}
+		if rec.prev != last {
+			problems = append(problems, fmt.Sprintf("record at offset %d has prev %d, expected %d", offset, rec.prev, last))
+		}
+		last = offset
+		offset = rec.next
+	}
+	if count != l.hdr.size {
+		problems = append(problems, fmt.Sprintf("actual node count (%d) differs from Size (%d)", count, l.hdr.size))
+	}
+	if last != l.hdr.tail {
+		problems = append(problems, "tail pointer does not point to last node")
+	}
+
+	// Walk the free list
+	for offset := l.hdr.free; offset != 0; {
+		rec, err := l.read(offset)
+		if err != nil {
+			problems = append(problems, err.Error())
+			break
+		}
+		if !seen.add(l.slot(offset)) {
+			problems = append(problems, fmt.Sprintf("free list reaches offset %d twice or shares it with the list", offset))
+			break
+		}
+		if rec.flags != flagFree {
+			problems = append(problems, fmt.Sprintf("free record at offset %d is not marked free", offset))
+		}
+		offset = rec.next
+	}
+
+	// Anything else is orphaned
+	var orphans []string
+	for i := range l.slots() {
+		if !seen.has(i) {
+			orphans = append(orphans, fmt.Sprint(headerSize+i*l.recordTotal()))
+		}
+	}
+	if len(orphans) > 0 {
+		problems = append(problems, fmt.Sprintf("orphaned records at offsets %s", strings.Join(orphans, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrCorrupt, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// slots returns the number of record slots in the file.
+func (l *List) slots() int64 {
+	return (l.hdr.end - headerSize) / l.recordTotal()
+}
+
+// slot returns the slot number of a valid record offset.
+func (l *List) slot(offset int64) int64 {
+	return (offset - headerSize) / l.recordTotal()
+}
+
+// slotSet is a bitmap of record slots, one bit per record.
+type slotSet []uint64
+
+func newSlotSet(n int64) slotSet {
+	return make(slotSet, (n+63)/64)
+}
+
+func (s slotSet) has(i int64) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+// add marks slot i and reports whether it was unmarked before.
+func (s slotSet) add(i int64) bool {
+	if s.has(i) {
+		return false
+	}
+	s[i/64] |= 1 << (i % 64)
+	return true
+}
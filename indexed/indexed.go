@@ -0,0 +1,900 @@
+// Package indexed implements a list with O(log n) positional access.
+//
+// The list is an implicit treap: a binary tree kept in list order, where
+// every node records the size of its subtree so an index can be found by
+// descending from the root, and random node priorities keep the tree
+// balanced with high probability. Get, Set, Insert, DeleteAt and Move take
+// O(log n) time at any index, where the linked lists take O(n). Operations
+// that scan by value, such as Search and Delete, are O(n) as before.
+//
+// Snapshot returns a read-only View of the list in O(1). The view shares the
+// tree with the list; afterwards, the list copies every node on the path it
+// changes instead of changing it in place, so the view never sees a change.
+// Freeze makes a list permanently read-only.
+package indexed
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// Errors returned by list operations. They may be wrapped with more
+// detail, so check for them with errors.Is.
+var (
+	ErrEmpty            = errors.New("list is empty")
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrNotFound         = errors.New("value not found")
+	ErrMismatchedTypes  = errors.New("mismatched types in list")
+	ErrUnsupportedType  = errors.New("unsupported type for sorting")
+	ErrCorrupt          = errors.New("corrupt list")
+	ErrNotComparable    = errors.New("value is not comparable")
+	ErrFrozen           = errors.New("list is frozen")
+)
+
+// node is a tree node. The nodes of a subtree hold a contiguous run of the
+// list: left subtree first, then the node's own value, then the right one.
+type node struct {
+	value       any
+	left, right *node
+	size        int    // Number of nodes in the subtree rooted here
+	priority    uint64 // Heap order: no child has a higher priority
+	gen         uint64 // Generation of the list that created the node
+}
+
+// List is an indexable list. The zero value is an empty list ready to use.
+type List struct {
+	root      *node
+	observers *event.Hub // Subscribers to change events, created on demand
+
+	// A node from an earlier generation than gen may be shared with a
+	// View, so it is copied before it is changed. shared is false when no
+	// such node is left in the tree.
+	gen    uint64
+	shared bool
+	frozen bool // Set by Freeze; mutations fail with ErrFrozen
+}
+
+// NewIndexedList creates and returns an empty indexed list.
+func NewIndexedList() *List {
+	return &List{}
+}
+
+// Len returns the number of elements in the list.
+func (l *List) Len() int {
+	return size(l.root)
+}
+
+// IsEmpty returns true if the list has no elements.
+func (l *List) IsEmpty() bool {
+	return l.root == nil
+}
+
+// Prepend adds a new element with the given value at the beginning of the list.
+func (l *List) Prepend(value any) error {
+	return l.Insert(value, 0)
+}
+
+// Append adds a new element with the given value at the end of the list.
+func (l *List) Append(value any) error {
+	return l.Insert(value, l.Len())
+}
+
+// Insert adds a new value at the specified index.
+func (l *List) Insert(value any, index int) error {
+	if index < 0 || index > l.Len() {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, l.Len())
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	l.root = l.insert(l.root, index, newNode(value, l.gen))
+	emit(l, event.Inserted{Index: index, Value: value})
+	return nil
+}
+
+// Get returns the value at the specified index.
+func (l *List) Get(index int) (any, error) {
+	if index < 0 || index >= l.Len() {
+		return nil, ErrIndexOutOfBounds
+	}
+	return nodeAt(l.root, index).value, nil
+}
+
+// Set replaces the value at the specified index.
+func (l *List) Set(index int, value any) error {
+	if index < 0 || index >= l.Len() {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, l.Len())
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	old := nodeAt(l.root, index).value
+	if l.shared {
+		l.root = l.set(l.root, index, value)
+	} else {
+		nodeAt(l.root, index).value = value
+	}
+	emit(l, event.Updated{Index: index, Old: old, Value: value})
+	return nil
+}
+
+// GetMiddle returns the middle element of the list. For an even number of
+// elements it returns the first of the two middle ones, like the other lists.
+func (l *List) GetMiddle() (any, error) {
+	if l.root == nil {
+		return nil, ErrEmpty
+	}
+	return nodeAt(l.root, (l.Len()-1)/2).value, nil
+}
+
+// DeleteAt removes the element at the specified index.
+func (l *List) DeleteAt(index int) error {
+	if index < 0 || index >= l.Len() {
+		return ErrIndexOutOfBounds
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	var removed *node
+	l.root, removed = l.remove(l.root, index)
+	emit(l, event.Removed{Index: index, Value: removed.value})
+	return nil
+}
+
+// Shift removes the first element from the list.
+func (l *List) Shift() error {
+	if l.root == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	return l.DeleteAt(0)
+}
+
+// Pop removes the last element from the list.
+func (l *List) Pop() error {
+	if l.root == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	return l.DeleteAt(l.Len() - 1)
+}
+
+// Delete removes the first occurrence of the specified value from the list.
+// Deleting a value that can't be compared with ==, such as a slice, returns
+// ErrNotComparable; use DeleteFunc instead.
+func (l *List) Delete(value any) error {
+	if !equality.Comparable(value) {
+		return fmt.Errorf("%w: %T; use DeleteFunc", ErrNotComparable, value)
+	}
+	return l.DeleteFunc(func(v any) bool { return v == value })
+}
+
+// DeleteFunc removes the first value for which match returns true.
+func (l *List) DeleteFunc(match func(any) bool) error {
+	if l.root == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	index := l.IndexFunc(match)
+	if index < 0 {
+		return fmt.Errorf("%w in the list", ErrNotFound)
+	}
+	return l.DeleteAt(index)
+}
+
+// Move relocates the element at index from so that it ends up at index to.
+func (l *List) Move(from, to int) error {
+	if from < 0 || from >= l.Len() || to < 0 || to >= l.Len() {
+		return ErrIndexOutOfBounds
+	}
+	if from == to {
+		return nil
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	var n *node
+	l.root, n = l.remove(l.root, from)
+	n = l.own(n)
+	n.left, n.right, n.size = nil, nil, 1
+	l.root = l.insert(l.root, to, n)
+	emit(l, event.Moved{From: from, To: to, Value: n.value})
+	return nil
+}
+
+// Clear removes all elements from the list. It has no error result, so on a
+// frozen list it panics with ErrFrozen.
+func (l *List) Clear() {
+	if l.frozen {
+		panic(ErrFrozen)
+	}
+	count := l.Len()
+	l.root, l.shared = nil, false
+	if count > 0 {
+		emit(l, event.Cleared{Count: count})
+	}
+}
+
+// Search finds the first occurrence of a value in the list and returns its
+// index. Values such as slices and maps can't be compared with ==, so
+// searching for one returns ErrNotComparable; use SearchFunc instead.
+func (l *List) Search(value any) (int, error) {
+	if !equality.Comparable(value) {
+		return -1, fmt.Errorf("%w: %T; use SearchFunc", ErrNotComparable, value)
+	}
+	return l.SearchFunc(func(v any) bool { return v == value })
+}
+
+// SearchFunc returns the index of the first value for which match returns
+// true, or ErrNotFound.
+func (l *List) SearchFunc(match func(any) bool) (int, error) {
+	if index := l.IndexFunc(match); index >= 0 {
+		return index, nil
+	}
+	return -1, ErrNotFound
+}
+
+// IndexFunc returns the index of the first value for which match returns
+// true, or -1.
+func (l *List) IndexFunc(match func(any) bool) int {
+	for i, v := range l.All() {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexFunc returns the index of the last value for which match returns
+// true, or -1.
+func (l *List) LastIndexFunc(match func(any) bool) int {
+	for i, v := range l.Backward() {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains checks if a value exists in the list. A value that can't be
+// compared with ==, such as a slice, is never found. Unlike Search,
+// Contains does not report ErrNotComparable, to match the other lists'
+// Contains. Use ContainsFunc for such values.
+func (l *List) Contains(value any) bool {
+	_, err := l.Search(value)
+	return err == nil
+}
+
+// ContainsFunc reports whether match returns true for any value.
+func (l *List) ContainsFunc(match func(any) bool) bool {
+	return l.IndexFunc(match) >= 0
+}
+
+// All returns an iterator over the indices and values of the list from
+// first to last.
+func (l *List) All() iter.Seq2[int, any] {
+	return forward(l.root)
+}
+
+// Backward returns an iterator over the indices and values of the list from
+// last to first.
+func (l *List) Backward() iter.Seq2[int, any] {
+	return backward(l.root)
+}
+
+// Print displays the list elements from first to last.
+func (l *List) Print() {
+	fmt.Println(format(l.All()))
+}
+
+// PrintReverse displays the list elements from last to first.
+func (l *List) PrintReverse() {
+	fmt.Println(format(l.Backward()))
+}
+
+// format joins the values of seq the way the linked lists print them.
+func format(seq iter.Seq2[int, any]) string {
+	var b strings.Builder
+	first := true
+	for _, v := range seq {
+		if !first {
+			b.WriteString(" <-> ")
+		}
+		fmt.Fprint(&b, v)
+		first = false
+	}
+	return b.String()
+}
+
+// IntoSlice converts the list into a slice.
+func (l *List) IntoSlice() []any {
+	if l.root == nil {
+		return nil
+	}
+	return l.IntoArray()
+}
+
+// FromSlice creates a list from the given slice.
+func (l *List) FromSlice(slice []any) error {
+	if slice == nil {
+		return fmt.Errorf("cannot create list from nil slice")
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	l.beginBatch()
+	defer l.endBatch()
+	l.Clear()
+	l.root, l.shared = build(slice, l.gen), false
+	l.emitInserted(0, slice)
+	return nil
+}
+
+// IntoArray converts the list into a slice of exactly Len elements.
+func (l *List) IntoArray() []any {
+	arr := make([]any, 0, l.Len())
+	for _, v := range l.All() {
+		arr = append(arr, v)
+	}
+	return arr
+}
+
+// FromArray creates a list from the given array.
+func (l *List) FromArray(arr []any) error {
+	if arr == nil {
+		return fmt.Errorf("cannot create list from nil array")
+	}
+	return l.FromSlice(arr)
+}
+
+// Clone returns a shallow copy of the list: a new list holding the same
+// values, without the subscribers.
+func (l *List) Clone() *List {
+	return &List{root: build(l.IntoArray(), 0)}
+}
+
+// CloneFunc returns a copy of the list holding copy(v) for every value v,
+// for deep copies of values such as slices and pointers.
+func (l *List) CloneFunc(copy func(any) any) *List {
+	values := l.IntoArray()
+	for i, v := range values {
+		values[i] = copy(v)
+	}
+	return &List{root: build(values, 0)}
+}
+
+// Equal reports whether other holds the same values in the same order,
+// compared with ==. Values that can't be compared with ==, such as slices,
+// are never equal; use EqualFunc for them. A nil list counts as empty.
+func (l *List) Equal(other *List) bool {
+	return l.EqualFunc(other, equality.Equal)
+}
+
+// EqualFunc reports whether other holds the same number of values as the
+// list and eq returns true for every pair of values at the same index. A nil
+// list counts as empty.
+func (l *List) EqualFunc(other *List, eq func(a, b any) bool) bool {
+	if other == nil {
+		return l.Len() == 0
+	}
+	if l.Len() != other.Len() {
+		return false
+	}
+	return slices.EqualFunc(l.IntoArray(), other.IntoArray(), eq)
+}
+
+// Compare compares the list with other lexicographically, using cmp to
+// compare values. It returns the result of cmp for the first pair of values
+// that differ, or, when one list is a prefix of the other, -1 if the list is
+// the shorter one and +1 if other is. It returns 0 if the lists are equal. A
+// nil list counts as empty.
+func (l *List) Compare(other *List, cmp func(a, b any) int) int {
+	var values []any
+	if other != nil {
+		values = other.IntoArray()
+	}
+	return slices.CompareFunc(l.IntoArray(), values, cmp)
+}
+
+// Merge appends the elements of another list to the current one.
+func (l *List) Merge(list *List) error {
+	if list == nil {
+		return fmt.Errorf("cannot merge with nil list")
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	// Copy the values first so merging a list into itself works
+	values := list.IntoArray()
+	start := l.Len()
+	l.beginBatch()
+	defer l.endBatch()
+	l.root = l.join(l.root, build(values, l.gen))
+	l.emitInserted(start, values)
+	return nil
+}
+
+// Reverse reverses the order of elements in the list.
+func (l *List) Reverse() error {
+	if l.root == nil {
+		return fmt.Errorf("cannot reverse: %w", ErrEmpty)
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	if l.Len() <= 1 {
+		return nil
+	}
+	l.ownAll()
+	mirror(l.root)
+	emit(l, event.Reordered{})
+	return nil
+}
+
+// Unique removes duplicate values from the list. If any value can't be
+// compared with ==, such as a slice, it returns ErrNotComparable and leaves
+// the list unchanged; use UniqueBy instead.
+func (l *List) Unique() error {
+	return l.UniqueBy(nil)
+}
+
+// UniqueBy removes every value whose key, as returned by key, matches the
+// key of an earlier value. Keys must be comparable with ==; a nil key
+// function uses the values themselves.
+func (l *List) UniqueBy(key func(any) any) error {
+	if l.root == nil {
+		return ErrEmpty
+	}
+	if l.frozen {
+		return ErrFrozen
+	}
+	values := l.IntoArray()
+	keys := values
+	if key != nil {
+		keys = make([]any, len(values))
+		for i, v := range values {
+			keys[i] = key(v)
+		}
+	}
+	for i, k := range keys {
+		if !equality.Comparable(k) {
+			return fmt.Errorf("%w: %T at index %d", ErrNotComparable, k, i)
+		}
+	}
+
+	kept := values[:0:0]
+	visited := make(map[any]bool)
+	l.beginBatch()
+	defer l.endBatch()
+	for i, v := range values {
+		if visited[keys[i]] {
+			emit(l, event.Removed{Index: len(kept), Value: v})
+			continue
+		}
+		visited[keys[i]] = true
+		kept = append(kept, v)
+	}
+	if len(kept) < len(values) {
+		l.root, l.shared = build(kept, l.gen), false
+	}
+	return nil
+}
+
+// Sort orders the elements in the list (supports int, string, float64). The
+// sort is stable, and a list of mixed or unsupported types is left unchanged.
+func (l *List) Sort() error {
+	if l.frozen {
+		return ErrFrozen
+	}
+	if l.Len() <= 1 {
+		return nil
+	}
+	values := l.IntoArray()
+	var compare func(a, b any) int
+	switch values[0].(type) {
+	case int:
+		compare = compareAs[int]
+	case string:
+		compare = compareAs[string]
+	case float64:
+		compare = compareAs[float64]
+	default:
+		return ErrUnsupportedType
+	}
+	for _, v := range values[1:] {
+		if reflect.TypeOf(v) != reflect.TypeOf(values[0]) {
+			return ErrMismatchedTypes
+		}
+	}
+	if slices.IsSortedFunc(values, compare) {
+		return nil
+	}
+	slices.SortStableFunc(values, compare)
+	l.ownAll()
+	i := 0
+	walk(l.root, func(n *node) {
+		n.value = values[i]
+		i++
+	})
+	emit(l, event.Reordered{})
+	return nil
+}
+
+// compareAs compares two values of the same ordered type T.
+func compareAs[T cmp.Ordered](a, b any) int {
+	return cmp.Compare(a.(T), b.(T))
+}
+
+// Validate checks the integrity of the tree: every subtree size must be
+// right and no node may have a higher priority than its parent.
+func (l *List) Validate() error {
+	_, err := validate(l.root)
+	return err
+}
+
+// validate checks the subtree rooted at n and returns its node count.
+func validate(n *node) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+	for _, child := range []*node{n.left, n.right} {
+		if child != nil && child.priority > n.priority {
+			return 0, fmt.Errorf("%w: child has a higher priority than its parent", ErrCorrupt)
+		}
+	}
+	left, err := validate(n.left)
+	if err != nil {
+		return 0, err
+	}
+	right, err := validate(n.right)
+	if err != nil {
+		return 0, err
+	}
+	if count := left + right + 1; count != n.size {
+		return 0, fmt.Errorf("%w: subtree holds %d nodes but records size %d", ErrCorrupt, count, n.size)
+	}
+	return n.size, nil
+}
+
+// Subscribe registers fn to be called after every change to the list and
+// returns a function that unsubscribes it. Bulk operations such as FromSlice,
+// Merge and Unique deliver their changes as a single event.Batch.
+//
+// Subscribe creates the list's event hub on first use, so like the other
+// methods it must not run concurrently with changes to the list. The
+// function it returns may be called from any goroutine.
+func (l *List) Subscribe(fn func(event.Event)) (unsubscribe func()) {
+	if l.observers == nil {
+		l.observers = &event.Hub{}
+	}
+	return l.observers.Subscribe(fn)
+}
+
+// SubscribeChan is like Subscribe but delivers events on a channel with the
+// given buffer size. Mutations block while the buffer is full. Unsubscribing
+// closes the channel.
+func (l *List) SubscribeChan(buffer int) (<-chan event.Event, func()) {
+	if l.observers == nil {
+		l.observers = &event.Hub{}
+	}
+	return l.observers.SubscribeChan(buffer)
+}
+
+// newNode returns a single node tree of generation gen holding value.
+func newNode(value any, gen uint64) *node {
+	return &node{value: value, size: 1, priority: rand.Uint64(), gen: gen}
+}
+
+// size returns the number of nodes in the subtree rooted at n.
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recomputes n's size from its children.
+func update(n *node) {
+	n.size = size(n.left) + size(n.right) + 1
+}
+
+// nodeAt returns the node at index, which must be in range, in the subtree
+// rooted at n.
+func nodeAt(n *node, index int) *node {
+	for {
+		left := size(n.left)
+		switch {
+		case index < left:
+			n = n.left
+		case index > left:
+			index -= left + 1
+			n = n.right
+		default:
+			return n
+		}
+	}
+}
+
+// own returns n if it belongs to the list's current generation, and
+// otherwise a copy of it that does. Nodes are owned before they are changed,
+// so a node shared with a View never is.
+func (l *List) own(n *node) *node {
+	if n.gen == l.gen {
+		return n
+	}
+	c := *n
+	c.gen = l.gen
+	return &c
+}
+
+// ownAll owns every node of the tree, for operations that change all of it.
+func (l *List) ownAll() {
+	if !l.shared {
+		return
+	}
+	var own func(n *node) *node
+	own = func(n *node) *node {
+		if n == nil {
+			return nil
+		}
+		n = l.own(n)
+		n.left, n.right = own(n.left), own(n.right)
+		return n
+	}
+	l.root = own(l.root)
+	l.shared = false
+}
+
+// set replaces the value at index, which must be in range, in the subtree
+// rooted at n, copying the path to it, and returns the new root.
+func (l *List) set(n *node, index int, value any) *node {
+	n = l.own(n)
+	switch left := size(n.left); {
+	case index < left:
+		n.left = l.set(n.left, index, value)
+	case index > left:
+		n.right = l.set(n.right, index-left-1, value)
+	default:
+		n.value = value
+	}
+	return n
+}
+
+// split divides the subtree rooted at n into the first k nodes and the rest.
+func (l *List) split(n *node, k int) (*node, *node) {
+	if n == nil {
+		return nil, nil
+	}
+	n = l.own(n)
+	if k <= size(n.left) {
+		left, right := l.split(n.left, k)
+		n.left = right
+		update(n)
+		return left, n
+	}
+	left, right := l.split(n.right, k-size(n.left)-1)
+	n.right = left
+	update(n)
+	return n, right
+}
+
+// join concatenates two subtrees, keeping the heap order.
+func (l *List) join(a, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a = l.own(a)
+		a.right = l.join(a.right, b)
+		update(a)
+		return a
+	}
+	b = l.own(b)
+	b.left = l.join(a, b.left)
+	update(b)
+	return b
+}
+
+// insert places the single node m, which the list owns, at index in the
+// subtree rooted at n and returns the new root.
+func (l *List) insert(n *node, index int, m *node) *node {
+	if n == nil {
+		return m
+	}
+	if m.priority > n.priority {
+		m.left, m.right = l.split(n, index)
+		update(m)
+		return m
+	}
+	n = l.own(n)
+	if left := size(n.left); index <= left {
+		n.left = l.insert(n.left, index, m)
+	} else {
+		n.right = l.insert(n.right, index-left-1, m)
+	}
+	update(n)
+	return n
+}
+
+// remove takes the node at index out of the subtree rooted at n and returns
+// the new root and the removed node. The removed node may still be shared.
+func (l *List) remove(n *node, index int) (*node, *node) {
+	left := size(n.left)
+	if index == left {
+		return l.join(n.left, n.right), n
+	}
+	n = l.own(n)
+	var removed *node
+	if index < left {
+		n.left, removed = l.remove(n.left, index)
+	} else {
+		n.right, removed = l.remove(n.right, index-left-1)
+	}
+	update(n)
+	return n, removed
+}
+
+// build returns a tree holding values in order. It assigns priorities first
+// and builds the Cartesian tree for them in linear time, keeping the
+// rightmost path on a stack.
+func build(values []any, gen uint64) *node {
+	var stack []*node
+	for _, v := range values {
+		n := newNode(v, gen)
+		var last *node
+		for len(stack) > 0 && stack[len(stack)-1].priority < n.priority {
+			last = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		}
+		n.left = last
+		if len(stack) > 0 {
+			stack[len(stack)-1].right = n
+		}
+		stack = append(stack, n)
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	resize(stack[0])
+	return stack[0]
+}
+
+// forward returns an iterator over the subtree rooted at root in list order.
+func forward(root *node) iter.Seq2[int, any] {
+	return forwardRange(root, 0, size(root))
+}
+
+// forwardRange returns an iterator over count nodes of the subtree rooted at
+// root, starting at index from, which must be in range. It yields indices
+// counted from from. Only the path down to from is walked before the first
+// value, so it takes O(log n + count) time.
+func forwardRange(root *node, from, count int) iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		if count <= 0 {
+			return
+		}
+		// The stack holds the nodes still to visit whose right subtrees
+		// follow them, nearest first.
+		var stack []*node
+		for n, index := root, from; n != nil; {
+			left := size(n.left)
+			switch {
+			case index < left:
+				stack = append(stack, n)
+				n = n.left
+			case index > left:
+				index -= left + 1
+				n = n.right
+			default:
+				stack = append(stack, n)
+				n = nil
+			}
+		}
+		for i := 0; i < count && len(stack) > 0; i++ {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(i, n.value) {
+				return
+			}
+			for n = n.right; n != nil; n = n.left {
+				stack = append(stack, n)
+			}
+		}
+	}
+}
+
+// backward returns an iterator over the subtree rooted at root in reverse
+// list order.
+func backward(root *node) iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		index := size(root) - 1
+		var stack []*node
+		for n := root; n != nil || len(stack) > 0; {
+			for ; n != nil; n = n.right {
+				stack = append(stack, n)
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(index, n.value) {
+				return
+			}
+			index--
+			n = n.left
+		}
+	}
+}
+
+// resize recomputes the sizes of every node in the subtree rooted at n and
+// returns n's.
+func resize(n *node) int {
+	if n == nil {
+		return 0
+	}
+	n.size = resize(n.left) + resize(n.right) + 1
+	return n.size
+}
+
+// mirror reverses the subtree rooted at n by swapping children throughout.
+func mirror(n *node) {
+	if n == nil {
+		return
+	}
+	n.left, n.right = n.right, n.left
+	mirror(n.left)
+	mirror(n.right)
+}
+
+// walk calls fn on every node of the subtree rooted at n in list order.
+func walk(n *node, fn func(*node)) {
+	if n == nil {
+		return
+	}
+	walk(n.left, fn)
+	fn(n)
+	walk(n.right, fn)
+}
+
+// emitInserted reports values as inserted one after another from start.
+func (l *List) emitInserted(start int, values []any) {
+	if !l.observers.Active() {
+		return
+	}
+	for i, v := range values {
+		emit(l, event.Inserted{Index: start + i, Value: v})
+	}
+}
+
+// emit sends e to the list's subscribers, if there are any. It is generic
+// so that e is only boxed into an event.Event, which allocates, when someone
+// is listening.
+func emit[E event.Event](l *List, e E) {
+	if l.observers.Active() {
+		l.observers.Emit(e)
+	}
+}
+
+// beginBatch starts collecting events for a bulk operation.
+func (l *List) beginBatch() {
+	if l.observers != nil {
+		l.observers.Begin()
+	}
+}
+
+// endBatch delivers the events collected since beginBatch.
+func (l *List) endBatch() {
+	if l.observers != nil {
+		l.observers.End()
+	}
+}
@@ -0,0 +1,94 @@
+package indexed
+
+import (
+	"iter"
+
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// View is a read-only copy of a list as it was when Snapshot was called.
+// Later changes to the list don't show in the view. A view is safe for
+// concurrent use by any number of readers.
+type View struct {
+	root *node
+}
+
+// Snapshot returns a View of the list's current contents in O(1). The list
+// keeps working as before, but the first change to any part of the tree the
+// view shares copies that part, O(log n) nodes per change, and Reverse and
+// Sort copy the whole tree once.
+//
+// The list itself is not safe for concurrent use, and Snapshot counts as a
+// change to it: callers that share a list between goroutines must hold the
+// same lock around Snapshot as around every other call. Only reading the
+// returned View needs no lock.
+func (l *List) Snapshot() *View {
+	if !l.frozen && l.root != nil {
+		l.gen++
+		l.shared = true
+	}
+	return &View{root: l.root}
+}
+
+// Freeze makes the list permanently read-only. Every method that would
+// change it returns ErrFrozen instead, except Clear, which panics with it. A
+// frozen list shares its tree with snapshots without copying. Freeze counts
+// as a change and needs the same lock as one; once it has returned, the
+// list is safe for concurrent reads without a lock.
+func (l *List) Freeze() {
+	l.frozen = true
+}
+
+// Frozen reports whether Freeze has been called on the list. Before the list
+// is frozen it is a read like any other and must not race with changes.
+func (l *List) Frozen() bool {
+	return l.frozen
+}
+
+// Len returns the number of elements in the view.
+func (v *View) Len() int {
+	return size(v.root)
+}
+
+// Get returns the value at the specified index.
+func (v *View) Get(index int) (any, error) {
+	if index < 0 || index >= v.Len() {
+		return nil, ErrIndexOutOfBounds
+	}
+	return nodeAt(v.root, index).value, nil
+}
+
+// Contains checks if a value exists in the view. A value that can't be
+// compared with ==, such as a slice, is never found, as with List.Contains.
+func (v *View) Contains(value any) bool {
+	if !equality.Comparable(value) {
+		return false
+	}
+	for _, x := range v.All() {
+		if x == value {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns an iterator over the indices and values of the view from
+// first to last.
+func (v *View) All() iter.Seq2[int, any] {
+	return forward(v.root)
+}
+
+// Backward returns an iterator over the indices and values of the view from
+// last to first.
+func (v *View) Backward() iter.Seq2[int, any] {
+	return backward(v.root)
+}
+
+// IntoSlice returns the values of the view as a slice.
+func (v *View) IntoSlice() []any {
+	values := make([]any, 0, v.Len())
+	for _, x := range v.All() {
+		values = append(values, x)
+	}
+	return values
+}
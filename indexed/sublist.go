@@ -0,0 +1,121 @@
+package indexed
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/JustMrNone/ll/internal/paging"
+)
+
+// ErrInvalidCursor is returned by Page for a cursor it did not issue, or one
+// past the end of the list. It is the singly and doubly lists' error too.
+var ErrInvalidCursor = paging.ErrInvalidCursor
+
+// Sublist is a live window onto a run of an indexed list: it reads the
+// list's current values and writes through to it, in O(log n) per access.
+// It holds indices, so inserting or deleting before the window shifts which
+// values it covers. A sublist of a frozen list can still be read, and its
+// changes fail with ErrFrozen like the list's own.
+type Sublist struct {
+	list *List
+	span paging.Span
+}
+
+// Range returns a sublist of the values from index from up to, but not
+// including, index to.
+func (l *List) Range(from, to int) (*Sublist, error) {
+	span, ok := paging.NewSpan(from, to, l.Len())
+	if !ok {
+		return nil, fmt.Errorf("%w: range [%d, %d), size %d", ErrIndexOutOfBounds, from, to, l.Len())
+	}
+	return &Sublist{list: l, span: span}, nil
+}
+
+// Len returns the number of values in the sublist, which shrinks if the list
+// loses the values at its end.
+func (s *Sublist) Len() int {
+	return s.span.Len(s.list.Len())
+}
+
+// Get returns the value at index within the sublist.
+func (s *Sublist) Get(index int) (any, error) {
+	pos, ok := s.span.Pos(index, s.list.Len())
+	if !ok {
+		return nil, s.outOfBounds(index)
+	}
+	return s.list.Get(pos)
+}
+
+// Set replaces the value at index within the sublist.
+func (s *Sublist) Set(index int, value any) error {
+	pos, ok := s.span.Pos(index, s.list.Len())
+	if !ok {
+		return s.outOfBounds(index)
+	}
+	return s.list.Set(pos, value)
+}
+
+// Insert adds a value at index within the sublist, which grows by one.
+// Inserting at Len places the value right after the sublist's last one.
+func (s *Sublist) Insert(value any, index int) error {
+	n := s.Len()
+	pos, ok := s.span.InsertPos(index, s.list.Len())
+	if !ok {
+		return s.outOfBounds(index)
+	}
+	if err := s.list.Insert(value, pos); err != nil {
+		return err
+	}
+	s.span.Resize(n + 1)
+	return nil
+}
+
+// DeleteAt removes the value at index within the sublist, which shrinks by
+// one.
+func (s *Sublist) DeleteAt(index int) error {
+	n := s.Len()
+	pos, ok := s.span.Pos(index, s.list.Len())
+	if !ok {
+		return s.outOfBounds(index)
+	}
+	if err := s.list.DeleteAt(pos); err != nil {
+		return err
+	}
+	s.span.Resize(n - 1)
+	return nil
+}
+
+// All returns an iterator over the indices within the sublist and values,
+// from first to last. It descends the tree once to the first value, so
+// reading a sublist of k values takes O(log n + k).
+func (s *Sublist) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		// Read the root when iteration starts, not when All is called
+		forwardRange(s.list.root, s.span.From, s.Len())(yield)
+	}
+}
+
+// IntoSlice returns the values of the sublist as a slice.
+func (s *Sublist) IntoSlice() []any {
+	values := make([]any, 0, s.Len())
+	for _, v := range s.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// outOfBounds returns the error for an index outside the sublist.
+func (s *Sublist) outOfBounds(index int) error {
+	return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, s.Len())
+}
+
+// Page returns up to limit values starting at cursor, and the cursor of the
+// value after them, with the same cursor rules as the doubly list's Page.
+// Finding the cursor's position takes O(log n), so paging through the whole
+// list is O(n) overall.
+func (l *List) Page(cursor string, limit int) ([]any, string, error) {
+	return paging.Page(cursor, limit, l.Len(), func(from, to int) []any {
+		page, _ := l.Range(from, to)
+		return page.IntoSlice()
+	})
+}
@@ -0,0 +1,408 @@
+// Package bench defines the benchmark workloads shared by the test package
+// and cmd/llbench, so both measure exactly the same thing.
+//
+// A Case is one operation on one element type at one list size, run against
+// one implementation: the singly and doubly lists, on their own or with a
+// pool or arena allocator, or the container/list and []T baselines.
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/JustMrNone/ll/alloc"
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// Sizes are the list lengths benchmarked by default.
+var Sizes = []int{10, 100, 1000}
+
+// Impl is a benchmarked list implementation.
+type Impl struct {
+	Name string
+	New  func(t Type) List // Returns an empty list for elements of type t
+}
+
+// Impls are the implementations benchmarked by default. The baselines come
+// last so reports read from the lists under test to the reference points.
+var Impls = []Impl{
+	{Name: "singly", New: func(Type) List { return newSingly(nil) }},
+	{Name: "singly-pool", New: func(Type) List { return newSingly(alloc.NewPool[singly.Node]()) }},
+	{Name: "singly-arena", New: func(Type) List { return newSingly(alloc.NewArena[singly.Node](0)) }},
+	{Name: "doubly", New: func(Type) List { return newDoubly(nil) }},
+	{Name: "doubly-pool", New: func(Type) List { return newDoubly(alloc.NewPool[doubly.Node]()) }},
+	{Name: "doubly-arena", New: func(Type) List { return newDoubly(alloc.NewArena[doubly.Node](0)) }},
+	{Name: "indexed", New: func(Type) List { return newIndexed() }},
+	{Name: "stdlist", New: newStdList},
+	{Name: "slice", New: func(t Type) List { return t.newSlice() }},
+}
+
+// Type is an element type stored in the lists.
+type Type struct {
+	Name    string
+	Value   func(i int) any    // Returns the i'th of a series of distinct values
+	Compare func(a, b any) int // Orders values; nil when the lists cannot sort them
+
+	newSlice func() List
+}
+
+// record is a struct element, larger than a word and not sortable by the lists.
+type record struct {
+	ID    int
+	Name  string
+	Score float64
+}
+
+// Types are the element types benchmarked by default.
+var Types = []Type{
+	newType("int", func(i int) int { return i }, cmp.Compare[int]),
+	newType("string", func(i int) string { return fmt.Sprintf("value-%06d", i) }, cmp.Compare[string]),
+	newType("struct", func(i int) record { return record{ID: i, Name: strconv.Itoa(i), Score: float64(i) / 2} }, nil),
+}
+
+// newType describes element type T, whose []T baseline uses compare to sort.
+func newType[T comparable](name string, value func(i int) T, compare func(a, b T) int) Type {
+	t := Type{
+		Name:     name,
+		Value:    func(i int) any { return value(i) },
+		newSlice: func() List { return &sliceList[T]{compare: compare} },
+	}
+	if compare != nil {
+		t.Compare = func(a, b any) int { return compare(a.(T), b.(T)) }
+	}
+	return t
+}
+
+// env is the state an operation works on.
+type env struct {
+	list, other List
+	values      []any // Size distinct values in order
+	shuffled    []any // The same values in a fixed random order
+	missing     any   // A value of the same type that is not in values
+	positions   []int // Fixed random indices, with positions[i] <= i
+	newList     func() List
+}
+
+// fill returns a new list holding values.
+func (e *env) fill(values []any) List {
+	l := e.newList()
+	for _, v := range values {
+		l.Append(v)
+	}
+	return l
+}
+
+// Op is a benchmarked operation.
+type Op struct {
+	Name  string
+	Doc   string // What one iteration does
+	Sorts bool   // Whether the element type must be orderable
+
+	fresh bool // Whether every iteration needs the list set up again
+	setup func(e *env)
+	run   func(e *env)
+}
+
+// Ops are the benchmarked operations. Ops that consume or build a whole list
+// time all Size calls in one iteration; the others time a single call.
+var Ops = []Op{
+	{
+		Name:  "Append",
+		Doc:   "append Size values to an empty list",
+		fresh: true,
+		setup: func(e *env) { e.list = e.newList() },
+		run: func(e *env) {
+			for _, v := range e.values {
+				e.list.Append(v)
+			}
+		},
+	},
+	{
+		Name:  "Prepend",
+		Doc:   "prepend Size values to an empty list",
+		fresh: true,
+		setup: func(e *env) { e.list = e.newList() },
+		run: func(e *env) {
+			for _, v := range e.values {
+				e.list.Prepend(v)
+			}
+		},
+	},
+	{
+		Name:  "Insert",
+		Doc:   "insert Size values, each in the middle of the growing list",
+		fresh: true,
+		setup: func(e *env) { e.list = e.newList() },
+		run: func(e *env) {
+			for i, v := range e.values {
+				e.list.Insert(v, i/2)
+			}
+		},
+	},
+	{
+		Name:  "InsertRandom",
+		Doc:   "insert Size values, each at a random index of the growing list",
+		fresh: true,
+		setup: func(e *env) { e.list = e.newList() },
+		run: func(e *env) {
+			for i, v := range e.values {
+				e.list.Insert(v, e.positions[i])
+			}
+		},
+	},
+	{
+		Name:  "FromSlice",
+		Doc:   "build a list from a slice of Size values",
+		fresh: true,
+		setup: func(e *env) { e.list = e.newList() },
+		run:   func(e *env) { e.list.FromSlice(e.values) },
+	},
+	{
+		Name:  "Merge",
+		Doc:   "merge a list of Size values into an empty list",
+		fresh: true,
+		setup: func(e *env) { e.list, e.other = e.newList(), e.fill(e.values) },
+		run:   func(e *env) { e.list.Merge(e.other) },
+	},
+	{
+		Name:  "Get",
+		Doc:   "get the middle element by index",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.Get(e.list.Len() / 2) },
+	},
+	{
+		Name:  "GetAll",
+		Doc:   "get every element by index, in order",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for i := range e.values {
+				e.list.Get(i)
+			}
+		},
+	},
+	{
+		Name:  "GetMiddle",
+		Doc:   "get the middle element",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.GetMiddle() },
+	},
+	{
+		Name:  "Search",
+		Doc:   "search for the last value",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.Search(e.values[len(e.values)-1]) },
+	},
+	{
+		Name:  "Contains",
+		Doc:   "look for a value that is not in the list",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.Contains(e.missing) },
+	},
+	{
+		Name:  "Walk",
+		Doc:   "visit every element in order",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.Walk() },
+	},
+	{
+		Name:  "IntoSlice",
+		Doc:   "copy the list into a []any",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.IntoSlice() },
+	},
+	{
+		Name:  "Reverse",
+		Doc:   "reverse the list in place",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.Reverse() },
+	},
+	{
+		Name:  "Move",
+		Doc:   "move the first element to the end",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run:   func(e *env) { e.list.Move(0, e.list.Len()-1) },
+	},
+	{
+		Name:  "Shift",
+		Doc:   "empty a list of Size values from the front",
+		fresh: true,
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for range e.values {
+				e.list.Shift()
+			}
+		},
+	},
+	{
+		Name:  "Pop",
+		Doc:   "empty a list of Size values from the back",
+		fresh: true,
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for range e.values {
+				e.list.Pop()
+			}
+		},
+	},
+	{
+		Name:  "DeleteAt",
+		Doc:   "empty a list of Size values by deleting the middle index",
+		fresh: true,
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for range e.values {
+				e.list.DeleteAt(e.list.Len() / 2)
+			}
+		},
+	},
+	{
+		Name:  "DeleteAtRandom",
+		Doc:   "empty a list of Size values by deleting a random index",
+		fresh: true,
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for i := len(e.values) - 1; i >= 0; i-- {
+				e.list.DeleteAt(e.positions[i])
+			}
+		},
+	},
+	{
+		Name:  "Delete",
+		Doc:   "empty a list of Size values by deleting each value, last first",
+		fresh: true,
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for i := len(e.values) - 1; i >= 0; i-- {
+				e.list.Delete(e.values[i])
+			}
+		},
+	},
+	{
+		Name:  "Queue",
+		Doc:   "append and shift Size times on a list of Size values",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			for _, v := range e.values {
+				e.list.Append(v)
+				e.list.Shift()
+			}
+		},
+	},
+	{
+		Name:  "Refill",
+		Doc:   "clear a list of Size values and append them again",
+		setup: func(e *env) { e.list = e.fill(e.values) },
+		run: func(e *env) {
+			e.list.Clear()
+			for _, v := range e.values {
+				e.list.Append(v)
+			}
+		},
+	},
+	{
+		Name:  "Sort",
+		Doc:   "sort Size values in random order",
+		Sorts: true,
+		fresh: true,
+		setup: func(e *env) { e.list = e.fill(e.shuffled) },
+		run:   func(e *env) { e.list.Sort() },
+	},
+	{
+		Name:  "Unique",
+		Doc:   "remove duplicates from Size values holding each value twice",
+		fresh: true,
+		setup: func(e *env) {
+			half := (len(e.values) + 1) / 2
+			e.list = e.newList()
+			for i := range e.values {
+				e.list.Append(e.values[i%half])
+			}
+		},
+		run: func(e *env) { e.list.Unique() },
+	},
+}
+
+// Case is one operation on one element type and size, run against one
+// implementation.
+type Case struct {
+	Op   Op
+	Type Type
+	Size int
+	Impl Impl
+}
+
+// Name returns the case's sub-benchmark name, Op/type/size/impl.
+func (c Case) Name() string {
+	return fmt.Sprintf("%s/%s/%d/%s", c.Op.Name, c.Type.Name, c.Size, c.Impl.Name)
+}
+
+// Cases returns every combination of ops, types, sizes and impls, skipping
+// sorts of types the lists cannot order. Sizes must be positive.
+func Cases(ops []Op, types []Type, sizes []int, impls []Impl) []Case {
+	var cases []Case
+	for _, op := range ops {
+		for _, t := range types {
+			if op.Sorts && t.Compare == nil {
+				continue
+			}
+			for _, size := range sizes {
+				for _, impl := range impls {
+					cases = append(cases, Case{Op: op, Type: t, Size: size, Impl: impl})
+				}
+			}
+		}
+	}
+	return cases
+}
+
+// Result runs the case once and returns what the list then holds, so tests
+// can check that every implementation does the same work.
+func (c Case) Result() []any {
+	e := c.env()
+	c.Op.setup(e)
+	c.Op.run(e)
+	return e.list.IntoSlice()
+}
+
+// Benchmark runs the case. Setting up a fresh list between iterations is
+// not timed and its allocations are not counted.
+func (c Case) Benchmark(b *testing.B) {
+	e := c.env()
+	c.Op.setup(e)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if c.Op.fresh && i > 0 {
+			b.StopTimer()
+			c.Op.setup(e)
+			b.StartTimer()
+		}
+		c.Op.run(e)
+	}
+}
+
+// env returns the values the case works on.
+func (c Case) env() *env {
+	e := &env{
+		values:  make([]any, c.Size),
+		missing: c.Type.Value(c.Size),
+		newList: func() List { return c.Impl.New(c.Type) },
+	}
+	for i := range e.values {
+		e.values[i] = c.Type.Value(i)
+	}
+	rng := rand.New(rand.NewPCG(1, uint64(c.Size)))
+	e.shuffled = make([]any, c.Size)
+	for i, j := range rng.Perm(c.Size) {
+		e.shuffled[i] = e.values[j]
+	}
+	e.positions = make([]int, c.Size)
+	for i := range e.positions {
+		e.positions[i] = rng.IntN(i + 1)
+	}
+	return e
+}
@@ -0,0 +1,437 @@
+package bench
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+
+	"github.com/JustMrNone/ll/alloc"
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/indexed"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// List is the interface every benchmarked implementation is driven through.
+// Workloads never cause errors, so implementations panic on them rather than
+// making every benchmark check.
+type List interface {
+	Len() int
+	Append(value any)
+	Prepend(value any)
+	Insert(value any, index int)
+	FromSlice(values []any)
+	Merge(other List)
+	Get(index int) any
+	GetMiddle() any
+	Search(value any) int
+	Contains(value any) bool
+	Walk()
+	IntoSlice() []any
+	Reverse()
+	Move(from, to int)
+	Shift()
+	Pop()
+	DeleteAt(index int)
+	Delete(value any)
+	Sort()
+	Unique()
+	Clear()
+}
+
+// must panics with err, which means a workload is broken.
+func must(err error) {
+	if err != nil {
+		panic(fmt.Sprintf("bench: unexpected error: %v", err))
+	}
+}
+
+// singlyList drives a singly linked list, using an allocator if it was
+// created with one.
+type singlyList struct {
+	l    *singly.LinkedList
+	last any // Written by Walk so the loop is not optimized away
+}
+
+func newSingly(a alloc.Allocator[singly.Node]) List {
+	l := singly.NewSinglyLinkedList()
+	l.Allocator = a
+	return &singlyList{l: l}
+}
+
+func (s *singlyList) Len() int                    { return s.l.Size }
+func (s *singlyList) Append(value any)            { s.l.Append(value) }
+func (s *singlyList) Prepend(value any)           { s.l.Prepend(value) }
+func (s *singlyList) Insert(value any, index int) { must(s.l.Insert(value, index)) }
+func (s *singlyList) FromSlice(values []any)      { must(s.l.FromSlice(values)) }
+func (s *singlyList) Merge(other List)            { must(s.l.Merge(other.(*singlyList).l)) }
+func (s *singlyList) Search(value any) int        { return s.l.Search(value) }
+func (s *singlyList) Contains(value any) bool     { return s.l.Contains(value) }
+func (s *singlyList) IntoSlice() []any            { return s.l.IntoSlice() }
+func (s *singlyList) Reverse()                    { s.l.Reverse() }
+func (s *singlyList) Move(from, to int)           { must(s.l.Move(from, to)) }
+func (s *singlyList) Shift()                      { must(s.l.Shift()) }
+func (s *singlyList) Pop()                        { must(s.l.Pop()) }
+func (s *singlyList) DeleteAt(index int)          { must(s.l.DeleteAt(index)) }
+func (s *singlyList) Delete(value any)            { must(s.l.Delete(value)) }
+func (s *singlyList) Sort()                       { must(s.l.Sort()) }
+func (s *singlyList) Unique()                     { must(s.l.Unique()) }
+func (s *singlyList) Clear()                      { s.l.Clear() }
+
+func (s *singlyList) Get(index int) any {
+	value, err := s.l.Get(index)
+	must(err)
+	return value
+}
+
+func (s *singlyList) GetMiddle() any {
+	value, err := s.l.GetMiddle()
+	must(err)
+	return value
+}
+
+func (s *singlyList) Walk() {
+	for _, value := range s.l.All() {
+		s.last = value
+	}
+}
+
+// doublyList drives a doubly linked list, using an allocator if it was
+// created with one.
+type doublyList struct {
+	l    *doubly.LinkedList
+	last any
+}
+
+func newDoubly(a alloc.Allocator[doubly.Node]) List {
+	l := doubly.NewDoublyLinkedList()
+	l.Allocator = a
+	return &doublyList{l: l}
+}
+
+func (d *doublyList) Len() int                    { return d.l.Size }
+func (d *doublyList) Append(value any)            { must(d.l.Append(value)) }
+func (d *doublyList) Prepend(value any)           { must(d.l.Prepend(value)) }
+func (d *doublyList) Insert(value any, index int) { must(d.l.Insert(value, index)) }
+func (d *doublyList) FromSlice(values []any)      { must(d.l.FromSlice(values)) }
+func (d *doublyList) Merge(other List)            { must(d.l.Merge(other.(*doublyList).l)) }
+func (d *doublyList) Contains(value any) bool     { return d.l.Contains(value) }
+func (d *doublyList) IntoSlice() []any            { return d.l.IntoSlice() }
+func (d *doublyList) Reverse()                    { must(d.l.Reverse()) }
+func (d *doublyList) Move(from, to int)           { must(d.l.Move(from, to)) }
+func (d *doublyList) Shift()                      { must(d.l.Shift()) }
+func (d *doublyList) Pop()                        { must(d.l.Pop()) }
+func (d *doublyList) DeleteAt(index int)          { must(d.l.DeleteAt(index)) }
+func (d *doublyList) Delete(value any)            { must(d.l.Delete(value)) }
+func (d *doublyList) Sort()                       { must(d.l.Sort()) }
+func (d *doublyList) Unique()                     { must(d.l.Unique()) }
+func (d *doublyList) Clear()                      { d.l.Clear() }
+
+func (d *doublyList) Get(index int) any {
+	value, err := d.l.Get(index)
+	must(err)
+	return value
+}
+
+func (d *doublyList) GetMiddle() any {
+	value, err := d.l.GetMiddle()
+	must(err)
+	return value
+}
+
+func (d *doublyList) Search(value any) int {
+	index, err := d.l.Search(value)
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+func (d *doublyList) Walk() {
+	for _, value := range d.l.All() {
+		d.last = value
+	}
+}
+
+// indexedList drives an indexed list.
+type indexedList struct {
+	l    *indexed.List
+	last any
+}
+
+func newIndexed() List {
+	return &indexedList{l: indexed.NewIndexedList()}
+}
+
+func (x *indexedList) Len() int                    { return x.l.Len() }
+func (x *indexedList) Append(value any)            { must(x.l.Append(value)) }
+func (x *indexedList) Prepend(value any)           { must(x.l.Prepend(value)) }
+func (x *indexedList) Insert(value any, index int) { must(x.l.Insert(value, index)) }
+func (x *indexedList) FromSlice(values []any)      { must(x.l.FromSlice(values)) }
+func (x *indexedList) Merge(other List)            { must(x.l.Merge(other.(*indexedList).l)) }
+func (x *indexedList) Contains(value any) bool     { return x.l.Contains(value) }
+func (x *indexedList) IntoSlice() []any            { return x.l.IntoSlice() }
+func (x *indexedList) Reverse()                    { must(x.l.Reverse()) }
+func (x *indexedList) Move(from, to int)           { must(x.l.Move(from, to)) }
+func (x *indexedList) Shift()                      { must(x.l.Shift()) }
+func (x *indexedList) Pop()                        { must(x.l.Pop()) }
+func (x *indexedList) DeleteAt(index int)          { must(x.l.DeleteAt(index)) }
+func (x *indexedList) Delete(value any)            { must(x.l.Delete(value)) }
+func (x *indexedList) Sort()                       { must(x.l.Sort()) }
+func (x *indexedList) Unique()                     { must(x.l.Unique()) }
+func (x *indexedList) Clear()                      { x.l.Clear() }
+
+func (x *indexedList) Get(index int) any {
+	value, err := x.l.Get(index)
+	must(err)
+	return value
+}
+
+func (x *indexedList) GetMiddle() any {
+	value, err := x.l.GetMiddle()
+	must(err)
+	return value
+}
+
+func (x *indexedList) Search(value any) int {
+	index, err := x.l.Search(value)
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+func (x *indexedList) Walk() {
+	for _, value := range x.l.All() {
+		x.last = value
+	}
+}
+
+// stdList is the container/list baseline. Operations the package lacks are
+// written the way a caller would write them.
+type stdList struct {
+	l       *list.List
+	compare func(a, b any) int
+	last    any
+}
+
+func newStdList(t Type) List {
+	return &stdList{l: list.New(), compare: t.Compare}
+}
+
+func (s *stdList) Len() int           { return s.l.Len() }
+func (s *stdList) Append(value any)   { s.l.PushBack(value) }
+func (s *stdList) Prepend(value any)  { s.l.PushFront(value) }
+func (s *stdList) Merge(other List)   { s.l.PushBackList(other.(*stdList).l) }
+func (s *stdList) Get(index int) any  { return s.at(index).Value }
+func (s *stdList) GetMiddle() any     { return s.at((s.l.Len() - 1) / 2).Value }
+func (s *stdList) Shift()             { s.l.Remove(s.l.Front()) }
+func (s *stdList) Pop()               { s.l.Remove(s.l.Back()) }
+func (s *stdList) DeleteAt(index int) { s.l.Remove(s.at(index)) }
+func (s *stdList) Clear()             { s.l.Init() }
+
+// at returns the element at index, walking from the nearer end.
+func (s *stdList) at(index int) *list.Element {
+	if index < 0 || index >= s.l.Len() {
+		panic(fmt.Sprintf("bench: index %d out of range for length %d", index, s.l.Len()))
+	}
+	if index < s.l.Len()/2 {
+		e := s.l.Front()
+		for ; index > 0; index-- {
+			e = e.Next()
+		}
+		return e
+	}
+	e := s.l.Back()
+	for i := s.l.Len() - 1; i > index; i-- {
+		e = e.Prev()
+	}
+	return e
+}
+
+func (s *stdList) Insert(value any, index int) {
+	if index == s.l.Len() {
+		s.l.PushBack(value)
+		return
+	}
+	s.l.InsertBefore(value, s.at(index))
+}
+
+func (s *stdList) FromSlice(values []any) {
+	s.l.Init()
+	for _, value := range values {
+		s.l.PushBack(value)
+	}
+}
+
+func (s *stdList) Search(value any) int {
+	index := 0
+	for e := s.l.Front(); e != nil; e = e.Next() {
+		if e.Value == value {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+func (s *stdList) Contains(value any) bool {
+	return s.Search(value) >= 0
+}
+
+func (s *stdList) Walk() {
+	for e := s.l.Front(); e != nil; e = e.Next() {
+		s.last = e.Value
+	}
+}
+
+func (s *stdList) IntoSlice() []any {
+	values := make([]any, 0, s.l.Len())
+	for e := s.l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+func (s *stdList) Reverse() {
+	for e := s.l.Front(); e != nil; {
+		next := e.Next()
+		s.l.MoveToFront(e)
+		e = next
+	}
+}
+
+func (s *stdList) Move(from, to int) {
+	if from == to {
+		return
+	}
+	e, mark := s.at(from), s.at(to)
+	if from < to {
+		s.l.MoveAfter(e, mark)
+	} else {
+		s.l.MoveBefore(e, mark)
+	}
+}
+
+func (s *stdList) Delete(value any) {
+	for e := s.l.Front(); e != nil; e = e.Next() {
+		if e.Value == value {
+			s.l.Remove(e)
+			return
+		}
+	}
+	panic(fmt.Sprintf("bench: value %v not found", value))
+}
+
+// Sort sorts a copy of the values and writes them back.
+func (s *stdList) Sort() {
+	values := s.IntoSlice()
+	slices.SortFunc(values, s.compare)
+	e := s.l.Front()
+	for _, value := range values {
+		e.Value = value
+		e = e.Next()
+	}
+}
+
+func (s *stdList) Unique() {
+	seen := make(map[any]bool)
+	for e := s.l.Front(); e != nil; {
+		next := e.Next()
+		if seen[e.Value] {
+			s.l.Remove(e)
+		} else {
+			seen[e.Value] = true
+		}
+		e = next
+	}
+}
+
+// sliceList is the []T baseline. Values arrive as any and are stored as T,
+// so it pays for boxing whatever it hands back as any, as in Get.
+type sliceList[T comparable] struct {
+	s       []T
+	compare func(a, b T) int
+	last    T
+}
+
+func (s *sliceList[T]) Len() int                    { return len(s.s) }
+func (s *sliceList[T]) Append(value any)            { s.s = append(s.s, value.(T)) }
+func (s *sliceList[T]) Prepend(value any)           { s.s = slices.Insert(s.s, 0, value.(T)) }
+func (s *sliceList[T]) Insert(value any, index int) { s.s = slices.Insert(s.s, index, value.(T)) }
+func (s *sliceList[T]) Merge(other List)            { s.s = append(s.s, other.(*sliceList[T]).s...) }
+func (s *sliceList[T]) Get(index int) any           { return s.s[index] }
+func (s *sliceList[T]) GetMiddle() any              { return s.s[(len(s.s)-1)/2] }
+func (s *sliceList[T]) Search(value any) int        { return slices.Index(s.s, value.(T)) }
+func (s *sliceList[T]) Contains(value any) bool     { return slices.Contains(s.s, value.(T)) }
+func (s *sliceList[T]) Reverse()                    { slices.Reverse(s.s) }
+func (s *sliceList[T]) Sort()                       { slices.SortFunc(s.s, s.compare) }
+func (s *sliceList[T]) DeleteAt(index int)          { s.s = slices.Delete(s.s, index, index+1) }
+
+func (s *sliceList[T]) FromSlice(values []any) {
+	s.s = make([]T, len(values))
+	for i, value := range values {
+		s.s[i] = value.(T)
+	}
+}
+
+func (s *sliceList[T]) Clear() {
+	clear(s.s)
+	s.s = s.s[:0]
+}
+
+func (s *sliceList[T]) Walk() {
+	for _, value := range s.s {
+		s.last = value
+	}
+}
+
+func (s *sliceList[T]) IntoSlice() []any {
+	values := make([]any, len(s.s))
+	for i, value := range s.s {
+		values[i] = value
+	}
+	return values
+}
+
+func (s *sliceList[T]) Move(from, to int) {
+	value := s.s[from]
+	if from < to {
+		copy(s.s[from:to], s.s[from+1:to+1])
+	} else {
+		copy(s.s[to+1:from+1], s.s[to:from])
+	}
+	s.s[to] = value
+}
+
+// Shift reslices past the first element, the usual way to use a slice as a
+// queue.
+func (s *sliceList[T]) Shift() {
+	var zero T
+	s.s[0] = zero
+	s.s = s.s[1:]
+}
+
+func (s *sliceList[T]) Pop() {
+	var zero T
+	s.s[len(s.s)-1] = zero
+	s.s = s.s[:len(s.s)-1]
+}
+
+func (s *sliceList[T]) Delete(value any) {
+	index := slices.Index(s.s, value.(T))
+	if index < 0 {
+		panic(fmt.Sprintf("bench: value %v not found", value))
+	}
+	s.s = slices.Delete(s.s, index, index+1)
+}
+
+func (s *sliceList[T]) Unique() {
+	seen := make(map[T]bool, len(s.s))
+	s.s = slices.DeleteFunc(s.s, func(value T) bool {
+		if seen[value] {
+			return true
+		}
+		seen[value] = true
+		return false
+	})
+}
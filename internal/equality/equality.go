@@ -0,0 +1,22 @@
+// Package equality compares the lists' values with == without panicking on
+// values, such as slices and maps, that == can't handle.
+package equality
+
+import "reflect"
+
+// Comparable reports whether v can be compared with == and used as a map key
+// without panicking.
+func Comparable(v any) bool {
+	switch v.(type) {
+	case nil, int, string, float64, bool:
+		return true
+	}
+	return reflect.ValueOf(v).Comparable()
+}
+
+// Equal reports whether a == b, treating values that can't be compared as
+// unequal instead of panicking. Checking a is enough: if b has the same type
+// and is not comparable, neither is a.
+func Equal(a, b any) bool {
+	return Comparable(a) && a == b
+}
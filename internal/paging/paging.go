@@ -0,0 +1,69 @@
+// Package paging encodes the opaque position tokens that the lists' Page
+// methods hand out, so every list issues and accepts the same cursors.
+package paging
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned for a cursor that Page did not issue, or one
+// that points past the end of the list. The list packages export it under
+// the same name.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// prefix marks the cursors Page issues, so a token from elsewhere is
+// rejected rather than read as a position.
+const prefix = "p"
+
+// Encode returns the cursor for a position.
+func Encode(pos int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(prefix + strconv.Itoa(pos)))
+}
+
+// Decode returns the position a cursor holds. The empty cursor is position
+// 0.
+func Decode(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), prefix) {
+		return 0, ErrInvalidCursor
+	}
+	pos, err := strconv.Atoi(strings.TrimPrefix(string(data), prefix))
+	if err != nil || pos < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return pos, nil
+}
+
+// Bounds returns the range of positions the page at cursor covers in a list
+// of size values: from the cursor's position up to limit values further, or
+// the end of the list.
+func Bounds(cursor string, limit, size int) (from, to int, err error) {
+	if limit < 1 {
+		return 0, 0, fmt.Errorf("invalid page limit %d", limit)
+	}
+	from, err = Decode(cursor)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from > size {
+		return 0, 0, fmt.Errorf("%w: position %d is past the end of the list", ErrInvalidCursor, from)
+	}
+	return from, min(from+limit, size), nil
+}
+
+// Page returns the values of the page at cursor in a list of size values,
+// read with values, and the cursor of the page after it.
+func Page(cursor string, limit, size int, values func(from, to int) []any) ([]any, string, error) {
+	from, to, err := Bounds(cursor, limit, size)
+	if err != nil {
+		return nil, "", err
+	}
+	return values(from, to), Encode(to), nil
+}
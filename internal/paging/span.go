@@ -0,0 +1,46 @@
+package paging
+
+// Span is the run of positions [From, To) a live sublist covers. The list
+// can change size under it, so the methods take the list's current size,
+// and a span over a list that has shrunk covers only the positions left.
+type Span struct {
+	From, To int
+}
+
+// NewSpan returns the span [from, to) of a list of size values. It reports
+// false if the span doesn't fit in the list.
+func NewSpan(from, to, size int) (Span, bool) {
+	if from < 0 || to < from || to > size {
+		return Span{}, false
+	}
+	return Span{From: from, To: to}, true
+}
+
+// Len returns the number of positions the span covers.
+func (s Span) Len(size int) int {
+	return max(min(s.To, size)-s.From, 0)
+}
+
+// Pos returns the list position of index within the span. It reports false
+// if index is not in the span.
+func (s Span) Pos(index, size int) (int, bool) {
+	if index < 0 || index >= s.Len(size) {
+		return 0, false
+	}
+	return s.From + index, true
+}
+
+// InsertPos is like Pos, but also accepts index Len, the position right
+// after the span's last value.
+func (s Span) InsertPos(index, size int) (int, bool) {
+	if index < 0 || index > s.Len(size) {
+		return 0, false
+	}
+	return s.From + index, true
+}
+
+// Resize makes the span n positions long, after a value was inserted into
+// or deleted from it.
+func (s *Span) Resize(n int) {
+	s.To = s.From + n
+}
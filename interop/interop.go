@@ -0,0 +1,65 @@
+// Package interop converts between singly and doubly linked lists and
+// compares one with the other.
+package interop
+
+import (
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/internal/equality"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// ToDoubly returns a doubly linked list holding the values of list in the
+// same order. A nil list gives an empty one.
+func ToDoubly(list *singly.LinkedList) *doubly.LinkedList {
+	out := doubly.NewDoublyLinkedList()
+	if list != nil {
+		for current := list.Head; current != nil; current = current.Next {
+			out.Append(current.Value)
+		}
+	}
+	return out
+}
+
+// ToSingly returns a singly linked list holding the values of list in the
+// same order. A nil list gives an empty one.
+func ToSingly(list *doubly.LinkedList) *singly.LinkedList {
+	out := singly.NewSinglyLinkedList()
+	if list != nil {
+		// Prepend from the tail, since Append on a singly list walks it
+		for current := list.Tail; current != nil; current = current.Prev {
+			out.Prepend(current.Value)
+		}
+	}
+	return out
+}
+
+// Equal reports whether s and d hold the same values in the same order,
+// compared with ==. Values that can't be compared with ==, such as slices,
+// are never equal; use EqualFunc for them. A nil list counts as empty.
+func Equal(s *singly.LinkedList, d *doubly.LinkedList) bool {
+	return EqualFunc(s, d, equality.Equal)
+}
+
+// EqualFunc reports whether s and d hold the same number of values and eq
+// returns true for every pair of values at the same index. A nil list counts
+// as empty.
+func EqualFunc(s *singly.LinkedList, d *doubly.LinkedList, eq func(a, b any) bool) bool {
+	var a *singly.Node
+	var b *doubly.Node
+	sizeA, sizeB := 0, 0
+	if s != nil {
+		a, sizeA = s.Head, s.Size
+	}
+	if d != nil {
+		b, sizeB = d.Head, d.Size
+	}
+	if sizeA != sizeB {
+		return false
+	}
+	for ; a != nil && b != nil; a, b = a.Next, b.Next {
+		if !eq(a.Value, b.Value) {
+			return false
+		}
+	}
+	return a == nil && b == nil
+}
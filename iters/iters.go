@@ -0,0 +1,111 @@
+// Package iters adapts the iterators the lists return, such as All and
+// Backward, for batching and paging: Chunk and Window group values into
+// slices, and Skip and Take cut an iterator short.
+//
+// The functions are generic over the key type, so they work with the index
+// and value pairs of the lists as well as with the key and value pairs of an
+// ordered.Map. None of them reads further ahead than it needs to, so they
+// can be used on long or unbounded iterators.
+package iters
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Chunk returns an iterator over consecutive slices of up to n values of
+// seq. Every slice has n values except possibly the last. Each slice is new,
+// so it may be kept. Chunk panics if n is less than 1.
+func Chunk[K, V any](seq iter.Seq2[K, V], n int) iter.Seq[[]V] {
+	if n < 1 {
+		panic(fmt.Sprintf("iters.Chunk: n = %d, must be at least 1", n))
+	}
+	return func(yield func([]V) bool) {
+		var chunk []V
+		for _, v := range seq {
+			if chunk == nil {
+				chunk = make([]V, 0, n)
+			}
+			chunk = append(chunk, v)
+			if len(chunk) == n {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window returns an iterator over every run of n consecutive values of seq,
+// each one starting a value later than the one before. A sequence shorter
+// than n yields nothing. Each slice is new, so it may be kept. Window panics
+// if n is less than 1.
+func Window[K, V any](seq iter.Seq2[K, V], n int) iter.Seq[[]V] {
+	if n < 1 {
+		panic(fmt.Sprintf("iters.Window: n = %d, must be at least 1", n))
+	}
+	return func(yield func([]V) bool) {
+		// buf holds the last n values, oldest first, in a ring starting at
+		// start once it is full
+		buf := make([]V, 0, n)
+		start := 0
+		for _, v := range seq {
+			if len(buf) < n {
+				buf = append(buf, v)
+				if len(buf) < n {
+					continue
+				}
+			} else {
+				buf[start] = v
+				start = (start + 1) % n
+			}
+			window := make([]V, 0, n)
+			window = append(window, buf[start:]...)
+			window = append(window, buf[:start]...)
+			if !yield(window) {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns an iterator over the pairs of seq after the first n. The keys
+// are passed through, so the indices of a list stay the list's own.
+func Skip[K, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		skipped := 0
+		for k, v := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns an iterator over the first n pairs of seq. It stops seq as
+// soon as it has them.
+func Take[K, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for k, v := range seq {
+			if !yield(k, v) {
+				return
+			}
+			taken++
+			if taken == n {
+				return
+			}
+		}
+	}
+}
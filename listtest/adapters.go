@@ -0,0 +1,129 @@
+package listtest
+
+import (
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/indexed"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// SinglyErrors are the sentinel errors of the singly package.
+var SinglyErrors = Errors{
+	Empty:            singly.ErrEmpty,
+	IndexOutOfBounds: singly.ErrIndexOutOfBounds,
+	NotFound:         singly.ErrNotFound,
+	MismatchedTypes:  singly.ErrMismatchedTypes,
+	UnsupportedType:  singly.ErrUnsupportedType,
+	NotComparable:    singly.ErrNotComparable,
+
+	SearchIgnoresNotComparable: true,
+}
+
+// DoublyErrors are the sentinel errors of the doubly package.
+var DoublyErrors = Errors{
+	Empty:            doubly.ErrEmpty,
+	IndexOutOfBounds: doubly.ErrIndexOutOfBounds,
+	NotFound:         doubly.ErrNotFound,
+	MismatchedTypes:  doubly.ErrMismatchedTypes,
+	UnsupportedType:  doubly.ErrUnsupportedType,
+	NotComparable:    doubly.ErrNotComparable,
+}
+
+// IndexedErrors are the sentinel errors of the indexed package.
+var IndexedErrors = Errors{
+	Empty:            indexed.ErrEmpty,
+	IndexOutOfBounds: indexed.ErrIndexOutOfBounds,
+	NotFound:         indexed.ErrNotFound,
+	MismatchedTypes:  indexed.ErrMismatchedTypes,
+	UnsupportedType:  indexed.ErrUnsupportedType,
+	NotComparable:    indexed.ErrNotComparable,
+}
+
+// NewSingly returns an empty singly linked list behind the List interface.
+func NewSingly() List {
+	return Singly{singly.NewSinglyLinkedList()}
+}
+
+// NewDoubly returns an empty doubly linked list behind the List interface.
+func NewDoubly() List {
+	return Doubly{doubly.NewDoublyLinkedList()}
+}
+
+// NewIndexed returns an empty indexed list behind the List interface.
+func NewIndexed() List {
+	return Indexed{indexed.NewIndexedList()}
+}
+
+// Singly adapts a singly linked list to List.
+type Singly struct {
+	*singly.LinkedList
+}
+
+func (l Singly) Len() int {
+	return l.Size
+}
+
+func (l Singly) Append(value any) error {
+	l.LinkedList.Append(value)
+	return nil
+}
+
+func (l Singly) Prepend(value any) error {
+	l.LinkedList.Prepend(value)
+	return nil
+}
+
+// Search reports a missing value as ErrNotFound, like the doubly list.
+func (l Singly) Search(value any) (int, error) {
+	if index := l.LinkedList.Search(value); index >= 0 {
+		return index, nil
+	}
+	return -1, singly.ErrNotFound
+}
+
+// SearchFunc reports a missing value as ErrNotFound, like the doubly list.
+func (l Singly) SearchFunc(match func(any) bool) (int, error) {
+	if index := l.LinkedList.SearchFunc(match); index >= 0 {
+		return index, nil
+	}
+	return -1, singly.ErrNotFound
+}
+
+func (l Singly) Reverse() error {
+	l.LinkedList.Reverse()
+	return nil
+}
+
+func (l Singly) Merge(other List) error {
+	if other == nil {
+		return l.LinkedList.Merge(nil)
+	}
+	return l.LinkedList.Merge(other.(Singly).LinkedList)
+}
+
+// Doubly adapts a doubly linked list to List.
+type Doubly struct {
+	*doubly.LinkedList
+}
+
+func (l Doubly) Len() int {
+	return l.Size
+}
+
+func (l Doubly) Merge(other List) error {
+	if other == nil {
+		return l.LinkedList.Merge(nil)
+	}
+	return l.LinkedList.Merge(other.(Doubly).LinkedList)
+}
+
+// Indexed adapts an indexed list to List.
+type Indexed struct {
+	*indexed.List
+}
+
+func (l Indexed) Merge(other List) error {
+	if other == nil {
+		return l.List.Merge(nil)
+	}
+	return l.List.Merge(other.(Indexed).List)
+}
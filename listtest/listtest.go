@@ -0,0 +1,612 @@
+// Package listtest is a conformance suite for list implementations. Every
+// list in this module, and any new one, runs the same table-driven checks of
+// each method's edge cases and of the structural invariants.
+//
+// A test for a new implementation adapts it to List and calls Run:
+//
+//	func TestConformance(t *testing.T) {
+//		listtest.Run(t, listtest.NewDoubly, listtest.DoublyErrors)
+//	}
+package listtest
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+	"testing"
+)
+
+// List is the common behaviour the suite checks. Adapters paper over small
+// signature differences between implementations without changing their
+// semantics.
+type List interface {
+	Len() int
+	IsEmpty() bool
+	Append(value any) error
+	Prepend(value any) error
+	Insert(value any, index int) error
+	Delete(value any) error
+	DeleteAt(index int) error
+	Shift() error
+	Pop() error
+	Clear()
+	Get(index int) (any, error)
+	Search(value any) (int, error)
+	SearchFunc(match func(any) bool) (int, error)
+	IndexFunc(match func(any) bool) int
+	LastIndexFunc(match func(any) bool) int
+	Contains(value any) bool
+	ContainsFunc(match func(any) bool) bool
+	DeleteFunc(match func(any) bool) error
+	UniqueBy(key func(any) any) error
+	Move(from, to int) error
+	Sort() error
+	Reverse() error
+	Unique() error
+	Merge(other List) error
+	GetMiddle() (any, error)
+	FromSlice(values []any) error
+	IntoSlice() []any
+	All() iter.Seq2[int, any]
+	Validate() error
+}
+
+// Errors holds an implementation's sentinel errors so the suite can check
+// that failures have the right kind. A nil field only requires a non-nil
+// error.
+type Errors struct {
+	Empty            error
+	IndexOutOfBounds error
+	NotFound         error
+	MismatchedTypes  error
+	UnsupportedType  error
+	NotComparable    error
+
+	// SearchIgnoresNotComparable marks a Search that has no way to report
+	// NotComparable and returns NotFound for such values instead, like the
+	// singly list's.
+	SearchIgnoresNotComparable bool
+}
+
+// suite carries what each check needs.
+type suite struct {
+	newList func() List
+	errs    Errors
+}
+
+// Run runs the conformance suite against lists made by newList, which must
+// return a new empty list on every call.
+func Run(t *testing.T, newList func() List, errs Errors) {
+	s := suite{newList: newList, errs: errs}
+	for _, c := range []struct {
+		name string
+		fn   func(*testing.T, suite)
+	}{
+		{"Empty", testEmpty},
+		{"AppendPrepend", testAppendPrepend},
+		{"Insert", testInsert},
+		{"Get", testGet},
+		{"DeleteAt", testDeleteAt},
+		{"Delete", testDelete},
+		{"ShiftPop", testShiftPop},
+		{"SearchContains", testSearchContains},
+		{"Funcs", testFuncs},
+		{"NotComparable", testNotComparable},
+		{"Move", testMove},
+		{"Reverse", testReverse},
+		{"Sort", testSort},
+		{"Unique", testUnique},
+		{"Merge", testMerge},
+		{"GetMiddle", testGetMiddle},
+		{"Slices", testSlices},
+		{"All", testAll},
+	} {
+		t.Run(c.name, func(t *testing.T) { c.fn(t, s) })
+	}
+}
+
+// build returns a list holding values.
+func (s suite) build(t *testing.T, values ...any) List {
+	t.Helper()
+	l := s.newList()
+	for _, v := range values {
+		if err := l.Append(v); err != nil {
+			t.Fatalf("Append(%v) failed: %v", v, err)
+		}
+	}
+	return l
+}
+
+// check verifies that l holds exactly want and that its structure is valid.
+func check(t *testing.T, l List, want ...any) {
+	t.Helper()
+	if err := l.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	got := l.IntoSlice()
+	if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if l.Len() != len(want) {
+		t.Fatalf("Expected Len %d, got %d", len(want), l.Len())
+	}
+	if l.IsEmpty() != (len(want) == 0) {
+		t.Fatalf("IsEmpty is %v for %v", l.IsEmpty(), want)
+	}
+}
+
+// expectErr fails unless err has the given kind, or is at least non-nil when
+// the kind is unknown.
+func expectErr(t *testing.T, op string, err, kind error) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("%s: expected an error, got nil", op)
+	}
+	if kind != nil && !errors.Is(err, kind) {
+		t.Fatalf("%s: expected %v, got %v", op, kind, err)
+	}
+}
+
+// expectOK fails if err is not nil.
+func expectOK(t *testing.T, op string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", op, err)
+	}
+}
+
+func testEmpty(t *testing.T, s suite) {
+	l := s.newList()
+	check(t, l)
+	_, err := l.Get(0)
+	expectErr(t, "Get(0)", err, s.errs.IndexOutOfBounds)
+	expectErr(t, "DeleteAt(0)", l.DeleteAt(0), s.errs.IndexOutOfBounds)
+	expectErr(t, "Shift", l.Shift(), s.errs.Empty)
+	expectErr(t, "Pop", l.Pop(), s.errs.Empty)
+	expectErr(t, "Delete", l.Delete(1), s.errs.Empty)
+	expectErr(t, "Unique", l.Unique(), s.errs.Empty)
+	_, err = l.GetMiddle()
+	expectErr(t, "GetMiddle", err, s.errs.Empty)
+	_, err = l.Search(1)
+	expectErr(t, "Search", err, s.errs.NotFound)
+	expectErr(t, "Move(0, 0)", l.Move(0, 0), s.errs.IndexOutOfBounds)
+	if l.Contains(1) {
+		t.Fatal("Contains(1) is true on an empty list")
+	}
+	expectOK(t, "Sort", l.Sort())
+	l.Clear()
+	check(t, l)
+
+	// Reverse may report the empty list but must leave it intact
+	if err := l.Reverse(); err != nil {
+		expectErr(t, "Reverse", err, s.errs.Empty)
+	}
+	check(t, l)
+}
+
+func testAppendPrepend(t *testing.T, s suite) {
+	l := s.newList()
+	expectOK(t, "Append(2)", l.Append(2))
+	expectOK(t, "Prepend(1)", l.Prepend(1))
+	expectOK(t, "Append(3)", l.Append(3))
+	expectOK(t, "Prepend(0)", l.Prepend(0))
+	check(t, l, 0, 1, 2, 3)
+
+	l.Clear()
+	check(t, l)
+	expectOK(t, "Append after Clear", l.Append("x"))
+	check(t, l, "x")
+}
+
+func testInsert(t *testing.T, s suite) {
+	cases := []struct {
+		name  string
+		index int
+		want  []any
+	}{
+		{"Front", 0, []any{9, 1, 2, 3}},
+		{"Middle", 1, []any{1, 9, 2, 3}},
+		{"BeforeLast", 2, []any{1, 2, 9, 3}},
+		{"End", 3, []any{1, 2, 3, 9}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := s.build(t, 1, 2, 3)
+			expectOK(t, "Insert", l.Insert(9, c.index))
+			check(t, l, c.want...)
+		})
+	}
+
+	for _, index := range []int{-1, 4} {
+		l := s.build(t, 1, 2, 3)
+		expectErr(t, "Insert out of range", l.Insert(9, index), s.errs.IndexOutOfBounds)
+		check(t, l, 1, 2, 3)
+	}
+
+	l := s.newList()
+	expectOK(t, "Insert into empty list", l.Insert(1, 0))
+	check(t, l, 1)
+}
+
+func testGet(t *testing.T, s suite) {
+	l := s.build(t, "a", "b", "c")
+	for i, want := range []any{"a", "b", "c"} {
+		got, err := l.Get(i)
+		expectOK(t, "Get", err)
+		if got != want {
+			t.Fatalf("Get(%d): expected %v, got %v", i, want, got)
+		}
+	}
+	for _, index := range []int{-1, 3, 4} {
+		_, err := l.Get(index)
+		expectErr(t, "Get out of range", err, s.errs.IndexOutOfBounds)
+	}
+	check(t, l, "a", "b", "c")
+}
+
+func testDeleteAt(t *testing.T, s suite) {
+	cases := []struct {
+		index int
+		want  []any
+	}{
+		{0, []any{2, 3, 4}},
+		{1, []any{1, 3, 4}},
+		{3, []any{1, 2, 3}},
+	}
+	for _, c := range cases {
+		l := s.build(t, 1, 2, 3, 4)
+		expectOK(t, "DeleteAt", l.DeleteAt(c.index))
+		check(t, l, c.want...)
+	}
+
+	for _, index := range []int{-1, 4, 5} {
+		l := s.build(t, 1, 2, 3, 4)
+		expectErr(t, "DeleteAt out of range", l.DeleteAt(index), s.errs.IndexOutOfBounds)
+		check(t, l, 1, 2, 3, 4)
+	}
+
+	l := s.build(t, 1)
+	expectOK(t, "DeleteAt only element", l.DeleteAt(0))
+	check(t, l)
+	expectOK(t, "Append after emptying", l.Append(2))
+	check(t, l, 2)
+}
+
+func testDelete(t *testing.T, s suite) {
+	cases := []struct {
+		name  string
+		value any
+		want  []any
+	}{
+		{"Head", 1, []any{2, 3, 2}},
+		{"FirstOccurrence", 2, []any{1, 3, 2}},
+		{"Middle", 3, []any{1, 2, 2}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := s.build(t, 1, 2, 3, 2)
+			expectOK(t, "Delete", l.Delete(c.value))
+			check(t, l, c.want...)
+		})
+	}
+
+	l := s.build(t, 1, 2, 3)
+	expectOK(t, "Delete tail", l.Delete(3))
+	check(t, l, 1, 2)
+	expectOK(t, "Append after deleting tail", l.Append(4))
+	check(t, l, 1, 2, 4)
+
+	expectErr(t, "Delete missing", l.Delete(9), s.errs.NotFound)
+	check(t, l, 1, 2, 4)
+
+	// Values of different types never match
+	expectErr(t, "Delete with other type", l.Delete(int64(1)), s.errs.NotFound)
+	check(t, l, 1, 2, 4)
+}
+
+func testShiftPop(t *testing.T, s suite) {
+	l := s.build(t, 1, 2, 3, 4)
+	expectOK(t, "Shift", l.Shift())
+	check(t, l, 2, 3, 4)
+	expectOK(t, "Pop", l.Pop())
+	check(t, l, 2, 3)
+	expectOK(t, "Pop", l.Pop())
+	check(t, l, 2)
+	expectOK(t, "Shift", l.Shift())
+	check(t, l)
+	expectErr(t, "Shift on empty", l.Shift(), s.errs.Empty)
+	expectErr(t, "Pop on empty", l.Pop(), s.errs.Empty)
+
+	l = s.build(t, 1)
+	expectOK(t, "Pop only element", l.Pop())
+	check(t, l)
+	expectOK(t, "Prepend after Pop", l.Prepend(5))
+	expectOK(t, "Append after Pop", l.Append(6))
+	check(t, l, 5, 6)
+}
+
+func testSearchContains(t *testing.T, s suite) {
+	l := s.build(t, "a", "b", "a", 1.5)
+	for _, c := range []struct {
+		value any
+		index int
+	}{{"a", 0}, {"b", 1}, {1.5, 3}} {
+		index, err := l.Search(c.value)
+		expectOK(t, "Search", err)
+		if index != c.index {
+			t.Fatalf("Search(%v): expected %d, got %d", c.value, c.index, index)
+		}
+		if !l.Contains(c.value) {
+			t.Fatalf("Contains(%v) is false", c.value)
+		}
+	}
+	index, err := l.Search("z")
+	expectErr(t, "Search missing", err, s.errs.NotFound)
+	if index != -1 {
+		t.Fatalf("Search missing: expected -1, got %d", index)
+	}
+	if l.Contains("z") {
+		t.Fatal("Contains(z) is true")
+	}
+}
+
+func testFuncs(t *testing.T, s suite) {
+	type user struct {
+		ID   int
+		Tags []string
+	}
+	a, b, c := user{1, []string{"x"}}, user{2, nil}, user{1, []string{"y"}}
+	id := func(want int) func(any) bool {
+		return func(v any) bool { return v.(user).ID == want }
+	}
+	l := s.build(t, a, b, c)
+
+	index, err := l.SearchFunc(id(1))
+	expectOK(t, "SearchFunc", err)
+	if index != 0 {
+		t.Fatalf("SearchFunc: expected 0, got %d", index)
+	}
+	_, err = l.SearchFunc(id(3))
+	expectErr(t, "SearchFunc missing", err, s.errs.NotFound)
+	if got := l.IndexFunc(id(2)); got != 1 {
+		t.Fatalf("IndexFunc: expected 1, got %d", got)
+	}
+	if got := l.LastIndexFunc(id(1)); got != 2 {
+		t.Fatalf("LastIndexFunc: expected 2, got %d", got)
+	}
+	if got := l.IndexFunc(id(3)); got != -1 {
+		t.Fatalf("IndexFunc missing: expected -1, got %d", got)
+	}
+	if got := l.LastIndexFunc(id(3)); got != -1 {
+		t.Fatalf("LastIndexFunc missing: expected -1, got %d", got)
+	}
+	if !l.ContainsFunc(id(2)) || l.ContainsFunc(id(3)) {
+		t.Fatal("ContainsFunc reported the wrong result")
+	}
+
+	expectOK(t, "UniqueBy", l.UniqueBy(func(v any) any { return v.(user).ID }))
+	check(t, l, a, b)
+	expectOK(t, "DeleteFunc", l.DeleteFunc(id(1)))
+	check(t, l, b)
+	expectErr(t, "DeleteFunc missing", l.DeleteFunc(id(1)), s.errs.NotFound)
+	expectOK(t, "DeleteFunc last", l.DeleteFunc(id(2)))
+	check(t, l)
+	expectErr(t, "DeleteFunc on empty", l.DeleteFunc(id(2)), s.errs.Empty)
+	expectErr(t, "UniqueBy on empty", l.UniqueBy(nil), s.errs.Empty)
+
+	// A nil key function is the same as Unique
+	l = s.build(t, 1, 2, 1)
+	expectOK(t, "UniqueBy nil", l.UniqueBy(nil))
+	check(t, l, 1, 2)
+}
+
+// testNotComparable checks that values == can't compare are reported as
+// errors instead of panicking.
+func testNotComparable(t *testing.T, s suite) {
+	slice, other := []int{1}, []int{1}
+	l := s.build(t, 1, slice, map[string]int{}, 2)
+
+	_, err := l.Search(other)
+	if s.errs.SearchIgnoresNotComparable {
+		expectErr(t, "Search slice", err, s.errs.NotFound)
+	} else {
+		expectErr(t, "Search slice", err, s.errs.NotComparable)
+	}
+	if l.Contains(other) {
+		t.Fatal("Contains(slice) is true")
+	}
+	expectErr(t, "Delete slice", l.Delete(other), s.errs.NotComparable)
+	expectErr(t, "Unique with slices", l.Unique(), s.errs.NotComparable)
+	check(t, l, 1, slice, map[string]int{}, 2)
+
+	// Comparable values are still found among non-comparable ones
+	index, err := l.Search(2)
+	expectOK(t, "Search past slices", err)
+	if index != 3 {
+		t.Fatalf("Search(2): expected 3, got %d", index)
+	}
+	expectOK(t, "Delete past slices", l.Delete(2))
+	check(t, l, 1, slice, map[string]int{})
+
+	// A comparable type can still hold a slice in an interface field
+	type box struct{ V any }
+	l = s.build(t, box{slice})
+	expectErr(t, "Delete box of slice", l.Delete(box{other}), s.errs.NotComparable)
+	expectErr(t, "UniqueBy slice keys", l.UniqueBy(func(v any) any { return v.(box).V }), s.errs.NotComparable)
+	check(t, l, box{slice})
+}
+
+func testMove(t *testing.T, s suite) {
+	cases := []struct {
+		from, to int
+		want     []any
+	}{
+		{0, 3, []any{2, 3, 4, 1}},
+		{3, 0, []any{4, 1, 2, 3}},
+		{1, 2, []any{1, 3, 2, 4}},
+		{2, 1, []any{1, 3, 2, 4}},
+		{2, 2, []any{1, 2, 3, 4}},
+	}
+	for _, c := range cases {
+		l := s.build(t, 1, 2, 3, 4)
+		expectOK(t, "Move", l.Move(c.from, c.to))
+		check(t, l, c.want...)
+	}
+
+	for _, c := range [][2]int{{-1, 0}, {0, 4}, {4, 0}, {0, -1}} {
+		l := s.build(t, 1, 2, 3, 4)
+		expectErr(t, "Move out of range", l.Move(c[0], c[1]), s.errs.IndexOutOfBounds)
+		check(t, l, 1, 2, 3, 4)
+	}
+}
+
+func testReverse(t *testing.T, s suite) {
+	for _, values := range [][]any{{1}, {1, 2}, {1, 2, 3, 4, 5}} {
+		l := s.build(t, values...)
+		expectOK(t, "Reverse", l.Reverse())
+		want := make([]any, len(values))
+		for i, v := range values {
+			want[len(values)-1-i] = v
+		}
+		check(t, l, want...)
+
+		// The ends must be usable after reversing
+		expectOK(t, "Append after Reverse", l.Append(0))
+		expectOK(t, "Prepend after Reverse", l.Prepend(9))
+		check(t, l, append(append([]any{9}, want...), 0)...)
+	}
+}
+
+func testSort(t *testing.T, s suite) {
+	cases := []struct {
+		name         string
+		values, want []any
+	}{
+		{"Ints", []any{3, 1, 2, 1}, []any{1, 1, 2, 3}},
+		{"Strings", []any{"b", "c", "a"}, []any{"a", "b", "c"}},
+		{"Floats", []any{2.5, -1.0, 0.5}, []any{-1.0, 0.5, 2.5}},
+		{"Sorted", []any{1, 2, 3}, []any{1, 2, 3}},
+		{"Single", []any{true}, []any{true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := s.build(t, c.values...)
+			expectOK(t, "Sort", l.Sort())
+			check(t, l, c.want...)
+		})
+	}
+
+	l := s.build(t, 2, "a", 1)
+	expectErr(t, "Sort mixed types", l.Sort(), s.errs.MismatchedTypes)
+	if err := l.Validate(); err != nil {
+		t.Fatalf("Validate failed after a failed Sort: %v", err)
+	}
+
+	l = s.build(t, true, false)
+	expectErr(t, "Sort unsupported type", l.Sort(), s.errs.UnsupportedType)
+	check(t, l, true, false)
+}
+
+func testUnique(t *testing.T, s suite) {
+	cases := []struct {
+		values, want []any
+	}{
+		{[]any{1}, []any{1}},
+		{[]any{1, 1, 1}, []any{1}},
+		{[]any{1, 2, 1, 3, 2}, []any{1, 2, 3}},
+		{[]any{1, 2, 3, 3}, []any{1, 2, 3}},
+		{[]any{1, 1.0, "1"}, []any{1, 1.0, "1"}},
+	}
+	for _, c := range cases {
+		l := s.build(t, c.values...)
+		expectOK(t, "Unique", l.Unique())
+		check(t, l, c.want...)
+		expectOK(t, "Append after Unique", l.Append(9))
+		check(t, l, append(c.want, 9)...)
+	}
+}
+
+func testMerge(t *testing.T, s suite) {
+	l, other := s.build(t, 1, 2), s.build(t, 3, 4)
+	expectOK(t, "Merge", l.Merge(other))
+	check(t, l, 1, 2, 3, 4)
+	check(t, other, 3, 4)
+
+	expectOK(t, "Merge empty", l.Merge(s.newList()))
+	check(t, l, 1, 2, 3, 4)
+
+	empty := s.newList()
+	expectOK(t, "Merge into empty", empty.Merge(other))
+	check(t, empty, 3, 4)
+
+	// Merging a list into itself doubles it once
+	expectOK(t, "Merge self", other.Merge(other))
+	check(t, other, 3, 4, 3, 4)
+
+	if err := l.Merge(nil); err == nil {
+		t.Fatal("Merge(nil): expected an error")
+	}
+	check(t, l, 1, 2, 3, 4)
+}
+
+func testGetMiddle(t *testing.T, s suite) {
+	// For an even length the lower of the two middle elements is returned
+	for n, want := range []int{0, 0, 1, 1, 2, 2, 3} {
+		values := make([]any, n+1)
+		for i := range values {
+			values[i] = i
+		}
+		l := s.build(t, values...)
+		got, err := l.GetMiddle()
+		expectOK(t, "GetMiddle", err)
+		if got != want {
+			t.Fatalf("GetMiddle of %d elements: expected %d, got %v", n+1, want, got)
+		}
+	}
+}
+
+func testSlices(t *testing.T, s suite) {
+	l := s.build(t, 1, 2)
+	expectOK(t, "FromSlice", l.FromSlice([]any{"a", "b", "c"}))
+	check(t, l, "a", "b", "c")
+	expectOK(t, "FromSlice empty", l.FromSlice([]any{}))
+	check(t, l)
+
+	l = s.build(t, 1)
+	if err := l.FromSlice(nil); err == nil {
+		t.Fatal("FromSlice(nil): expected an error")
+	}
+
+	// IntoSlice returns a copy
+	l = s.build(t, 1, 2)
+	got := l.IntoSlice()
+	got[0] = 9
+	check(t, l, 1, 2)
+}
+
+func testAll(t *testing.T, s suite) {
+	l := s.build(t, "a", "b", "c")
+	var values []any
+	for i, v := range l.All() {
+		if want, _ := l.Get(i); v != want {
+			t.Fatalf("All yielded %v at %d, Get returned %v", v, i, want)
+		}
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []any{"a", "b", "c"}) {
+		t.Fatalf("All yielded %v", values)
+	}
+
+	count := 0
+	for range l.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatalf("All kept yielding after break")
+	}
+
+	for range s.newList().All() {
+		t.Fatal("All yielded a value for an empty list")
+	}
+}
@@ -0,0 +1,514 @@
+package listtest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// OpKind names a list operation used in model-based tests.
+type OpKind int
+
+const (
+	OpAppend OpKind = iota
+	OpPrepend
+	OpInsert
+	OpDeleteAt
+	OpDelete
+	OpShift
+	OpPop
+	OpReverse
+	OpUnique
+	OpSort
+	OpMerge
+	OpGet
+	numOpKinds
+)
+
+var opNames = [...]string{"Append", "Prepend", "Insert", "DeleteAt", "Delete", "Shift", "Pop", "Reverse", "Unique", "Sort", "Merge", "Get"}
+
+// String returns the name of the list method.
+func (k OpKind) String() string {
+	if k >= 0 && k < numOpKinds {
+		return opNames[k]
+	}
+	return fmt.Sprintf("OpKind(%d)", int(k))
+}
+
+// Op is one operation in a generated sequence. Only the fields the kind
+// uses are set.
+type Op struct {
+	Kind   OpKind
+	Value  any   // Append, Prepend, Insert, Delete
+	Index  int   // Insert, DeleteAt, Get
+	Values []any // Merge
+}
+
+// GoString formats the op as a call on a variable named list.
+func (op Op) GoString() string {
+	switch op.Kind {
+	case OpAppend, OpPrepend, OpDelete:
+		return fmt.Sprintf("list.%s(%#v)", op.Kind, op.Value)
+	case OpInsert:
+		return fmt.Sprintf("list.Insert(%#v, %d)", op.Value, op.Index)
+	case OpDeleteAt, OpGet:
+		return fmt.Sprintf("list.%s(%d)", op.Kind, op.Index)
+	case OpMerge:
+		args := make([]string, len(op.Values))
+		for i, v := range op.Values {
+			args[i] = fmt.Sprintf("%#v", v)
+		}
+		return fmt.Sprintf("list.Merge(listOf(%s))", strings.Join(args, ", "))
+	}
+	return fmt.Sprintf("list.%s()", op.Kind)
+}
+
+// Ops is a sequence of operations. It implements quick.Generator, so
+// testing/quick can produce random sequences.
+type Ops []Op
+
+// Generate returns a random sequence of up to size operations. Values are
+// mostly small ints, so deletes and duplicates hit often, with the odd
+// string to make Sort fail.
+func (Ops) Generate(r *rand.Rand, size int) reflect.Value {
+	value := func() any {
+		if r.Intn(10) == 0 {
+			return string(rune('a' + r.Intn(3)))
+		}
+		return r.Intn(5)
+	}
+	ops := make(Ops, r.Intn(size+1))
+	for i := range ops {
+		op := Op{Kind: OpKind(r.Intn(int(numOpKinds)))}
+		switch op.Kind {
+		case OpAppend, OpPrepend, OpDelete:
+			op.Value = value()
+		case OpInsert:
+			op.Value = value()
+			op.Index = r.Intn(8) - 1
+		case OpDeleteAt, OpGet:
+			op.Index = r.Intn(8) - 1
+		case OpMerge:
+			op.Values = make([]any, r.Intn(4))
+			for j := range op.Values {
+				op.Values[j] = value()
+			}
+		}
+		ops[i] = op
+	}
+	return reflect.ValueOf(ops)
+}
+
+// DecodeOps turns arbitrary bytes into an operation sequence, so native fuzz
+// targets can drive the same model as RunModel. Each op takes a kind byte and
+// then one byte per argument; a trailing partial op is dropped.
+func DecodeOps(data []byte) Ops {
+	value := func(b byte) any {
+		if b&0x80 != 0 {
+			return string(rune('a' + b%3))
+		}
+		return int(b % 5)
+	}
+	index := func(b byte) int {
+		return int(b%10) - 1
+	}
+
+	var ops Ops
+	for len(data) > 0 {
+		op := Op{Kind: OpKind(data[0] % byte(numOpKinds))}
+		args := data[1:]
+		need := 0
+		switch op.Kind {
+		case OpAppend, OpPrepend, OpDelete, OpDeleteAt, OpGet:
+			need = 1
+		case OpInsert:
+			need = 2
+		case OpMerge:
+			if len(args) > 0 {
+				need = 1 + int(args[0]%4)
+			} else {
+				need = 1
+			}
+		}
+		if len(args) < need {
+			break
+		}
+		switch op.Kind {
+		case OpAppend, OpPrepend, OpDelete:
+			op.Value = value(args[0])
+		case OpInsert:
+			op.Value, op.Index = value(args[0]), index(args[1])
+		case OpDeleteAt, OpGet:
+			op.Index = index(args[0])
+		case OpMerge:
+			op.Values = []any{}
+			for _, b := range args[1:need] {
+				op.Values = append(op.Values, value(b))
+			}
+		}
+		ops = append(ops, op)
+		data = args[need:]
+	}
+	return ops
+}
+
+// GoString formats the sequence as Go statements that reproduce it.
+func (ops Ops) GoString() string {
+	var sb strings.Builder
+	sb.WriteString("list := newList()\n")
+	for _, op := range ops {
+		sb.WriteString(op.GoString())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// outcome is what the model allows an operation to return: success, or an
+// error of one of the kinds. A nil kind accepts any error.
+type outcome struct {
+	ok    bool
+	kinds []error
+	value any // Value a successful Get returns
+}
+
+// success allows only success.
+func success() outcome {
+	return outcome{ok: true}
+}
+
+// failure allows only an error of the given kind.
+func failure(kind error) outcome {
+	return outcome{kinds: []error{kind}}
+}
+
+// String describes the allowed results for failure messages.
+func (o outcome) String() string {
+	var parts []string
+	if o.ok {
+		parts = append(parts, "success")
+	}
+	for _, kind := range o.kinds {
+		if kind == nil {
+			parts = append(parts, "an error")
+		} else {
+			parts = append(parts, fmt.Sprintf("%q", kind))
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+// accepts reports whether err is an allowed result.
+func (o outcome) accepts(err error) bool {
+	if err == nil {
+		return o.ok
+	}
+	for _, kind := range o.kinds {
+		if kind == nil || errors.Is(err, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// StepError reports the first step at which a list diverged from the model.
+type StepError struct {
+	Step int // Index of the operation in the sequence
+	Op   Op
+	Msg  string
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d, %#v: %s", e.Step, e.Op, e.Msg)
+}
+
+// Replay applies ops to a new list and to a slice model of it, comparing
+// errors, contents and Validate after every step. It returns a *StepError
+// for the first divergence, including panics, or nil.
+func Replay(newList func() List, errs Errors, ops Ops) (err error) {
+	step := -1
+	defer func() {
+		if r := recover(); r != nil {
+			e := &StepError{Step: step, Msg: fmt.Sprintf("panic: %v", r)}
+			if step >= 0 {
+				e.Op = ops[step]
+			}
+			err = e
+		}
+	}()
+
+	list := newList()
+	var model []any
+	for i, op := range ops {
+		step = i
+		want, next := apply(model, op, errs)
+		value, got := run(list, op, newList)
+		if !want.accepts(got) {
+			return &StepError{Step: i, Op: op, Msg: fmt.Sprintf("returned %v, model expects %v", got, want)}
+		}
+		if op.Kind == OpGet && got == nil && value != want.value {
+			return &StepError{Step: i, Op: op, Msg: fmt.Sprintf("returned %#v, model expects %#v", value, want.value)}
+		}
+
+		values := list.IntoSlice()
+		if op.Kind == OpSort && got != nil {
+			// A failed sort may leave the list partly sorted, but it must
+			// still hold the same values.
+			if !sameValues(values, model) {
+				return &StepError{Step: i, Op: op, Msg: fmt.Sprintf("failed sort changed the values from %v to %v", model, values)}
+			}
+			next = values
+		}
+		if !equal(values, next) {
+			return &StepError{Step: i, Op: op, Msg: fmt.Sprintf("list is %v, model is %v", values, next)}
+		}
+		if list.Len() != len(next) {
+			return &StepError{Step: i, Op: op, Msg: fmt.Sprintf("Len is %d, model has %d values", list.Len(), len(next))}
+		}
+		if err := list.Validate(); err != nil {
+			return &StepError{Step: i, Op: op, Msg: fmt.Sprintf("Validate failed: %v", err)}
+		}
+		model = next
+	}
+	return nil
+}
+
+// run applies op to list. Only Get returns a value.
+func run(list List, op Op, newList func() List) (any, error) {
+	switch op.Kind {
+	case OpAppend:
+		return nil, list.Append(op.Value)
+	case OpPrepend:
+		return nil, list.Prepend(op.Value)
+	case OpInsert:
+		return nil, list.Insert(op.Value, op.Index)
+	case OpDeleteAt:
+		return nil, list.DeleteAt(op.Index)
+	case OpDelete:
+		return nil, list.Delete(op.Value)
+	case OpShift:
+		return nil, list.Shift()
+	case OpPop:
+		return nil, list.Pop()
+	case OpReverse:
+		return nil, list.Reverse()
+	case OpUnique:
+		return nil, list.Unique()
+	case OpSort:
+		return nil, list.Sort()
+	case OpMerge:
+		other := newList()
+		for _, v := range op.Values {
+			other.Append(v)
+		}
+		return nil, list.Merge(other)
+	case OpGet:
+		return list.Get(op.Index)
+	}
+	return nil, fmt.Errorf("unknown op %v", op.Kind)
+}
+
+// apply runs op on the model and returns the allowed outcome and the new
+// contents. The model is never modified in place.
+func apply(model []any, op Op, errs Errors) (outcome, []any) {
+	switch op.Kind {
+	case OpAppend:
+		return success(), append(slices.Clone(model), op.Value)
+	case OpPrepend:
+		return success(), append([]any{op.Value}, model...)
+	case OpInsert:
+		if op.Index < 0 || op.Index > len(model) {
+			return failure(errs.IndexOutOfBounds), model
+		}
+		return success(), slices.Insert(slices.Clone(model), op.Index, op.Value)
+	case OpDeleteAt:
+		if op.Index < 0 || op.Index >= len(model) {
+			return failure(errs.IndexOutOfBounds), model
+		}
+		return success(), slices.Delete(slices.Clone(model), op.Index, op.Index+1)
+	case OpDelete:
+		if len(model) == 0 {
+			return failure(errs.Empty), model
+		}
+		i := slices.Index(model, op.Value)
+		if i < 0 {
+			return failure(errs.NotFound), model
+		}
+		return success(), slices.Delete(slices.Clone(model), i, i+1)
+	case OpShift, OpPop:
+		if len(model) == 0 {
+			return failure(errs.Empty), model
+		}
+		if op.Kind == OpShift {
+			return success(), slices.Clone(model[1:])
+		}
+		return success(), slices.Clone(model[:len(model)-1])
+	case OpReverse:
+		if len(model) == 0 {
+			// Implementations may report the empty list
+			return outcome{ok: true, kinds: []error{errs.Empty}}, model
+		}
+		reversed := slices.Clone(model)
+		slices.Reverse(reversed)
+		return success(), reversed
+	case OpUnique:
+		if len(model) == 0 {
+			return failure(errs.Empty), model
+		}
+		var unique []any
+		for _, v := range model {
+			if !slices.Contains(unique, v) {
+				unique = append(unique, v)
+			}
+		}
+		return success(), unique
+	case OpSort:
+		return sortModel(model, errs)
+	case OpMerge:
+		return success(), append(slices.Clone(model), op.Values...)
+	case OpGet:
+		if op.Index < 0 || op.Index >= len(model) {
+			return failure(errs.IndexOutOfBounds), model
+		}
+		return outcome{ok: true, value: model[op.Index]}, model
+	}
+	return failure(nil), model
+}
+
+// sortModel sorts a model holding ints or strings. Lists of one element
+// always sort successfully; otherwise any mix of types is an error whose kind
+// depends on where the sort finds it first.
+func sortModel(model []any, errs Errors) (outcome, []any) {
+	if len(model) <= 1 {
+		return success(), model
+	}
+	sorted := slices.Clone(model)
+	switch model[0].(type) {
+	case int:
+		for _, v := range model {
+			if _, ok := v.(int); !ok {
+				return outcome{kinds: []error{errs.MismatchedTypes, errs.UnsupportedType}}, model
+			}
+		}
+		slices.SortStableFunc(sorted, func(a, b any) int { return a.(int) - b.(int) })
+	case string:
+		for _, v := range model {
+			if _, ok := v.(string); !ok {
+				return outcome{kinds: []error{errs.MismatchedTypes, errs.UnsupportedType}}, model
+			}
+		}
+		slices.SortStableFunc(sorted, func(a, b any) int { return strings.Compare(a.(string), b.(string)) })
+	default:
+		return failure(errs.UnsupportedType), model
+	}
+	return success(), sorted
+}
+
+// equal compares two value slices, treating nil and empty as equal.
+func equal(a, b []any) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+// sameValues reports whether a and b hold the same values in any order.
+func sameValues(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := map[any]int{}
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		counts[v]--
+		if counts[v] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Shrink returns a shorter sequence that still makes Replay fail. It removes
+// ever smaller runs of operations, then simplifies the remaining values, until
+// no single change keeps the failure.
+func Shrink(newList func() List, errs Errors, ops Ops) Ops {
+	fails := func(candidate Ops) bool {
+		return Replay(newList, errs, candidate) != nil
+	}
+	if !fails(ops) {
+		return ops
+	}
+
+	for chunk := len(ops) / 2; chunk >= 1; {
+		removed := false
+		for start := 0; start+chunk <= len(ops); {
+			candidate := slices.Concat(ops[:start], ops[start+chunk:])
+			if fails(candidate) {
+				ops = candidate
+				removed = true
+			} else {
+				start += chunk
+			}
+		}
+		if !removed {
+			chunk /= 2
+		}
+	}
+
+	for i := range ops {
+		for _, simpler := range simplify(ops[i]) {
+			candidate := slices.Clone(ops)
+			candidate[i] = simpler
+			if fails(candidate) {
+				ops = candidate
+				break
+			}
+		}
+	}
+	return ops
+}
+
+// simplify returns simpler variants of op to try while shrinking.
+func simplify(op Op) []Op {
+	var simpler []Op
+	if op.Value != nil && op.Value != 0 {
+		s := op
+		s.Value = 0
+		simpler = append(simpler, s)
+	}
+	if op.Index != 0 {
+		s := op
+		s.Index = 0
+		simpler = append(simpler, s)
+	}
+	for i := range op.Values {
+		s := op
+		s.Values = slices.Delete(slices.Clone(op.Values), i, i+1)
+		simpler = append(simpler, s)
+	}
+	return simpler
+}
+
+// RunModel checks random operation sequences generated by testing/quick
+// against a slice model. A failing sequence is shrunk and reported as Go
+// code. A nil config uses the testing/quick defaults.
+func RunModel(t *testing.T, newList func() List, errs Errors, config *quick.Config) {
+	t.Helper()
+	property := func(ops Ops) bool {
+		return Replay(newList, errs, ops) == nil
+	}
+	err := quick.Check(property, config)
+	if err == nil {
+		return
+	}
+	var checkErr *quick.CheckError
+	if !errors.As(err, &checkErr) {
+		t.Fatal(err)
+	}
+
+	ops := Shrink(newList, errs, checkErr.In[0].(Ops))
+	t.Fatalf("model check failed after %d random sequences: %v\nminimal reproducer:\n\n%s", checkErr.Count, Replay(newList, errs, ops), ops.GoString())
+}
@@ -0,0 +1,305 @@
+// Package ordered implements a map and a set that remember the order in
+// which their keys were added. Each combines a Go map, for O(1) lookup, with
+// a doubly linked list that holds the keys in order, for O(1) insertion and
+// deletion anywhere.
+package ordered
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+
+	"github.com/JustMrNone/ll/doubly"
+)
+
+// entry is what the underlying list stores for every key.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// Map is a map that iterates over its entries in insertion order. The zero
+// value is an empty map in insertion order, ready to use. It is not safe for
+// concurrent use.
+type Map[K comparable, V any] struct {
+	list        *doubly.LinkedList // *entry values in iteration order
+	nodes       map[K]*doubly.Node // Node of every key
+	accessOrder bool               // Get and Set move their key to the end
+}
+
+// NewMap creates an empty map that keeps its keys in insertion order.
+// Setting an existing key keeps its position.
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{}
+}
+
+// NewAccessOrderMap creates an empty map that keeps its keys in access
+// order: Get and Set move their key to the end, so iteration runs from the
+// least to the most recently used key. Together with First and Delete, this
+// is the bookkeeping an LRU cache needs.
+func NewAccessOrderMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{accessOrder: true}
+}
+
+// Len returns the number of entries in the map.
+func (m *Map[K, V]) Len() int {
+	return len(m.nodes)
+}
+
+// Get returns the value stored for key and whether it was present. In access
+// order it also moves key to the end.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	node, ok := m.nodes[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if m.accessOrder {
+		m.list.MoveToBack(node)
+	}
+	return node.Value.(*entry[K, V]).value, true
+}
+
+// Has reports whether key is present, without counting as an access.
+func (m *Map[K, V]) Has(key K) bool {
+	_, ok := m.nodes[key]
+	return ok
+}
+
+// Set stores value for key. A new key goes to the end; an existing key keeps
+// its position, unless the map is in access order.
+func (m *Map[K, V]) Set(key K, value V) {
+	if node, ok := m.nodes[key]; ok {
+		if m.accessOrder {
+			m.list.MoveToBack(node)
+		}
+		node.Value.(*entry[K, V]).value = value
+		return
+	}
+	if m.list == nil {
+		m.list = doubly.NewDoublyLinkedList()
+		m.nodes = make(map[K]*doubly.Node)
+	}
+	m.nodes[key] = m.list.InsertAfter(m.list.Tail, &entry[K, V]{key: key, value: value})
+}
+
+// Delete removes key and reports whether it was present.
+func (m *Map[K, V]) Delete(key K) bool {
+	node, ok := m.nodes[key]
+	if !ok {
+		return false
+	}
+	m.list.Remove(node)
+	delete(m.nodes, key)
+	return true
+}
+
+// MoveToEnd moves key to the end of the iteration order and reports whether
+// it was present.
+func (m *Map[K, V]) MoveToEnd(key K) bool {
+	node, ok := m.nodes[key]
+	if ok {
+		m.list.MoveToBack(node)
+	}
+	return ok
+}
+
+// First returns the first entry in iteration order: the oldest key, or in
+// access order the least recently used one.
+func (m *Map[K, V]) First() (K, V, bool) {
+	if m.Len() == 0 {
+		var key K
+		var value V
+		return key, value, false
+	}
+	e := m.list.Head.Value.(*entry[K, V])
+	return e.key, e.value, true
+}
+
+// Last returns the last entry in iteration order.
+func (m *Map[K, V]) Last() (K, V, bool) {
+	if m.Len() == 0 {
+		var key K
+		var value V
+		return key, value, false
+	}
+	e := m.list.Tail.Value.(*entry[K, V])
+	return e.key, e.value, true
+}
+
+// Clear removes every entry.
+func (m *Map[K, V]) Clear() {
+	if m.list != nil {
+		m.list.Clear()
+		clear(m.nodes)
+	}
+}
+
+// All returns an iterator over the entries in order. The map must not be
+// changed during iteration, except by deleting the current key.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.list == nil {
+			return
+		}
+		for node := m.list.Head; node != nil; {
+			next := node.Next
+			e := node.Value.(*entry[K, V])
+			if !yield(e.key, e.value) {
+				return
+			}
+			node = next
+		}
+	}
+}
+
+// Backward returns an iterator over the entries in reverse order.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.list == nil {
+			return
+		}
+		for node := m.list.Tail; node != nil; {
+			prev := node.Prev
+			e := node.Value.(*entry[K, V])
+			if !yield(e.key, e.value) {
+				return
+			}
+			node = prev
+		}
+	}
+}
+
+// Keys returns the keys in order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Values returns the values in order.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	for _, value := range m.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// MarshalJSON encodes the map as a JSON object with its keys in order. Keys
+// are encoded like encoding/json encodes map keys: strings as they are,
+// integers as decimal strings, and encoding.TextMarshaler keys as their text.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for key, value := range m.All() {
+		name, err := encodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		quoted, _ := json.Marshal(name)
+		buf.Write(quoted)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces the contents of the map with a JSON object, keeping
+// its keys in the order they appear. A key that appears twice ends up with
+// its last value. A JSON null leaves the map empty.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	m.Clear()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("cannot unmarshal %v into a map", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := decodeKey[K](tok.(string))
+		if err != nil {
+			return err
+		}
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// encodeKey turns a map key into the name of a JSON object member.
+func encodeKey(key any) (string, error) {
+	v := reflect.ValueOf(key)
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported key type %T", key)
+}
+
+// decodeKey parses the name of a JSON object member into a map key.
+func decodeKey[K comparable](name string) (K, error) {
+	var key K
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := tu.UnmarshalText([]byte(name))
+		return key, err
+	}
+	v := reflect.ValueOf(&key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("invalid key %q: %v", name, err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("invalid key %q: %v", name, err)
+		}
+		v.SetUint(n)
+	default:
+		return key, fmt.Errorf("unsupported key type %T", key)
+	}
+	return key, nil
+}
@@ -0,0 +1,94 @@
+package ordered
+
+import (
+	"encoding/json"
+	"iter"
+)
+
+// Set is a set that iterates over its values in insertion order, like a
+// doubly list after Unique but without rescanning it. The zero value is an
+// empty set ready to use. It is not safe for concurrent use.
+type Set[T comparable] struct {
+	m Map[T, struct{}]
+}
+
+// NewSet creates a set holding values, in order and without duplicates.
+func NewSet[T comparable](values ...T) *Set[T] {
+	s := &Set[T]{}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s
+}
+
+// Len returns the number of values in the set.
+func (s *Set[T]) Len() int {
+	return s.m.Len()
+}
+
+// Add adds value at the end and reports whether it was new. A value that is
+// already present keeps its position.
+func (s *Set[T]) Add(value T) bool {
+	if s.m.Has(value) {
+		return false
+	}
+	s.m.Set(value, struct{}{})
+	return true
+}
+
+// Remove removes value and reports whether it was present.
+func (s *Set[T]) Remove(value T) bool {
+	return s.m.Delete(value)
+}
+
+// Contains reports whether value is in the set.
+func (s *Set[T]) Contains(value T) bool {
+	return s.m.Has(value)
+}
+
+// MoveToEnd moves value to the end of the iteration order and reports
+// whether it was present.
+func (s *Set[T]) MoveToEnd(value T) bool {
+	return s.m.MoveToEnd(value)
+}
+
+// Clear removes every value.
+func (s *Set[T]) Clear() {
+	s.m.Clear()
+}
+
+// All returns an iterator over the values in order. The set must not be
+// changed during iteration, except by removing the current value.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range s.m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the values in order.
+func (s *Set[T]) Values() []T {
+	return s.m.Keys()
+}
+
+// MarshalJSON encodes the set as a JSON array in order.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Values())
+}
+
+// UnmarshalJSON replaces the contents of the set with a JSON array, keeping
+// the first occurrence of every value.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	s.Clear()
+	for _, v := range values {
+		s.Add(v)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package setops computes unions, intersections and differences of doubly
+// linked lists, treating them either as sets or as multisets.
+//
+// Every function returns a new list and leaves its inputs alone. The order
+// of the result is deterministic. On the hash path, which is the default,
+// the result keeps the order of the inputs: elements from the first list in
+// their order, followed by elements from the second. On the merge path,
+// chosen by setting Options.Compare, both inputs must be sorted and the
+// result is sorted too.
+package setops
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/JustMrNone/ll/doubly"
+)
+
+// ErrUnsorted is returned on the merge path for an input that is not sorted.
+// Values that can't be used as map keys on the hash path return
+// doubly.ErrNotComparable. Both may be wrapped with more detail, so check
+// for them with errors.Is.
+var ErrUnsorted = errors.New("list is not sorted")
+
+// Mode selects how repeated values are counted.
+type Mode int
+
+const (
+	// Set counts every value once: the inputs are reduced to the first
+	// occurrence of each value, so the result has no duplicates.
+	Set Mode = iota
+	// Multiset counts every occurrence. A value that appears m times in
+	// the first list and n times in the second appears max(m, n) times in
+	// the union, min(m, n) times in the intersection, max(m-n, 0) times in
+	// the difference and |m-n| times in the symmetric difference.
+	Multiset
+)
+
+// Options configures an operation. The zero value compares values with ==
+// in Set mode.
+type Options struct {
+	Mode Mode
+
+	// Key maps a value to the comparable key it is matched by on the hash
+	// path, for values such as slices that can't be compared with ==, or to
+	// match structs by ID. Nil means the value itself.
+	Key func(value any) any
+
+	// Compare switches to the linear-time merge path. Both lists must be
+	// sorted by it, or the operation fails with ErrUnsorted. Values it
+	// reports as equal are matched, and Key is not used.
+	Compare func(a, b any) int
+}
+
+// Union returns the values that are in a or b. On the hash path the result
+// holds a's values followed by the values of b that a does not match. In
+// Multiset mode, those are the occurrences of a value in b beyond the number
+// in a.
+func Union(a, b *doubly.LinkedList, opts Options) (*doubly.LinkedList, error) {
+	return run(a, b, opts, union)
+}
+
+// Intersection returns the values that are in both a and b, in a's order on
+// the hash path. In Multiset mode, the first occurrences of a value in a are
+// kept, as many as appear in b.
+func Intersection(a, b *doubly.LinkedList, opts Options) (*doubly.LinkedList, error) {
+	return run(a, b, opts, intersection)
+}
+
+// Difference returns the values of a that are not in b, in a's order on the
+// hash path. In Multiset mode, each occurrence in b cancels the earliest
+// remaining one in a.
+func Difference(a, b *doubly.LinkedList, opts Options) (*doubly.LinkedList, error) {
+	return run(a, b, opts, difference)
+}
+
+// SymmetricDifference returns the values that are in exactly one of a and
+// b. On the hash path the result is Difference(a, b) followed by
+// Difference(b, a).
+func SymmetricDifference(a, b *doubly.LinkedList, opts Options) (*doubly.LinkedList, error) {
+	return run(a, b, opts, symmetricDifference)
+}
+
+// op identifies one of the four operations.
+type op int
+
+const (
+	union op = iota
+	intersection
+	difference
+	symmetricDifference
+)
+
+// run checks the inputs and computes op on the path opts selects.
+func run(a, b *doubly.LinkedList, opts Options, o op) (*doubly.LinkedList, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("cannot combine with a nil list")
+	}
+	var values []any
+	var err error
+	if opts.Compare != nil {
+		values, err = merge(a.IntoSlice(), b.IntoSlice(), opts, o)
+	} else {
+		values, err = hash(a.IntoSlice(), b.IntoSlice(), opts, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := doubly.NewDoublyLinkedList()
+	for _, v := range values {
+		result.Append(v)
+	}
+	return result, nil
+}
+
+// hash computes o by counting keys in maps. Every occurrence of a key in one
+// list is matched against the count of that key in the other.
+func hash(a, b []any, opts Options, o op) ([]any, error) {
+	keysA, err := keys(a, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	keysB, err := keys(b, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Mode == Set {
+		a, keysA = firstOccurrences(a, keysA)
+		b, keysB = firstOccurrences(b, keysB)
+	}
+	countA, countB := count(keysA), count(keysB)
+
+	var out []any
+	// beyond appends the occurrences of each key in values past the first
+	// limit[key] ones
+	beyond := func(values, keys []any, limit map[any]int) {
+		seen := make(map[any]int)
+		for i, v := range values {
+			seen[keys[i]]++
+			if seen[keys[i]] > limit[keys[i]] {
+				out = append(out, v)
+			}
+		}
+	}
+
+	switch o {
+	case union:
+		out = append(out, a...)
+		beyond(b, keysB, countA)
+	case intersection:
+		seen := make(map[any]int)
+		for i, v := range a {
+			seen[keysA[i]]++
+			if seen[keysA[i]] <= countB[keysA[i]] {
+				out = append(out, v)
+			}
+		}
+	case difference:
+		beyond(a, keysA, countB)
+	case symmetricDifference:
+		beyond(a, keysA, countB)
+		beyond(b, keysB, countA)
+	}
+	return out, nil
+}
+
+// merge computes o by walking two sorted slices side by side, in the manner
+// of the C++ std::set_union family.
+func merge(a, b []any, opts Options, o op) ([]any, error) {
+	cmp := opts.Compare
+	if err := checkSorted(a, cmp, "first"); err != nil {
+		return nil, err
+	}
+	if err := checkSorted(b, cmp, "second"); err != nil {
+		return nil, err
+	}
+	if opts.Mode == Set {
+		a, b = distinct(a, cmp), distinct(b, cmp)
+	}
+
+	var out []any
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp(a[i], b[j]); {
+		case c < 0:
+			if o == union || o == difference || o == symmetricDifference {
+				out = append(out, a[i])
+			}
+			i++
+		case c > 0:
+			if o == union || o == symmetricDifference {
+				out = append(out, b[j])
+			}
+			j++
+		default:
+			if o == union || o == intersection {
+				out = append(out, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if o == union || o == difference || o == symmetricDifference {
+		out = append(out, a[i:]...)
+	}
+	if o == union || o == symmetricDifference {
+		out = append(out, b[j:]...)
+	}
+	return out, nil
+}
+
+// keys returns the key of every value, checking that it can be used in a
+// map.
+func keys(values []any, key func(any) any) ([]any, error) {
+	out := make([]any, len(values))
+	for i, v := range values {
+		k := v
+		if key != nil {
+			k = key(v)
+		}
+		if k != nil && !reflect.ValueOf(k).Comparable() {
+			return nil, fmt.Errorf("%w: %T at index %d; set Options.Key", doubly.ErrNotComparable, k, i)
+		}
+		out[i] = k
+	}
+	return out, nil
+}
+
+// firstOccurrences drops every value whose key appeared earlier.
+func firstOccurrences(values, keys []any) ([]any, []any) {
+	seen := make(map[any]bool)
+	var outValues, outKeys []any
+	for i, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			outValues = append(outValues, values[i])
+			outKeys = append(outKeys, k)
+		}
+	}
+	return outValues, outKeys
+}
+
+// count returns how many times every key occurs.
+func count(keys []any) map[any]int {
+	counts := make(map[any]int, len(keys))
+	for _, k := range keys {
+		counts[k]++
+	}
+	return counts
+}
+
+// checkSorted returns ErrUnsorted if values are not in ascending order.
+func checkSorted(values []any, cmp func(a, b any) int, which string) error {
+	for i := 1; i < len(values); i++ {
+		if cmp(values[i-1], values[i]) > 0 {
+			return fmt.Errorf("%w: %s list has %v before %v at index %d", ErrUnsorted, which, values[i-1], values[i], i)
+		}
+	}
+	return nil
+}
+
+// distinct drops every value equal to the one before it, which in a sorted
+// slice leaves one of each.
+func distinct(values []any, cmp func(a, b any) int) []any {
+	var out []any
+	for i, v := range values {
+		if i == 0 || cmp(values[i-1], v) != 0 {
+			out = append(out, v)
+		}
+	}
+	return out
+}
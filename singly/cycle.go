@@ -0,0 +1,77 @@
+package singly
+
+// Cycle describes a loop in the Next chain of a corrupted list.
+type Cycle struct {
+	Start      int // Index of the first node on the cycle
+	Length     int // Number of nodes on the cycle
+	TailLength int // Number of nodes before the cycle; always equal to Start
+}
+
+// DetectCycle reports whether following Next from Head loops back on itself
+// and, if it does, where the cycle starts and how long it is. It uses Brent's
+// algorithm, so it runs in linear time and constant space.
+func (ll *LinkedList) DetectCycle() (Cycle, bool) {
+	start, length, _ := findCycle(ll.Head)
+	if length == 0 {
+		return Cycle{}, false
+	}
+	return Cycle{Start: start, Length: length, TailLength: start}, true
+}
+
+// BreakCycle cuts the back-edge that closes a cycle in the Next chain and
+// recomputes Size from the nodes that remain. It reports whether there was a
+// cycle to break.
+func (ll *LinkedList) BreakCycle() bool {
+	start, length, entry := findCycle(ll.Head)
+	if length == 0 {
+		return false
+	}
+
+	// The last node on the cycle is the one pointing back at the entry
+	last := entry
+	for i := 1; i < length; i++ {
+		last = last.Next
+	}
+	last.Next = nil
+	ll.Size = start + length
+	return true
+}
+
+// findCycle runs Brent's algorithm on the chain starting at head. It returns
+// the index of the cycle's first node, the cycle length and the first node
+// itself, or a zero length if the chain ends.
+func findCycle(head *Node) (int, int, *Node) {
+	if head == nil {
+		return 0, 0, nil
+	}
+
+	// Find the cycle length: the hare runs ahead in powers of two while the
+	// tortoise waits at the start of each run.
+	power, length := 1, 1
+	tortoise, hare := head, head.Next
+	for hare != nil && tortoise != hare {
+		if power == length {
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+		hare = hare.Next
+		length++
+	}
+	if hare == nil {
+		return 0, 0, nil
+	}
+
+	// With the hare a cycle length ahead, both meet at the cycle's start
+	tortoise, hare = head, head
+	for i := 0; i < length; i++ {
+		hare = hare.Next
+	}
+	start := 0
+	for tortoise != hare {
+		tortoise = tortoise.Next
+		hare = hare.Next
+		start++
+	}
+	return start, length, tortoise
+}
@@ -0,0 +1,94 @@
+package singly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FixKind identifies what a single repair changed.
+type FixKind int
+
+const (
+	FixCycle FixKind = iota // The Next back-edge closing a cycle was cut
+	FixSize                 // Size was recomputed
+)
+
+// String returns a short name for the fix kind.
+func (k FixKind) String() string {
+	switch k {
+	case FixCycle:
+		return "cycle"
+	case FixSize:
+		return "size"
+	}
+	return fmt.Sprintf("FixKind(%d)", int(k))
+}
+
+// Fix records one change made by Repair.
+type Fix struct {
+	Kind   FixKind
+	Index  int // Index of the affected node, or -1 when the fix is not about one node
+	Detail string
+}
+
+// String formats the fix for logs.
+func (f Fix) String() string {
+	if f.Index < 0 {
+		return fmt.Sprintf("%s: %s", f.Kind, f.Detail)
+	}
+	return fmt.Sprintf("%s at %d: %s", f.Kind, f.Index, f.Detail)
+}
+
+// RepairReport lists every fix applied by Repair, in order.
+type RepairReport struct {
+	Fixes []Fix
+}
+
+// Changed reports whether Repair had to fix anything.
+func (r RepairReport) Changed() bool {
+	return len(r.Fixes) > 0
+}
+
+// String formats the report one fix per line.
+func (r RepairReport) String() string {
+	if !r.Changed() {
+		return "no repairs needed"
+	}
+	lines := make([]string, len(r.Fixes))
+	for i, fix := range r.Fixes {
+		lines[i] = fix.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add appends a fix to the report.
+func (r *RepairReport) add(kind FixKind, index int, format string, args ...any) {
+	r.Fixes = append(r.Fixes, Fix{Kind: kind, Index: index, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Repair makes the list consistent again and reports every fix it applied:
+// it cuts a cycle in the Next chain and recomputes Size from the nodes
+// reachable from Head. Validate returns nil after Repair.
+func (ll *LinkedList) Repair() RepairReport {
+	var report RepairReport
+
+	if start, length, entry := findCycle(ll.Head); length > 0 {
+		last := entry
+		for i := 1; i < length; i++ {
+			last = last.Next
+		}
+		last.Next = nil
+		report.add(FixCycle, start+length-1, "cut next link closing a cycle of length %d back to index %d", length, start)
+	}
+
+	count := 0
+	for current := ll.Head; current != nil; current = current.Next {
+		count++
+	}
+	if ll.Size != count {
+		report.add(FixSize, -1, "recomputed size from %d to %d", ll.Size, count)
+		ll.Size = count
+	}
+
+	return report
+}
@@ -1,7 +1,31 @@
 // Package singly implements a singly linked list data structure.
+//
+// Search and Contains keep their v1 signatures, which have no error, so
+// unlike Delete and Unique they can't report ErrNotComparable. They treat a
+// value that == can't compare, such as a slice, as not found.
 package singly
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/JustMrNone/ll/alloc"
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// Errors returned by list operations. They may be wrapped with more
+// detail, so check for them with errors.Is.
+var (
+	ErrEmpty            = errors.New("list is empty")
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrNotFound         = errors.New("value not found")
+	ErrMismatchedTypes  = errors.New("mismatched types in list")
+	ErrUnsupportedType  = errors.New("unsupported type for sorting")
+	ErrCorrupt          = errors.New("corrupt list")
+	ErrNotComparable    = errors.New("value is not comparable")
+)
 
 // Node represents a node in the singly linked list.
 type Node struct {
@@ -13,6 +37,14 @@ type Node struct {
 type LinkedList struct {
 	Head *Node // First node in the list
 	Size int   // Number of nodes in the list
+
+	// Allocator supplies new nodes and takes back removed ones. When it is
+	// nil, nodes are allocated with new and left to the garbage collector.
+	// Either way, a node removed by the list is zeroed, so it must not be
+	// used afterwards.
+	Allocator alloc.Allocator[Node]
+
+	observers *event.Hub // Subscribers to change events, created on demand
 }
 
 // NewSinglyLinkedList creates and returns an empty singly linked list.
@@ -46,22 +78,37 @@ func (ll *LinkedList) IsEmpty() bool {
 	return ll.Size == 0
 }
 
-// Clear removes all elements from the list.
+// Clear removes all elements from the list and releases their nodes.
 func (ll *LinkedList) Clear() {
+	count := ll.Size
+	if ll.Allocator != nil {
+		// Stop after Size nodes in case the list is corrupt and has a cycle
+		current := ll.Head
+		for i := 0; current != nil && i < count; i++ {
+			next := current.Next
+			ll.release(current)
+			current = next
+		}
+	}
 	ll.Head = nil
 	ll.Size = 0
+	if count > 0 {
+		emit(ll, event.Cleared{Count: count})
+	}
 }
 
 // Prepend adds a new node with the given value at the beginning of the list.
 func (ll *LinkedList) Prepend(value any) {
-	newNode := &Node{Value: value, Next: ll.Head}
+	newNode := ll.newNode(value)
+	newNode.Next = ll.Head
 	ll.Head = newNode
 	ll.Size++
+	emit(ll, event.Inserted{Index: 0, Value: value})
 }
 
 // Append adds a new node with the given value at the end of the list.
 func (ll *LinkedList) Append(value any) {
-	newNode := &Node{Value: value, Next: nil}
+	newNode := ll.newNode(value)
 
 	if ll.Head == nil {
 		ll.Head = newNode
@@ -73,6 +120,7 @@ func (ll *LinkedList) Append(value any) {
 		lastNode.Next = newNode
 	}
 	ll.Size++
+	emit(ll, event.Inserted{Index: ll.Size - 1, Value: value})
 }
 
 // IntoSlice converts the list into a slice.
@@ -91,6 +139,8 @@ func (ll *LinkedList) FromSlice(slice []any) error {
 	if slice == nil {
 		return fmt.Errorf("cannot create list from nil slice")
 	}
+	ll.beginBatch()
+	defer ll.endBatch()
 	ll.Clear()
 	for _, value := range slice {
 		ll.Append(value)
@@ -114,6 +164,8 @@ func (ll *LinkedList) FromArray(arr []any) error {
 	if arr == nil {
 		return fmt.Errorf("cannot create list from nil array")
 	}
+	ll.beginBatch()
+	defer ll.endBatch()
 	ll.Clear()
 	for _, value := range arr {
 		ll.Append(value)
@@ -121,39 +173,72 @@ func (ll *LinkedList) FromArray(arr []any) error {
 	return nil
 }
 
-// Search finds the first occurrence of a value in the list and returns its index.
+// Search finds the first occurrence of a value in the list and returns its
+// index, or -1. Use SearchFunc to find a value that == can't compare.
 func (ll *LinkedList) Search(value any) int {
+	if !equality.Comparable(value) {
+		return -1
+	}
+	return ll.SearchFunc(func(v any) bool { return v == value })
+}
+
+// SearchFunc returns the index of the first value for which match returns
+// true, or -1. It is the same as IndexFunc, named to go with Search.
+func (ll *LinkedList) SearchFunc(match func(any) bool) int {
+	return ll.IndexFunc(match)
+}
+
+// IndexFunc returns the index of the first value for which match returns
+// true, or -1.
+func (ll *LinkedList) IndexFunc(match func(any) bool) int {
 	index := 0
-	current := ll.Head
-	for current != nil {
-		if current.Value == value {
+	for current := ll.Head; current != nil; current = current.Next {
+		if match(current.Value) {
 			return index
 		}
-		current = current.Next
 		index++
 	}
 	return -1
 }
 
+// LastIndexFunc returns the index of the last value for which match returns
+// true, or -1. It always walks the whole list.
+func (ll *LinkedList) LastIndexFunc(match func(any) bool) int {
+	last, index := -1, 0
+	for current := ll.Head; current != nil; current = current.Next {
+		if match(current.Value) {
+			last = index
+		}
+		index++
+	}
+	return last
+}
+
 // Shift removes and returns the first element from the list.
 func (ll *LinkedList) Shift() error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty, nothing to delete")
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
 	}
+	removed := ll.Head
 	ll.Head = ll.Head.Next
 	ll.Size--
+	emit(ll, event.Removed{Index: 0, Value: removed.Value})
+	ll.release(removed)
 	return nil
 }
 
 // Pop removes and returns the last element from the list.
 func (ll *LinkedList) Pop() error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty nothing to delete")
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
 	}
 	// If there's only one node in the list
 	if ll.Head.Next == nil {
+		removed := ll.Head
 		ll.Head = nil
 		ll.Size--
+		emit(ll, event.Removed{Index: 0, Value: removed.Value})
+		ll.release(removed)
 		return nil
 	}
 	// Traverse to the second-to-last node
@@ -162,61 +247,79 @@ func (ll *LinkedList) Pop() error {
 		current = current.Next
 	}
 	// Remove the last node
+	removed := current.Next
 	current.Next = nil
 	ll.Size--
+	emit(ll, event.Removed{Index: ll.Size, Value: removed.Value})
+	ll.release(removed)
 	return nil
 }
 
 // Delete removes the first occurrence of the specified value from the list.
+// Deleting a value that can't be compared with ==, such as a slice, returns
+// ErrNotComparable; use DeleteFunc instead.
 func (ll *LinkedList) Delete(value any) error {
+	if !equality.Comparable(value) {
+		return fmt.Errorf("%w: %T; use DeleteFunc", ErrNotComparable, value)
+	}
+	return ll.DeleteFunc(func(v any) bool { return v == value })
+}
+
+// DeleteFunc removes the first value for which match returns true.
+func (ll *LinkedList) DeleteFunc(match func(any) bool) error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty nothing to delete")
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
 	}
 
 	// If the value is in the head node
-	if ll.Head.Value == value {
-		ll.Head = ll.Head.Next
-		ll.Size--
-		return nil
+	if match(ll.Head.Value) {
+		return ll.Shift()
 	}
 	// Traverse the list to find the node to delete
+	index := 1
 	current := ll.Head
-	for current.Next != nil && current.Next.Value != value {
+	for current.Next != nil && !match(current.Next.Value) {
 		current = current.Next
+		index++
 	}
 	// If the value was not found
 	if current.Next == nil {
-		return fmt.Errorf("value not found in the list")
+		return fmt.Errorf("%w in the list", ErrNotFound)
 	}
 	// Delete the node
+	removed := current.Next
 	current.Next = current.Next.Next
 	ll.Size--
+	emit(ll, event.Removed{Index: index, Value: removed.Value})
+	ll.release(removed)
 	return nil
 }
 
 // Insert adds a new value at the specified index.
 func (ll *LinkedList) Insert(value any, index int) error {
 	if index < 0 || index > ll.Size {
-		return fmt.Errorf("index out of bounds")
+		return ErrIndexOutOfBounds
 	}
 	if index == 0 {
-		ll.Append(value)
+		ll.Prepend(value)
 		return nil
 	}
 	current := ll.Head
 	for i := 0; i < index-1; i++ {
 		current = current.Next
 	}
-	newNode := &Node{Value: value, Next: current.Next}
+	newNode := ll.newNode(value)
+	newNode.Next = current.Next
 	current.Next = newNode
 	ll.Size++
+	emit(ll, event.Inserted{Index: index, Value: value})
 	return nil
 }
 
 // DeleteAt removes the element at the specified index.
 func (ll *LinkedList) DeleteAt(index int) error {
-	if index < 0 || index > ll.Size {
-		return fmt.Errorf("index out of bounds")
+	if index < 0 || index >= ll.Size {
+		return ErrIndexOutOfBounds
 	}
 	if index == 0 {
 		ll.Shift()
@@ -228,15 +331,18 @@ func (ll *LinkedList) DeleteAt(index int) error {
 		current = current.Next
 	}
 
+	removed := current.Next
 	current.Next = current.Next.Next
 	ll.Size--
+	emit(ll, event.Removed{Index: index, Value: removed.Value})
+	ll.release(removed)
 	return nil
 }
 
 // Get returns the value at the specified index.
 func (ll *LinkedList) Get(index int) (any, error) {
-	if index < 0 || index > ll.Size {
-		return nil, fmt.Errorf("index out of bounds")
+	if index < 0 || index >= ll.Size {
+		return nil, ErrIndexOutOfBounds
 	}
 	current := ll.Head
 	for i := 0; i < index; i++ {
@@ -245,6 +351,21 @@ func (ll *LinkedList) Get(index int) (any, error) {
 	return current.Value, nil
 }
 
+// Set replaces the value at the specified index.
+func (ll *LinkedList) Set(index int, value any) error {
+	if index < 0 || index >= ll.Size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.Size)
+	}
+	current := ll.Head
+	for i := 0; i < index; i++ {
+		current = current.Next
+	}
+	old := current.Value
+	current.Value = value
+	emit(ll, event.Updated{Index: index, Old: old, Value: value})
+	return nil
+}
+
 // Reverse reverses the order of elements in the list.
 func (ll *LinkedList) Reverse() {
 	var prev *Node
@@ -256,51 +377,175 @@ func (ll *LinkedList) Reverse() {
 		current = nextTemp
 	}
 	ll.Head = prev
+	if ll.Size > 1 {
+		emit(ll, event.Reordered{})
+	}
 }
 
-// Contains checks if a value exists in the list.
-func (ll *LinkedList) Contains(value any) bool {
-	current := ll.Head
-	for current != nil {
-		if current.Value == value {
-			return true
+// All returns an iterator over the indices and values of the list from head
+// to tail.
+func (ll *LinkedList) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		index := 0
+		for current := ll.Head; current != nil; current = current.Next {
+			if !yield(index, current.Value) {
+				return
+			}
+			index++
 		}
-		current = current.Next
 	}
-	return false
 }
 
-// Unique removes duplicate values from the list.
+// Contains checks if a value exists in the list. It finds exactly what
+// Search finds; use ContainsFunc for values that == can't compare.
+func (ll *LinkedList) Contains(value any) bool {
+	return ll.Search(value) >= 0
+}
+
+// ContainsFunc reports whether match returns true for any value.
+func (ll *LinkedList) ContainsFunc(match func(any) bool) bool {
+	return ll.IndexFunc(match) >= 0
+}
+
+// Unique removes duplicate values from the list. If any value can't be
+// compared with ==, such as a slice, it returns ErrNotComparable and leaves
+// the list unchanged; use UniqueBy instead.
 func (ll *LinkedList) Unique() error {
+	return ll.UniqueBy(nil)
+}
+
+// UniqueBy removes every value whose key, as returned by key, matches the
+// key of an earlier value. Keys must be comparable with ==; a nil key
+// function uses the values themselves.
+func (ll *LinkedList) UniqueBy(key func(any) any) error {
 	if ll.Head == nil {
-		return fmt.Errorf("list is empty")
+		return ErrEmpty
+	}
+	if key == nil {
+		key = identity
+	}
+
+	// Compute every key first, so a bad one leaves the list unchanged
+	keys := make([]any, 0, ll.Size)
+	for current := ll.Head; current != nil; current = current.Next {
+		k := key(current.Value)
+		if !equality.Comparable(k) {
+			return fmt.Errorf("%w: %T at index %d", ErrNotComparable, k, len(keys))
+		}
+		keys = append(keys, k)
 	}
 	if ll.Size <= 1 {
 		return nil
 	}
 
+	ll.beginBatch()
+	defer ll.endBatch()
+
 	visited := make(map[any]bool)
 	current := ll.Head
-	visited[current.Value] = true
-	for current.Next != nil {
-		if visited[current.Next.Value] {
+	visited[keys[0]] = true
+	index := 1
+	for i := 1; current.Next != nil; i++ {
+		if visited[keys[i]] {
+			removed := current.Next
 			current.Next = current.Next.Next
 			ll.Size--
+			emit(ll, event.Removed{Index: index, Value: removed.Value})
+			ll.release(removed)
 		} else {
-			visited[current.Next.Value] = true
+			visited[keys[i]] = true
 			current = current.Next
+			index++
 		}
 	}
 	return nil
 }
 
+// Clone returns a shallow copy of the list: a new list holding the same
+// values, with the same Allocator but without the subscribers.
+func (ll *LinkedList) Clone() *LinkedList {
+	return ll.CloneFunc(identity)
+}
+
+// CloneFunc returns a copy of the list holding copy(v) for every value v,
+// for deep copies of values such as slices and pointers.
+func (ll *LinkedList) CloneFunc(copy func(any) any) *LinkedList {
+	clone := &LinkedList{Allocator: ll.Allocator}
+	var last *Node
+	for current := ll.Head; current != nil; current = current.Next {
+		node := clone.newNode(copy(current.Value))
+		if last == nil {
+			clone.Head = node
+		} else {
+			last.Next = node
+		}
+		last = node
+		clone.Size++
+	}
+	return clone
+}
+
+// Equal reports whether other holds the same values in the same order,
+// compared with ==. Values that can't be compared with ==, such as slices,
+// are never equal; use EqualFunc for them. A nil list counts as empty.
+func (ll *LinkedList) Equal(other *LinkedList) bool {
+	return ll.EqualFunc(other, equality.Equal)
+}
+
+// EqualFunc reports whether other holds the same number of values as the
+// list and eq returns true for every pair of values at the same index. A nil
+// list counts as empty.
+func (ll *LinkedList) EqualFunc(other *LinkedList, eq func(a, b any) bool) bool {
+	if other == nil {
+		return ll.Size == 0
+	}
+	if ll.Size != other.Size {
+		return false
+	}
+	for a, b := ll.Head, other.Head; a != nil && b != nil; a, b = a.Next, b.Next {
+		if !eq(a.Value, b.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares the list with other lexicographically, using cmp to
+// compare values. It returns the result of cmp for the first pair of values
+// that differ, or, when one list is a prefix of the other, -1 if the list is
+// the shorter one and +1 if other is. It returns 0 if the lists are equal. A
+// nil list counts as empty.
+func (ll *LinkedList) Compare(other *LinkedList, cmp func(a, b any) int) int {
+	a := ll.Head
+	var b *Node
+	if other != nil {
+		b = other.Head
+	}
+	for ; a != nil && b != nil; a, b = a.Next, b.Next {
+		if c := cmp(a.Value, b.Value); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a != nil:
+		return 1
+	case b != nil:
+		return -1
+	}
+	return 0
+}
+
 // Merge combines the current list with another list.
 func (ll *LinkedList) Merge(list *LinkedList) error {
 	if list == nil {
 		return fmt.Errorf("cannot merge with nil list")
 	}
+	ll.beginBatch()
+	defer ll.endBatch()
+
+	// Take the count first so merging a list into itself terminates
 	current := list.Head
-	for current != nil {
+	for count := list.Size; count > 0; count-- {
 		ll.Append(current.Value)
 		current = current.Next
 	}
@@ -319,6 +564,13 @@ func (ll *LinkedList) Sort() error {
 		return nil
 	}
 
+	changed := false
+	defer func() {
+		if changed {
+			emit(ll, event.Reordered{})
+		}
+	}()
+
 	swapped := true
 	for swapped {
 		swapped = false
@@ -330,32 +582,35 @@ func (ll *LinkedList) Sort() error {
 			case int:
 				y, ok := current.Next.Value.(int)
 				if !ok {
-					return fmt.Errorf("mismatched types in list")
+					return ErrMismatchedTypes
 				}
 				if x > y {
 					current.Value, current.Next.Value = current.Next.Value, current.Value
 					swapped = true
+					changed = true
 				}
 			case string:
 				y, ok := current.Next.Value.(string)
 				if !ok {
-					return fmt.Errorf("mismatched types in list")
+					return ErrMismatchedTypes
 				}
 				if x > y {
 					current.Value, current.Next.Value = current.Next.Value, current.Value
 					swapped = true
+					changed = true
 				}
 			case float64:
 				y, ok := current.Next.Value.(float64)
 				if !ok {
-					return fmt.Errorf("mismatched types in list")
+					return ErrMismatchedTypes
 				}
 				if x > y {
 					current.Value, current.Next.Value = current.Next.Value, current.Value
 					swapped = true
+					changed = true
 				}
 			default:
-				return fmt.Errorf("unsupported type for sorting")
+				return ErrUnsupportedType
 			}
 			current = current.Next
 		}
@@ -363,29 +618,10 @@ func (ll *LinkedList) Sort() error {
 	return nil
 }
 
-// hasCycle detects if the list contains a cycle using Floyd's algorithm.
-func (ll *LinkedList) hasCycle() bool {
-	if ll.Head == nil || ll.Head.Next == nil {
-		return false
-	}
-
-	slow := ll.Head
-	fast := ll.Head
-
-	for fast != nil && fast.Next != nil {
-		slow = slow.Next
-		fast = fast.Next.Next
-		if slow == fast {
-			return true
-		}
-	}
-	return false
-}
-
 // GetMiddle returns the middle element of the list.
 func (ll *LinkedList) GetMiddle() (any, error) {
 	if ll.Head == nil {
-		return nil, fmt.Errorf("list is empty")
+		return nil, ErrEmpty
 	}
 
 	slow := ll.Head
@@ -402,7 +638,12 @@ func (ll *LinkedList) GetMiddle() (any, error) {
 // Validate checks the integrity of the list structure.
 func (ll *LinkedList) Validate() error {
 	if ll.Head == nil && ll.Size != 0 {
-		return fmt.Errorf("empty list has non-zero size")
+		return fmt.Errorf("%w: empty list has non-zero size", ErrCorrupt)
+	}
+
+	// Check for cycles in the list
+	if cycle, ok := ll.DetectCycle(); ok {
+		return fmt.Errorf("%w: list contains a cycle of length %d starting at index %d", ErrCorrupt, cycle.Length, cycle.Start)
 	}
 
 	// Count nodes to verify Size
@@ -411,18 +652,13 @@ func (ll *LinkedList) Validate() error {
 	for current != nil {
 		count++
 		if count > ll.Size {
-			return fmt.Errorf("list contains more nodes than Size indicates")
+			return fmt.Errorf("%w: list contains more nodes than Size indicates", ErrCorrupt)
 		}
 		current = current.Next
 	}
 
 	if count != ll.Size {
-		return fmt.Errorf("actual node count (%d) differs from Size (%d)", count, ll.Size)
-	}
-
-	// Check for cycles in the list
-	if ll.hasCycle() {
-		return fmt.Errorf("list contains a cycle")
+		return fmt.Errorf("%w: actual node count (%d) differs from Size (%d)", ErrCorrupt, count, ll.Size)
 	}
 
 	return nil
@@ -440,3 +676,114 @@ func (ll *LinkedList) reverseHelper(node *Node) {
 		fmt.Print(" -> ")
 	}
 }
+
+// Move relocates the element at index from so that it ends up at index to.
+func (ll *LinkedList) Move(from, to int) error {
+	if from < 0 || from >= ll.Size || to < 0 || to >= ll.Size {
+		return ErrIndexOutOfBounds
+	}
+	if from == to {
+		return nil
+	}
+
+	// Unlink the node at from
+	var node *Node
+	if from == 0 {
+		node = ll.Head
+		ll.Head = node.Next
+	} else {
+		prev := ll.Head
+		for i := 0; i < from-1; i++ {
+			prev = prev.Next
+		}
+		node = prev.Next
+		prev.Next = node.Next
+	}
+
+	// Link it back in at to
+	if to == 0 {
+		node.Next = ll.Head
+		ll.Head = node
+	} else {
+		prev := ll.Head
+		for i := 0; i < to-1; i++ {
+			prev = prev.Next
+		}
+		node.Next = prev.Next
+		prev.Next = node
+	}
+	emit(ll, event.Moved{From: from, To: to, Value: node.Value})
+	return nil
+}
+
+// Subscribe registers fn to be called after every change to the list and
+// returns a function that unsubscribes it. Bulk operations such as FromSlice,
+// Merge and Unique deliver their changes as a single event.Batch.
+//
+// Subscribe creates the list's event hub on first use, so like the other
+// methods it must not run concurrently with changes to the list. The
+// function it returns may be called from any goroutine.
+func (ll *LinkedList) Subscribe(fn func(event.Event)) (unsubscribe func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.Subscribe(fn)
+}
+
+// SubscribeChan is like Subscribe but delivers events on a channel with the
+// given buffer size. Mutations block while the buffer is full. Unsubscribing
+// closes the channel.
+func (ll *LinkedList) SubscribeChan(buffer int) (<-chan event.Event, func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.SubscribeChan(buffer)
+}
+
+// identity returns v.
+func identity(v any) any {
+	return v
+}
+
+// newNode returns a node holding value, taken from the allocator if there
+// is one.
+func (ll *LinkedList) newNode(value any) *Node {
+	if ll.Allocator == nil {
+		return &Node{Value: value}
+	}
+	node := ll.Allocator.New()
+	node.Value = value
+	return node
+}
+
+// release hands a node the list no longer uses to the allocator, which
+// zeroes it for reuse. Without an allocator the node is left as it is, since
+// a caller may still be holding it and walking on from it.
+func (ll *LinkedList) release(node *Node) {
+	if ll.Allocator != nil {
+		ll.Allocator.Free(node)
+	}
+}
+
+// emit sends e to the list's subscribers, if there are any. It is generic
+// so that e is only boxed into an event.Event, which allocates, when someone
+// is listening.
+func emit[E event.Event](ll *LinkedList, e E) {
+	if ll.observers.Active() {
+		ll.observers.Emit(e)
+	}
+}
+
+// beginBatch starts collecting events for a bulk operation.
+func (ll *LinkedList) beginBatch() {
+	if ll.observers != nil {
+		ll.observers.Begin()
+	}
+}
+
+// endBatch delivers the events collected since beginBatch.
+func (ll *LinkedList) endBatch() {
+	if ll.observers != nil {
+		ll.observers.End()
+	}
+}
@@ -0,0 +1,130 @@
+package singly
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/JustMrNone/ll/internal/paging"
+)
+
+// ErrInvalidCursor is returned by Page for a cursor it did not issue, or one
+// past the end of the list. A cursor from the doubly or indexed list's Page
+// works here too, and fails with this same error when it doesn't fit.
+var ErrInvalidCursor = paging.ErrInvalidCursor
+
+// Sublist is a live window onto a run of a singly linked list: it reads the
+// list's current values and writes through to it. Every access by index
+// walks from Head, so prefer All for reading the whole window. It holds
+// indices rather than nodes, so inserting or deleting before the window
+// shifts which values it covers.
+type Sublist struct {
+	list *LinkedList
+	span paging.Span
+}
+
+// Range returns a sublist of the values from index from up to, but not
+// including, index to.
+func (ll *LinkedList) Range(from, to int) (*Sublist, error) {
+	span, ok := paging.NewSpan(from, to, ll.Size)
+	if !ok {
+		return nil, fmt.Errorf("%w: range [%d, %d), size %d", ErrIndexOutOfBounds, from, to, ll.Size)
+	}
+	return &Sublist{list: ll, span: span}, nil
+}
+
+// Len returns the number of values in the sublist, which shrinks if the list
+// loses the values at its end.
+func (s *Sublist) Len() int {
+	return s.span.Len(s.list.Size)
+}
+
+// Get returns the value at index within the sublist.
+func (s *Sublist) Get(index int) (any, error) {
+	pos, ok := s.span.Pos(index, s.list.Size)
+	if !ok {
+		return nil, s.outOfBounds(index)
+	}
+	return s.list.Get(pos)
+}
+
+// Set replaces the value at index within the sublist.
+func (s *Sublist) Set(index int, value any) error {
+	pos, ok := s.span.Pos(index, s.list.Size)
+	if !ok {
+		return s.outOfBounds(index)
+	}
+	return s.list.Set(pos, value)
+}
+
+// Insert adds a value at index within the sublist, which grows by one.
+// Inserting at Len places the value right after the sublist's last one.
+func (s *Sublist) Insert(value any, index int) error {
+	n := s.Len()
+	pos, ok := s.span.InsertPos(index, s.list.Size)
+	if !ok {
+		return s.outOfBounds(index)
+	}
+	if err := s.list.Insert(value, pos); err != nil {
+		return err
+	}
+	s.span.Resize(n + 1)
+	return nil
+}
+
+// DeleteAt removes the value at index within the sublist, which shrinks by
+// one.
+func (s *Sublist) DeleteAt(index int) error {
+	n := s.Len()
+	pos, ok := s.span.Pos(index, s.list.Size)
+	if !ok {
+		return s.outOfBounds(index)
+	}
+	if err := s.list.DeleteAt(pos); err != nil {
+		return err
+	}
+	s.span.Resize(n - 1)
+	return nil
+}
+
+// All returns an iterator over the indices within the sublist and values,
+// from first to last. It walks from Head to the sublist once, then along it.
+func (s *Sublist) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		n := s.Len()
+		current := s.list.Head
+		for i := 0; i < s.span.From && current != nil; i++ {
+			current = current.Next
+		}
+		for i := 0; i < n && current != nil; i++ {
+			if !yield(i, current.Value) {
+				return
+			}
+			current = current.Next
+		}
+	}
+}
+
+// IntoSlice returns the values of the sublist as a slice.
+func (s *Sublist) IntoSlice() []any {
+	values := make([]any, 0, s.Len())
+	for _, v := range s.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// outOfBounds returns the error for an index outside the sublist.
+func (s *Sublist) outOfBounds(index int) error {
+	return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, s.Len())
+}
+
+// Page returns up to limit values starting at cursor, and the cursor of the
+// value after them, with the same cursor rules as the doubly list's Page.
+// Each page walks from Head to the cursor, so paging through the whole list
+// is O(n²/limit).
+func (ll *LinkedList) Page(cursor string, limit int) ([]any, string, error) {
+	return paging.Page(cursor, limit, ll.Size, func(from, to int) []any {
+		page, _ := ll.Range(from, to)
+		return page.IntoSlice()
+	})
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/JustMrNone/ll/alloc"
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/listtest"
+	"github.com/JustMrNone/ll/singly"
+)
+
+func TestArena(t *testing.T) {
+	a := alloc.NewArena[doubly.Node](2)
+	first, second, third := a.New(), a.New(), a.New()
+	if a.Blocks() != 2 || a.Live() != 3 {
+		t.Errorf("Expected 2 blocks and 3 live values, got %d and %d", a.Blocks(), a.Live())
+	}
+	if first == second || second == third {
+		t.Error("Expected distinct values")
+	}
+
+	second.Value = "x"
+	a.Free(second)
+	if second.Value != nil {
+		t.Errorf("Expected Free to zero the value, got %v", second.Value)
+	}
+	if reused := a.New(); reused != second {
+		t.Error("Expected New to reuse the freed value")
+	}
+	if a.Blocks() != 2 || a.Live() != 3 {
+		t.Errorf("Expected reuse without a new block, got %d blocks and %d live values", a.Blocks(), a.Live())
+	}
+
+	if a := alloc.NewArena[singly.Node](0); a.New() == nil || a.Blocks() != 1 {
+		t.Error("Expected a zero block size to fall back to the default")
+	}
+}
+
+func TestPool(t *testing.T) {
+	var p alloc.Pool[singly.Node]
+	node := p.New()
+	node.Value = 1
+	p.Free(node)
+	if node.Value != nil {
+		t.Errorf("Expected Free to zero the value, got %v", node.Value)
+	}
+	if node := p.New(); node.Value != nil || node.Next != nil {
+		t.Errorf("Expected a zeroed node, got %+v", node)
+	}
+}
+
+func TestSinglyArenaConformance(t *testing.T) {
+	listtest.Run(t, newSinglyArena, listtest.SinglyErrors)
+	listtest.RunModel(t, newSinglyArena, listtest.SinglyErrors, &quick.Config{MaxCount: 200})
+}
+
+func TestDoublyArenaConformance(t *testing.T) {
+	listtest.Run(t, newDoublyArena, listtest.DoublyErrors)
+	listtest.RunModel(t, newDoublyArena, listtest.DoublyErrors, &quick.Config{MaxCount: 200})
+}
+
+func TestDoublyPoolConformance(t *testing.T) {
+	pool := alloc.NewPool[doubly.Node]()
+	listtest.RunModel(t, func() listtest.List {
+		return listtest.Doubly{LinkedList: &doubly.LinkedList{Allocator: pool}}
+	}, listtest.DoublyErrors, &quick.Config{MaxCount: 200})
+}
+
+// newSinglyArena returns a singly list with a small arena, so tests cross
+// block boundaries.
+func newSinglyArena() listtest.List {
+	return listtest.Singly{LinkedList: &singly.LinkedList{Allocator: alloc.NewArena[singly.Node](3)}}
+}
+
+func newDoublyArena() listtest.List {
+	return listtest.Doubly{LinkedList: &doubly.LinkedList{Allocator: alloc.NewArena[doubly.Node](3)}}
+}
+
+func TestListsReleaseNodes(t *testing.T) {
+	t.Run("Singly", func(t *testing.T) {
+		a := alloc.NewArena[singly.Node](4)
+		list := &singly.LinkedList{Allocator: a}
+		list.FromSlice([]any{1, 2, 2, 3, 4, 5, 6})
+		head := list.Head
+
+		steps := []func() error{
+			list.Shift,
+			list.Pop,
+			func() error { return list.Delete(4) },
+			func() error { return list.DeleteAt(2) },
+			list.Unique,
+		}
+		for i, step := range steps {
+			if err := step(); err != nil {
+				t.Fatalf("Step %d failed: %v", i, err)
+			}
+			if a.Live() != list.Size {
+				t.Errorf("After step %d expected %d live nodes, got %d", i, list.Size, a.Live())
+			}
+		}
+		if head.Value != nil || head.Next != nil {
+			t.Errorf("Expected the shifted node to be zeroed, got %+v", head)
+		}
+		if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{2, 5}) {
+			t.Errorf("Expected [2 5], got %v", got)
+		}
+
+		list.Clear()
+		if a.Live() != 0 {
+			t.Errorf("Expected Clear to free every node, %d still live", a.Live())
+		}
+		list.Append(7)
+		if a.Blocks() != 2 {
+			t.Errorf("Expected freed nodes to be reused, got %d blocks", a.Blocks())
+		}
+	})
+
+	t.Run("Doubly", func(t *testing.T) {
+		a := alloc.NewArena[doubly.Node](4)
+		list := &doubly.LinkedList{Allocator: a}
+		list.FromSlice([]any{1, 2, 2, 3, 4, 5, 6})
+		tail := list.Tail
+
+		steps := []func() error{
+			list.Shift,
+			list.Pop,
+			func() error { return list.Delete(4) },
+			func() error { return list.DeleteAt(2) },
+			list.Unique,
+			func() error { return list.Insert(8, 1) },
+		}
+		for i, step := range steps {
+			if err := step(); err != nil {
+				t.Fatalf("Step %d failed: %v", i, err)
+			}
+			if a.Live() != list.Size {
+				t.Errorf("After step %d expected %d live nodes, got %d", i, list.Size, a.Live())
+			}
+		}
+		if tail.Value != nil || tail.Prev != nil {
+			t.Errorf("Expected the popped node to be zeroed, got %+v", tail)
+		}
+		if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{2, 8, 5}) {
+			t.Errorf("Expected [2 8 5], got %v", got)
+		}
+
+		// Remove leaves the node to the caller
+		node := list.Head
+		if err := list.Remove(node); err != nil {
+			t.Fatal(err)
+		}
+		if node.Value != 2 {
+			t.Errorf("Expected Remove to keep the node's value, got %v", node.Value)
+		}
+
+		list.Clear()
+		if a.Live() != 1 {
+			t.Errorf("Expected only the removed node to stay live, got %d", a.Live())
+		}
+	})
+}
+
+func TestReleaseWithoutAllocator(t *testing.T) {
+	s := singly.NewSinglyLinkedList()
+	s.FromSlice([]any{1, 2, 3})
+	head := s.Head
+	s.Shift()
+	if head.Value != 1 || head.Next != s.Head {
+		t.Errorf("Expected the shifted node to keep its value and link, got %+v", head)
+	}
+
+	d := doubly.NewDoublyLinkedList()
+	d.FromSlice([]any{1, 2, 3})
+	tail := d.Tail
+	d.Pop()
+	if tail.Value != 3 || tail.Prev != d.Tail {
+		t.Errorf("Expected the popped node to keep its value and link, got %+v", tail)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/internal/bench"
+)
+
+// benchOp runs the named operation for every element type, size and
+// implementation, as sub-benchmarks named type/size/impl.
+func benchOp(b *testing.B, name string) {
+	for _, op := range bench.Ops {
+		if op.Name != name {
+			continue
+		}
+		for _, c := range bench.Cases([]bench.Op{op}, bench.Types, bench.Sizes, bench.Impls) {
+			b.Run(c.Name()[len(name)+1:], c.Benchmark)
+		}
+		return
+	}
+	b.Fatalf("no benchmark operation named %s", name)
+}
+
+func BenchmarkAppend(b *testing.B)         { benchOp(b, "Append") }
+func BenchmarkPrepend(b *testing.B)        { benchOp(b, "Prepend") }
+func BenchmarkInsert(b *testing.B)         { benchOp(b, "Insert") }
+func BenchmarkInsertRandom(b *testing.B)   { benchOp(b, "InsertRandom") }
+func BenchmarkFromSlice(b *testing.B)      { benchOp(b, "FromSlice") }
+func BenchmarkMerge(b *testing.B)          { benchOp(b, "Merge") }
+func BenchmarkGet(b *testing.B)            { benchOp(b, "Get") }
+func BenchmarkGetAll(b *testing.B)         { benchOp(b, "GetAll") }
+func BenchmarkGetMiddle(b *testing.B)      { benchOp(b, "GetMiddle") }
+func BenchmarkSearch(b *testing.B)         { benchOp(b, "Search") }
+func BenchmarkContains(b *testing.B)       { benchOp(b, "Contains") }
+func BenchmarkWalk(b *testing.B)           { benchOp(b, "Walk") }
+func BenchmarkIntoSlice(b *testing.B)      { benchOp(b, "IntoSlice") }
+func BenchmarkReverse(b *testing.B)        { benchOp(b, "Reverse") }
+func BenchmarkMove(b *testing.B)           { benchOp(b, "Move") }
+func BenchmarkShift(b *testing.B)          { benchOp(b, "Shift") }
+func BenchmarkPop(b *testing.B)            { benchOp(b, "Pop") }
+func BenchmarkDeleteAt(b *testing.B)       { benchOp(b, "DeleteAt") }
+func BenchmarkDeleteAtRandom(b *testing.B) { benchOp(b, "DeleteAtRandom") }
+func BenchmarkDelete(b *testing.B)         { benchOp(b, "Delete") }
+func BenchmarkQueue(b *testing.B)          { benchOp(b, "Queue") }
+func BenchmarkRefill(b *testing.B)         { benchOp(b, "Refill") }
+func BenchmarkSort(b *testing.B)           { benchOp(b, "Sort") }
+func BenchmarkUnique(b *testing.B)         { benchOp(b, "Unique") }
+
+// TestBenchWorkloads checks that every implementation ends a workload
+// holding the same values, so the benchmarks compare like with like.
+func TestBenchWorkloads(t *testing.T) {
+	for _, c := range bench.Cases(bench.Ops, bench.Types, []int{1, 10, 11}, bench.Impls) {
+		baseline := c
+		baseline.Impl = bench.Impls[len(bench.Impls)-1]
+		t.Run(c.Name(), func(t *testing.T) {
+			got, want := c.Result(), baseline.Result()
+			if len(got) != 0 || len(want) != 0 {
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Expected %v like %s, got %v", want, baseline.Impl.Name, got)
+				}
+			}
+		})
+	}
+}
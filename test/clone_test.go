@@ -0,0 +1,158 @@
+package test
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/JustMrNone/ll/alloc"
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/indexed"
+	"github.com/JustMrNone/ll/interop"
+	"github.com/JustMrNone/ll/singly"
+)
+
+func compareAny(a, b any) int {
+	return cmp.Compare(a.(int), b.(int))
+}
+
+func copySlice(v any) any {
+	if s, ok := v.([]int); ok {
+		return slices.Clone(s)
+	}
+	return v
+}
+
+func TestDoublyCloneEqual(t *testing.T) {
+	list := newDoubly(1, []int{2}, 3)
+	list.Allocator = alloc.NewPool[doubly.Node]()
+	events := 0
+	list.Subscribe(func(event.Event) { events++ })
+
+	shallow, deep := list.Clone(), list.CloneFunc(copySlice)
+	if err := deep.Validate(); err != nil {
+		t.Fatalf("Clone is invalid: %v", err)
+	}
+	list.Head.Next.Value.([]int)[0] = 9
+	if got := shallow.Head.Next.Value.([]int)[0]; got != 9 {
+		t.Errorf("Shallow clone should share values, got %d", got)
+	}
+	if got := deep.Head.Next.Value.([]int)[0]; got != 2 {
+		t.Errorf("Deep clone should not share values, got %d", got)
+	}
+	shallow.Append(4)
+	if list.Size != 3 || events != 0 || shallow.Allocator != list.Allocator {
+		t.Errorf("Clone should be independent but keep the allocator: size %d, %d events", list.Size, events)
+	}
+
+	// Slices are never equal with ==, but EqualFunc can compare them
+	if list.Equal(list.Clone()) {
+		t.Errorf("Lists holding slices should not be Equal")
+	}
+	sliceEq := func(a, b any) bool {
+		x, okX := a.([]int)
+		y, okY := b.([]int)
+		if okX && okY {
+			return slices.Equal(x, y)
+		}
+		return a == b
+	}
+	if !list.EqualFunc(list.CloneFunc(copySlice), sliceEq) {
+		t.Errorf("Expected EqualFunc to match a deep clone")
+	}
+
+	a := newDoubly(1, 2, 3)
+	for _, c := range []struct {
+		other   *doubly.LinkedList
+		equal   bool
+		compare int
+	}{
+		{newDoubly(1, 2, 3), true, 0},
+		{newDoubly(1, 2), false, 1},
+		{newDoubly(1, 2, 3, 0), false, -1},
+		{newDoubly(1, 3), false, -1},
+		{newDoubly(0, 9, 9, 9), false, 1},
+		{nil, false, 1},
+	} {
+		if got := a.Equal(c.other); got != c.equal {
+			t.Errorf("Equal(%v) = %v, want %v", c.other, got, c.equal)
+		}
+		if got := a.Compare(c.other, compareAny); got != c.compare {
+			t.Errorf("Compare(%v) = %d, want %d", c.other, got, c.compare)
+		}
+	}
+	if !doubly.NewDoublyLinkedList().Equal(nil) {
+		t.Errorf("An empty list should equal a nil one")
+	}
+}
+
+func TestSinglyCloneEqual(t *testing.T) {
+	list := singly.NewSinglyLinkedList()
+	list.FromSlice([]any{1, []int{2}, 3})
+
+	deep := list.CloneFunc(copySlice)
+	if err := deep.Validate(); err != nil || deep.Size != 3 {
+		t.Fatalf("Clone is invalid: size %d, %v", deep.Size, err)
+	}
+	list.Head.Next.Value.([]int)[0] = 9
+	if got := deep.Head.Next.Value.([]int)[0]; got != 2 {
+		t.Errorf("Deep clone should not share values, got %d", got)
+	}
+
+	a, b := singly.NewSinglyLinkedList(), singly.NewSinglyLinkedList()
+	a.FromSlice([]any{1, 2, 3})
+	b.FromSlice([]any{1, 2, 4})
+	if !a.Equal(a.Clone()) || a.Equal(b) {
+		t.Errorf("Equal gave the wrong result")
+	}
+	if a.Compare(b, compareAny) >= 0 || b.Compare(a, compareAny) <= 0 || a.Compare(a.Clone(), compareAny) != 0 {
+		t.Errorf("Compare gave the wrong order")
+	}
+}
+
+func TestIndexedCloneEqual(t *testing.T) {
+	list := indexed.NewIndexedList()
+	list.FromSlice([]any{3, 1, 2})
+	clone := list.Clone()
+	clone.Set(0, 7)
+	if !list.Equal(list.Clone()) || list.Equal(clone) {
+		t.Errorf("Equal gave the wrong result")
+	}
+	if list.Compare(clone, compareAny) >= 0 {
+		t.Errorf("Expected [3 1 2] to sort before [7 1 2]")
+	}
+	if err := clone.Validate(); err != nil {
+		t.Errorf("Clone is invalid: %v", err)
+	}
+}
+
+func TestInterop(t *testing.T) {
+	d := newDoubly(1, "two", 3.0)
+	s := interop.ToSingly(d)
+	if err := s.Validate(); err != nil || !slices.Equal(s.IntoSlice(), d.IntoSlice()) {
+		t.Fatalf("ToSingly gave %v, %v", s.IntoSlice(), err)
+	}
+	back := interop.ToDoubly(s)
+	if err := back.Validate(); err != nil || !back.Equal(d) {
+		t.Fatalf("ToDoubly gave %v, %v", back.IntoSlice(), err)
+	}
+
+	if !interop.Equal(s, d) {
+		t.Errorf("Expected the converted lists to be equal")
+	}
+	s.Append(4)
+	if interop.Equal(s, d) {
+		t.Errorf("Lists of different lengths should not be equal")
+	}
+	if !interop.Equal(nil, doubly.NewDoublyLinkedList()) {
+		t.Errorf("A nil list should equal an empty one")
+	}
+	slice := newDoubly([]int{1})
+	if interop.Equal(interop.ToSingly(slice), slice) {
+		t.Errorf("Lists holding slices should not be Equal")
+	}
+	if !interop.EqualFunc(interop.ToSingly(slice), slice, func(a, b any) bool { return slices.Equal(a.([]int), b.([]int)) }) {
+		t.Errorf("Expected EqualFunc to compare slices")
+	}
+}
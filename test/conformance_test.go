@@ -0,0 +1,19 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JustMrNone/ll/listtest"
+)
+
+func TestSinglyConformance(t *testing.T) {
+	listtest.Run(t, listtest.NewSingly, listtest.SinglyErrors)
+}
+
+func TestDoublyConformance(t *testing.T) {
+	listtest.Run(t, listtest.NewDoubly, listtest.DoublyErrors)
+}
+
+func TestIndexedConformance(t *testing.T) {
+	listtest.Run(t, listtest.NewIndexed, listtest.IndexedErrors)
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/crdt"
+)
+
+// editRandomly performs n random local edits on r and returns the ops they
+// produced, encoded as JSON the way they would travel between replicas.
+func editRandomly(t *testing.T, rng *rand.Rand, r *crdt.RGA, n int) [][]byte {
+	var wire [][]byte
+	for i := 0; i < n; i++ {
+		ids := r.IDs()
+		var op crdt.Op
+		var err error
+		if len(ids) > 0 && rng.Intn(3) == 0 {
+			op, err = r.Delete(ids[rng.Intn(len(ids))])
+		} else {
+			after := crdt.ID{}
+			if len(ids) > 0 && rng.Intn(4) != 0 {
+				after = ids[rng.Intn(len(ids))]
+			}
+			op, err = r.InsertAfter(after, fmt.Sprintf("%s-%d", r.Replica(), i))
+		}
+		if err != nil {
+			t.Fatalf("Local edit failed: %v", err)
+		}
+		data, err := json.Marshal(op)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		wire = append(wire, data)
+	}
+	return wire
+}
+
+// deliverShuffled applies every op in wire to r in a random order, with some
+// ops delivered twice.
+func deliverShuffled(t *testing.T, rng *rand.Rand, r *crdt.RGA, wire [][]byte) {
+	shuffled := append([][]byte(nil), wire...)
+	for i := 0; i < len(wire)/4; i++ {
+		shuffled = append(shuffled, wire[rng.Intn(len(wire))])
+	}
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	for _, data := range shuffled {
+		var op crdt.Op
+		if err := json.Unmarshal(data, &op); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if err := r.Apply(op); err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+	}
+}
+
+// sync delivers every replica's ops to all the others and checks that they
+// converge.
+func syncReplicas(t *testing.T, rng *rand.Rand, replicas []*crdt.RGA, outboxes [][][]byte) {
+	for i, r := range replicas {
+		var incoming [][]byte
+		for j, out := range outboxes {
+			if i != j {
+				incoming = append(incoming, out...)
+			}
+		}
+		deliverShuffled(t, rng, r, incoming)
+	}
+
+	want := replicas[0].Values()
+	for _, r := range replicas {
+		if r.Pending() != 0 {
+			t.Errorf("Replica %s still has %d pending ops", r.Replica(), r.Pending())
+		}
+		if got := r.Values(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Replica %s diverged:\n got %v\nwant %v", r.Replica(), got, want)
+		}
+	}
+}
+
+func TestRGAConvergence(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		t.Run(fmt.Sprintf("Seed %d", seed), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(seed))
+			replicas := make([]*crdt.RGA, 4)
+			for i := range replicas {
+				replicas[i] = crdt.NewRGA(fmt.Sprintf("r%d", i))
+			}
+
+			for round := 0; round < 3; round++ {
+				outboxes := make([][][]byte, len(replicas))
+				for i, r := range replicas {
+					outboxes[i] = editRandomly(t, rng, r, 15)
+				}
+				syncReplicas(t, rng, replicas, outboxes)
+
+				// Everyone has seen everything, so all tombstones are stable
+				for _, r := range replicas {
+					r.GC(func(crdt.ID) bool { return true })
+				}
+			}
+		})
+	}
+}
+
+func TestRGAEdgeCases(t *testing.T) {
+	r := crdt.NewRGA("a")
+	if _, err := r.InsertAfter(crdt.ID{Counter: 7, Replica: "b"}, "x"); err == nil {
+		t.Error("Expected error when inserting after an unknown element")
+	}
+
+	op, _ := r.InsertAfter(crdt.ID{}, "x")
+	if _, err := r.Delete(op.ID); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := r.Delete(op.ID); err == nil {
+		t.Error("Expected error when deleting a tombstone")
+	}
+
+	// A delete that arrives before its insert waits for it
+	other := crdt.NewRGA("b")
+	other.Apply(crdt.Op{Kind: crdt.OpDelete, ID: op.ID})
+	if other.Pending() != 1 {
+		t.Errorf("Expected 1 pending op, got %d", other.Pending())
+	}
+	other.Apply(op)
+	if other.Pending() != 0 || other.Len() != 0 {
+		t.Errorf("Expected the delete to apply once the insert arrived, got %v", other.Values())
+	}
+
+	if err := other.Apply(crdt.Op{Kind: "move", ID: op.ID}); err == nil {
+		t.Error("Expected error for unknown op kind")
+	}
+}
+
+func TestRGAGCRedelivery(t *testing.T) {
+	a, b := crdt.NewRGA("a"), crdt.NewRGA("b")
+	insert, _ := a.InsertAfter(crdt.ID{}, "x")
+	del, _ := a.Delete(insert.ID)
+	b.Apply(insert)
+	b.Apply(del)
+	if n := b.GC(func(crdt.ID) bool { return true }); n != 1 {
+		t.Fatalf("Expected GC to remove 1 tombstone, removed %d", n)
+	}
+
+	// Redelivered ops for the collected element are dropped
+	for _, op := range []crdt.Op{insert, del} {
+		if err := b.Apply(op); err != nil {
+			t.Errorf("Apply(%v) failed: %v", op.Kind, err)
+		}
+	}
+	if b.Len() != 0 || b.Pending() != 0 {
+		t.Errorf("Expected an empty replica with nothing pending, got %v and %d pending", b.Values(), b.Pending())
+	}
+
+	// An insert anchored to the collected element can't be placed
+	late := crdt.Op{Kind: crdt.OpInsert, ID: crdt.ID{Counter: 9, Replica: "c"}, After: insert.ID, Value: "y"}
+	if err := b.Apply(late); !errors.Is(err, crdt.ErrCollected) {
+		t.Errorf("Expected ErrCollected, got %v", err)
+	}
+	if b.Pending() != 0 {
+		t.Errorf("Expected the late insert not to wait, got %d pending", b.Pending())
+	}
+}
+
+func TestRGAMaxPending(t *testing.T) {
+	r := crdt.NewRGA("a")
+	r.MaxPending = 2
+	for i := uint64(1); i <= 2; i++ {
+		if err := r.Apply(crdt.Op{Kind: crdt.OpDelete, ID: crdt.ID{Counter: i, Replica: "b"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Apply(crdt.Op{Kind: crdt.OpDelete, ID: crdt.ID{Counter: 3, Replica: "b"}}); !errors.Is(err, crdt.ErrPendingFull) {
+		t.Errorf("Expected ErrPendingFull, got %v", err)
+	}
+	if r.Pending() != 2 {
+		t.Errorf("Expected 2 pending ops, got %d", r.Pending())
+	}
+}
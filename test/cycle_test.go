@@ -0,0 +1,88 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// singlyNodeAt returns the node at index in a singly list without trusting Size.
+func singlyNodeAt(list *singly.LinkedList, index int) *singly.Node {
+	node := list.Head
+	for i := 0; i < index; i++ {
+		node = node.Next
+	}
+	return node
+}
+
+func TestSinglyCycle(t *testing.T) {
+	for _, c := range []struct{ size, start int }{{1, 0}, {2, 0}, {5, 0}, {5, 2}, {6, 5}, {40, 17}} {
+		list := singly.NewSinglyLinkedList()
+		for i := 0; i < c.size; i++ {
+			list.Append(i)
+		}
+		if _, ok := list.DetectCycle(); ok {
+			t.Fatalf("Expected no cycle in a healthy list of %d", c.size)
+		}
+
+		// Point the last node back at the node at index start
+		singlyNodeAt(list, c.size-1).Next = singlyNodeAt(list, c.start)
+		list.Size = 1000
+
+		cycle, ok := list.DetectCycle()
+		if !ok {
+			t.Fatalf("Expected a cycle starting at %d in a list of %d", c.start, c.size)
+		}
+		want := singly.Cycle{Start: c.start, Length: c.size - c.start, TailLength: c.start}
+		if cycle != want {
+			t.Errorf("Expected %+v, got %+v", want, cycle)
+		}
+		if err := list.Validate(); !errors.Is(err, singly.ErrCorrupt) {
+			t.Errorf("Expected Validate to report corruption, got %v", err)
+		}
+
+		if !list.BreakCycle() {
+			t.Fatal("Expected BreakCycle to find the cycle")
+		}
+		if list.Size != c.size {
+			t.Errorf("Expected Size %d after BreakCycle, got %d", c.size, list.Size)
+		}
+		if err := list.Validate(); err != nil {
+			t.Errorf("List validation failed after BreakCycle: %v", err)
+		}
+		if list.BreakCycle() {
+			t.Error("Expected no cycle left to break")
+		}
+	}
+}
+
+func TestDoublyCycle(t *testing.T) {
+	t.Run("Next Chain", func(t *testing.T) {
+		list := newDoubly(0, 1, 2, 3, 4)
+		list.Tail.Next = list.Head.Next
+		cycle, ok := list.DetectCycle()
+		if !ok || cycle != (doubly.Cycle{Start: 1, Length: 4, TailLength: 1}) {
+			t.Errorf("Unexpected cycle %+v (found: %v)", cycle, ok)
+		}
+		if !list.BreakCycle() || list.Size != 5 || list.Tail.Value != 4 {
+			t.Errorf("Expected BreakCycle to restore a 5-node chain, got size %d", list.Size)
+		}
+	})
+
+	t.Run("Prev Chain", func(t *testing.T) {
+		list := newDoubly(0, 1, 2, 3)
+		list.Head.Prev = list.Tail
+		cycle, ok := list.DetectPrevCycle()
+		if !ok || cycle != (doubly.Cycle{Start: 0, Length: 4, TailLength: 0}) {
+			t.Errorf("Unexpected prev cycle %+v (found: %v)", cycle, ok)
+		}
+		if !list.BreakPrevCycle() || list.Head.Prev != nil {
+			t.Error("Expected BreakPrevCycle to clear the head's prev pointer")
+		}
+		if err := list.Validate(); err != nil {
+			t.Errorf("List validation failed after BreakPrevCycle: %v", err)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/diff"
+	"github.com/JustMrNone/ll/doubly"
+)
+
+func newDoubly(values ...any) *doubly.LinkedList {
+	list := doubly.NewDoublyLinkedList()
+	list.FromSlice(values)
+	return list
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name  string
+		a, b  []any
+		edits int
+	}{
+		{"Empty", []any{}, []any{}, 0},
+		{"Identical", []any{1, 2, 3}, []any{1, 2, 3}, 0},
+		{"Insert Only", []any{}, []any{1, 2}, 2},
+		{"Delete Only", []any{1, 2}, []any{}, 2},
+		{"Classic", []any{"a", "b", "c", "a", "b", "b", "a"}, []any{"c", "b", "a", "b", "a", "c"}, 5},
+		{"Replace Middle", []any{1, 2, 3, 4}, []any{1, 5, 3, 4}, 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a, b := newDoubly(c.a...), newDoubly(c.b...)
+			patch := diff.Diff(a, b, nil)
+
+			edits := 0
+			for _, op := range patch {
+				if op.Kind != diff.Keep {
+					edits++
+				}
+			}
+			if edits != c.edits {
+				t.Errorf("Expected %d edits, got %d: %v", c.edits, edits, patch)
+			}
+
+			if err := diff.Apply(a, patch); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if !reflect.DeepEqual(a.IntoArray(), b.IntoArray()) {
+				t.Errorf("Expected %v after Apply, got %v", b.IntoArray(), a.IntoArray())
+			}
+			if err := a.Validate(); err != nil {
+				t.Errorf("List validation failed after Apply: %v", err)
+			}
+		})
+	}
+}
+
+func TestDiffCustomEquality(t *testing.T) {
+	type item struct {
+		ID   int
+		Name string
+	}
+	a := newDoubly(item{1, "one"}, item{2, "two"})
+	b := newDoubly(item{2, "TWO"}, item{1, "ONE"})
+	sameID := func(x, y any) bool { return x.(item).ID == y.(item).ID }
+
+	patch := diff.Diff(a, b, sameID)
+	if len(patch) != 3 {
+		t.Errorf("Expected 3 ops when matching by ID, got %v", patch)
+	}
+}
+
+// TestDiffRandom checks that applying the diff of random lists turns one
+// into the other, and that no script is longer than deleting everything and
+// inserting everything.
+func TestDiffRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(27, 0))
+	random := func() []any {
+		values := make([]any, rng.IntN(40))
+		for i := range values {
+			values[i] = rng.IntN(4)
+		}
+		return values
+	}
+	for round := 0; round < 300; round++ {
+		a, b := random(), random()
+		list := newDoubly(a...)
+		patch := diff.Diff(list, newDoubly(b...), nil)
+		edits := 0
+		for _, op := range patch {
+			if op.Kind != diff.Keep {
+				edits++
+			}
+		}
+		if edits > len(a)+len(b) {
+			t.Fatalf("Diff of %v and %v has %d edits", a, b, edits)
+		}
+		if err := diff.Apply(list, patch); err != nil {
+			t.Fatalf("Apply failed for %v to %v: %v", a, b, err)
+		}
+		if got := list.IntoSlice(); !reflect.DeepEqual(got, b) && len(b) > 0 {
+			t.Fatalf("Expected %v after Apply, got %v", b, got)
+		}
+	}
+}
+
+func TestDiffEdgeCases(t *testing.T) {
+	// A nil list counts as empty
+	if patch := diff.Diff(nil, newDoubly(1), nil); len(patch) != 1 || patch[0].Kind != diff.Insert {
+		t.Errorf("Expected a single insert, got %v", patch)
+	}
+
+	// Values that can't be compared with == never match instead of panicking
+	a := newDoubly([]int{1}, 2)
+	patch := diff.Diff(a, newDoubly([]int{1}, 2), nil)
+	if len(patch) != 3 {
+		t.Errorf("Expected the slices to be replaced, got %v", patch)
+	}
+	if err := diff.Apply(a, patch); !errors.Is(err, diff.ErrMismatch) {
+		t.Errorf("Expected ErrMismatch for a slice value, got %v", err)
+	}
+}
+
+func TestPatchApplyMismatch(t *testing.T) {
+	patch := diff.Diff(newDoubly(1, 2, 3), newDoubly(1, 3), nil)
+	if err := diff.Apply(newDoubly(1, 2), patch); !errors.Is(err, diff.ErrMismatch) {
+		t.Errorf("Expected ErrMismatch for a list of the wrong length, got %v", err)
+	}
+
+	// Right length, wrong values: the list must be left alone
+	list := newDoubly(1, 9, 3)
+	if err := diff.Apply(list, patch); !errors.Is(err, diff.ErrMismatch) {
+		t.Errorf("Expected ErrMismatch for a changed value, got %v", err)
+	}
+	if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{1, 9, 3}) {
+		t.Errorf("Expected the list to be unchanged, got %v", got)
+	}
+
+	// JSON decodes numbers as float64, which ApplyFunc can allow for
+	data, _ := json.Marshal(patch)
+	var decoded diff.Patch
+	json.Unmarshal(data, &decoded)
+	list = newDoubly(1, 2, 3)
+	asFloat := func(x, y any) bool { return x == any(float64(y.(int))) }
+	if err := diff.ApplyFunc(list, decoded, asFloat); err != nil {
+		t.Fatalf("ApplyFunc failed: %v", err)
+	}
+	if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{1, 3}) {
+		t.Errorf("Expected [1 3], got %v", got)
+	}
+}
+
+func TestPatchJSON(t *testing.T) {
+	patch := diff.Diff(newDoubly("a", "b"), newDoubly("b", "c"), nil)
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded diff.Patch
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(patch, decoded) {
+		t.Errorf("Expected %v after round trip, got %v", patch, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`[{"kind":"swap"}]`), &decoded); err == nil {
+		t.Error("Expected error for unknown op kind")
+	}
+}
+
+func TestUnified(t *testing.T) {
+	patch := diff.Diff(newDoubly(1, 2, 3, 4, 5, 6, 7, 8), newDoubly(1, 2, 3, 4, 9, 6, 7, 8), nil)
+	want := "@@ -4,3 +4,3 @@\n 4\n-5\n+9\n 6\n"
+	if got := diff.Unified(patch, 1); got != want {
+		t.Errorf("Expected unified diff %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,253 @@
+package test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/durable"
+)
+
+// durableOps is a sequence of mutations used by the recovery tests.
+var durableOps = []func(l *durable.List) error{
+	func(l *durable.List) error { return l.Append("a") },
+	func(l *durable.List) error { return l.Append("b") },
+	func(l *durable.List) error { return l.Prepend("start") },
+	func(l *durable.List) error { return l.Insert(1.5, 2) },
+	func(l *durable.List) error { return l.DeleteAt(1) },
+	func(l *durable.List) error { return l.Pop() },
+	func(l *durable.List) error { return l.Append(map[string]any{"k": "v"}) },
+	func(l *durable.List) error { return l.Shift() },
+	func(l *durable.List) error { return l.Append(true) },
+}
+
+func TestDurableReopen(t *testing.T) {
+	for _, opts := range []durable.Options{
+		{Sync: durable.SyncAlways},
+		{Sync: durable.SyncBatch, SyncEvery: 2},
+		{Sync: durable.SyncNever, CompactEvery: 3},
+	} {
+		dir := t.TempDir()
+		l, err := durable.Open(dir, opts)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		for _, op := range durableOps {
+			if err := op(l); err != nil {
+				t.Fatalf("Operation failed: %v", err)
+			}
+		}
+		want := l.IntoSlice()
+		if err := l.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		reopened, err := durable.Open(dir, opts)
+		if err != nil {
+			t.Fatalf("Reopen failed: %v", err)
+		}
+		if got := reopened.IntoSlice(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v after reopen, got %v", want, got)
+		}
+		reopened.Close()
+	}
+}
+
+func TestDurableCrashAtEveryOffset(t *testing.T) {
+	dir := t.TempDir()
+	l, err := durable.Open(dir, durable.Options{Sync: durable.SyncNever})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	// Remember the log size and the contents after every op
+	logPath := filepath.Join(dir, "wal.log")
+	offsets := []int64{0}
+	states := [][]any{nil}
+	for _, op := range durableOps {
+		if err := op(l); err != nil {
+			t.Fatalf("Operation failed: %v", err)
+		}
+		info, err := os.Stat(logPath)
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		offsets = append(offsets, info.Size())
+		states = append(states, l.IntoSlice())
+	}
+	l.Close()
+	full, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	for cut := 0; cut <= len(full); cut++ {
+		// The last op whose record made it to disk in full
+		last := 0
+		for i, off := range offsets {
+			if off <= int64(cut) {
+				last = i
+			}
+		}
+
+		crashed := t.TempDir()
+		if err := os.WriteFile(filepath.Join(crashed, "wal.log"), full[:cut], 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		recovered, err := durable.Open(crashed, durable.Options{})
+		if err != nil {
+			t.Fatalf("Recovery after crash at offset %d failed: %v", cut, err)
+		}
+		if got := recovered.IntoSlice(); !reflect.DeepEqual(got, states[last]) {
+			t.Fatalf("Crash at offset %d: expected %v, got %v", cut, states[last], got)
+		}
+
+		// The torn tail is gone, so new records land on a clean boundary
+		if err := recovered.Append("after"); err != nil {
+			t.Fatalf("Append after recovery failed: %v", err)
+		}
+		recovered.Close()
+		again, err := durable.Open(crashed, durable.Options{})
+		if err != nil {
+			t.Fatalf("Second recovery after crash at offset %d failed: %v", cut, err)
+		}
+		if again.Len() != len(states[last])+1 {
+			t.Fatalf("Crash at offset %d: expected %d values after second recovery, got %d", cut, len(states[last])+1, again.Len())
+		}
+		again.Close()
+	}
+}
+
+func TestDurableCompactionCrash(t *testing.T) {
+	dir := t.TempDir()
+	l, _ := durable.Open(dir, durable.Options{})
+	for _, op := range durableOps {
+		op(l)
+	}
+	want := l.IntoSlice()
+	logBefore, _ := os.ReadFile(filepath.Join(dir, "wal.log"))
+	if err := l.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	l.Close()
+
+	// Pretend the process died after the snapshot was renamed into place but
+	// before the log was truncated.
+	os.WriteFile(filepath.Join(dir, "wal.log"), logBefore, 0o644)
+	recovered, err := durable.Open(dir, durable.Options{})
+	if err != nil {
+		t.Fatalf("Recovery failed: %v", err)
+	}
+	if got := recovered.IntoSlice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	recovered.Close()
+}
+
+func TestDurableCorruption(t *testing.T) {
+	dir := t.TempDir()
+	l, _ := durable.Open(dir, durable.Options{})
+	l.Append("a")
+	l.Append("b")
+	l.Close()
+
+	// Flip a payload byte in the first of two records: that's corruption,
+	// not a torn write, and must not be silently dropped.
+	logPath := filepath.Join(dir, "wal.log")
+	data, _ := os.ReadFile(logPath)
+	data[10] ^= 0xff
+	os.WriteFile(logPath, data, 0o644)
+	if _, err := durable.Open(dir, durable.Options{}); !errors.Is(err, durable.ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt for a corrupt record in the middle of the log, got %v", err)
+	}
+
+	// An impossible length is damage too, even though the record it starts
+	// runs past the end of the file. The log must be left as it is.
+	binary.LittleEndian.PutUint32(data[0:4], 0xffffffff)
+	data[10] ^= 0xff
+	os.WriteFile(logPath, data, 0o644)
+	if _, err := durable.Open(dir, durable.Options{}); !errors.Is(err, durable.ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt for an oversized length, got %v", err)
+	}
+	if after, _ := os.ReadFile(logPath); len(after) != len(data) {
+		t.Errorf("Expected the damaged log to keep its %d bytes, got %d", len(data), len(after))
+	}
+
+	l, _ = durable.Open(t.TempDir(), durable.Options{})
+	if err := l.DeleteAt(0); err == nil {
+		t.Error("Expected error when deleting from empty list")
+	}
+	if err := l.Insert("x", 1); err == nil {
+		t.Error("Expected error when inserting out of bounds")
+	}
+	l.Close()
+}
+
+func TestDurableCompactError(t *testing.T) {
+	dir := t.TempDir()
+	var compactErr error
+	l, err := durable.Open(dir, durable.Options{
+		CompactEvery:   2,
+		OnCompactError: func(err error) { compactErr = err },
+	})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	// A directory where the temporary snapshot goes makes compaction fail
+	if err := os.Mkdir(filepath.Join(dir, "snapshot.tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	l.Append("a")
+	if err := l.Append("b"); err != nil {
+		t.Errorf("Expected the append to succeed although compaction failed, got %v", err)
+	}
+	if compactErr == nil {
+		t.Error("Expected OnCompactError to be called")
+	}
+	if got := l.IntoSlice(); !reflect.DeepEqual(got, []any{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", got)
+	}
+
+	// Compaction is retried once it can succeed
+	os.Remove(filepath.Join(dir, "snapshot.tmp"))
+	compactErr = nil
+	l.Append("c")
+	if compactErr != nil {
+		t.Errorf("Expected compaction to succeed, got %v", compactErr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "snapshot")); err != nil {
+		t.Errorf("Expected a snapshot after compaction: %v", err)
+	}
+}
+
+func TestDurableErrors(t *testing.T) {
+	l, err := durable.Open(t.TempDir(), durable.Options{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	if err := l.Shift(); !errors.Is(err, doubly.ErrEmpty) {
+		t.Errorf("Expected ErrEmpty from Shift, got %v", err)
+	}
+	if err := l.Pop(); !errors.Is(err, doubly.ErrEmpty) {
+		t.Errorf("Expected ErrEmpty from Pop, got %v", err)
+	}
+	if err := l.Insert("a", 1); !errors.Is(err, doubly.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds from Insert, got %v", err)
+	}
+	if err := l.DeleteAt(0); !errors.Is(err, doubly.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds from DeleteAt, got %v", err)
+	}
+
+	// Encoder errors come back wrapped
+	if err := l.Append(func() {}); err == nil || errors.Unwrap(err) == nil {
+		t.Errorf("Expected a wrapped encode error, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/singly"
+)
+
+func TestSinglyEvents(t *testing.T) {
+	list := singly.NewSinglyLinkedList()
+	var got []event.Event
+	unsubscribe := list.Subscribe(func(e event.Event) { got = append(got, e) })
+
+	list.Append(1)
+	list.Prepend(0)
+	list.Delete(1)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(got))
+	}
+	if got[0] != (event.Inserted{Index: 0, Value: 1}) {
+		t.Errorf("Unexpected first event %#v", got[0])
+	}
+	if got[2] != (event.Removed{Index: 1, Value: 1}) {
+		t.Errorf("Unexpected last event %#v", got[2])
+	}
+
+	unsubscribe()
+	list.Append(2)
+	if len(got) != 3 {
+		t.Errorf("Expected no events after unsubscribe, got %d", len(got))
+	}
+}
+
+func TestDoublyEvents(t *testing.T) {
+	t.Run("Batch", func(t *testing.T) {
+		list := doubly.NewDoublyLinkedList()
+		list.Append(9)
+		var got []event.Event
+		list.Subscribe(func(e event.Event) { got = append(got, e) })
+
+		list.FromSlice([]any{1, 2, 1})
+		if len(got) != 1 {
+			t.Fatalf("Expected a single batch event, got %d events", len(got))
+		}
+		batch, ok := got[0].(event.Batch)
+		if !ok {
+			t.Fatalf("Expected event.Batch, got %T", got[0])
+		}
+		if len(batch.Events) != 4 {
+			t.Errorf("Expected Cleared plus 3 inserts, got %d events", len(batch.Events))
+		}
+		if batch.Events[0] != (event.Cleared{Count: 1}) {
+			t.Errorf("Unexpected first batched event %#v", batch.Events[0])
+		}
+
+		got = nil
+		list.Unique()
+		batch = got[0].(event.Batch)
+		if len(batch.Events) != 1 || batch.Events[0] != (event.Removed{Index: 2, Value: 1}) {
+			t.Errorf("Unexpected Unique events %#v", batch.Events)
+		}
+	})
+
+	t.Run("Channel", func(t *testing.T) {
+		list := doubly.NewDoublyLinkedList()
+		ch, unsubscribe := list.SubscribeChan(4)
+		list.FromSlice([]any{3, 1, 2})
+		list.Move(2, 0)
+		list.Sort()
+		unsubscribe()
+
+		var got []event.Event
+		for e := range ch {
+			got = append(got, e)
+		}
+		if len(got) != 3 {
+			t.Fatalf("Expected 3 events, got %d", len(got))
+		}
+		if got[1] != (event.Moved{From: 2, To: 0, Value: 2}) {
+			t.Errorf("Unexpected move event %#v", got[1])
+		}
+		if _, ok := got[2].(event.Reordered); !ok {
+			t.Errorf("Expected event.Reordered, got %T", got[2])
+		}
+		if err := list.Validate(); err != nil {
+			t.Errorf("List validation failed after Move: %v", err)
+		}
+	})
+}
+
+// TestEventChanUnsubscribeConcurrent unsubscribes channel subscribers from
+// their reading goroutines while the list keeps emitting into full buffers,
+// and subscribes and unsubscribes callbacks alongside. Run it with -race.
+func TestEventChanUnsubscribeConcurrent(t *testing.T) {
+	list := doubly.NewDoublyLinkedList()
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for i := 0; i < 8; i++ {
+		ch, unsubscribe := list.SubscribeChan(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				if _, ok := <-ch; !ok {
+					return
+				}
+				if n == 3 {
+					unsubscribe()
+					unsubscribe() // Idempotent
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				unsubscribe := list.Subscribe(func(event.Event) {})
+				unsubscribe()
+			}
+		}
+	}()
+
+	for i := 0; i < 2000; i++ {
+		list.Append(i)
+	}
+	close(stop)
+	wg.Wait()
+}
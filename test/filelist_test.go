@@ -0,0 +1,142 @@
+package test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JustMrNone/ll/filelist"
+)
+
+func TestFileList(t *testing.T) {
+	for _, cache := range []int{0, 2} {
+		path := filepath.Join(t.TempDir(), "list.db")
+		list, err := filelist.Open(path, filelist.Options{RecordSize: 32, CacheSize: cache})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+
+		list.Append("b")
+		list.Append("d")
+		list.Prepend("a")
+		list.Insert("c", 2)
+		if got, _ := list.IntoSlice(); !reflect.DeepEqual(got, []any{"a", "b", "c", "d"}) {
+			t.Errorf("Expected [a b c d], got %v", got)
+		}
+		if v, err := list.Get(3); err != nil || v != "d" {
+			t.Errorf("Expected d at index 3, got %v (%v)", v, err)
+		}
+		if _, err := list.Get(4); err == nil {
+			t.Error("Expected error for Get past the end")
+		}
+
+		// Deleted records are reused instead of growing the file
+		list.Delete("b")
+		list.DeleteAt(0)
+		info, _ := os.Stat(path)
+		list.Append("e")
+		list.Prepend("z")
+		if after, _ := os.Stat(path); after.Size() != info.Size() {
+			t.Errorf("Expected freed records to be reused, file grew from %d to %d", info.Size(), after.Size())
+		}
+		if err := list.Validate(); err != nil {
+			t.Errorf("List validation failed: %v", err)
+		}
+		if err := list.Append(strings.Repeat("x", 64)); err == nil {
+			t.Error("Expected error for a value larger than the record size")
+		}
+		list.Close()
+
+		reopened, err := filelist.Open(path, filelist.Options{})
+		if err != nil {
+			t.Fatalf("Reopen failed: %v", err)
+		}
+		if got, _ := reopened.IntoSlice(); !reflect.DeepEqual(got, []any{"z", "c", "d", "e"}) {
+			t.Errorf("Expected [z c d e] after reopen, got %v", got)
+		}
+		reopened.Close()
+	}
+}
+
+func TestFileListValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.db")
+	list, _ := filelist.Open(path, filelist.Options{RecordSize: 32})
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	list.Close()
+
+	// Records are 21 header bytes plus 32 payload bytes after the 64-byte
+	// file header. Link the first record straight to the third, so the
+	// second is orphaned.
+	const first, second, third = 64, 64 + 53, 64 + 2*53
+	file, _ := os.OpenFile(path, os.O_RDWR, 0)
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, third)
+	file.WriteAt(buf, first+1)
+	binary.LittleEndian.PutUint64(buf, first)
+	file.WriteAt(buf, third+9)
+	binary.LittleEndian.PutUint64(buf, 2)
+	file.WriteAt(buf, 32)
+	file.Close()
+
+	list, _ = filelist.Open(path, filelist.Options{})
+	err := list.Validate()
+	if err == nil || !strings.Contains(err.Error(), "orphaned records at offsets 117") {
+		t.Errorf("Expected the second record to be reported as orphaned, got %v", err)
+	}
+	if !errors.Is(err, filelist.ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt from Validate, got %v", err)
+	}
+
+	// Break the back link of the last record as well
+	list.Close()
+	file, _ = os.OpenFile(path, os.O_RDWR, 0)
+	binary.LittleEndian.PutUint64(buf, second)
+	file.WriteAt(buf, third+9)
+	file.Close()
+	list, _ = filelist.Open(path, filelist.Options{})
+	if err := list.Validate(); err == nil || !strings.Contains(err.Error(), "has prev 117") {
+		t.Errorf("Expected a broken prev link to be reported, got %v", err)
+	}
+	list.Close()
+}
+
+func TestFileListErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.db")
+	list, err := filelist.Open(path, filelist.Options{RecordSize: 32})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := list.Delete("a"); !errors.Is(err, filelist.ErrEmpty) {
+		t.Errorf("Expected ErrEmpty from Delete, got %v", err)
+	}
+	list.Append("a")
+	if err := list.Delete("b"); !errors.Is(err, filelist.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from Delete, got %v", err)
+	}
+	if _, err := list.Get(1); !errors.Is(err, filelist.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds from Get, got %v", err)
+	}
+	if err := list.Insert("b", 2); !errors.Is(err, filelist.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds from Insert, got %v", err)
+	}
+	if err := list.DeleteAt(-1); !errors.Is(err, filelist.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds from DeleteAt, got %v", err)
+	}
+	list.Close()
+
+	// A header claiming more records than the file holds
+	file, _ := os.OpenFile(path, os.O_RDWR, 0)
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, 1<<20)
+	file.WriteAt(buf, 48)
+	file.Close()
+	if _, err := filelist.Open(path, filelist.Options{}); !errors.Is(err, filelist.ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt from Open, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/event"
+)
+
+func TestDoublySet(t *testing.T) {
+	list := newDoubly(0, 1, 2, 3)
+	var got []event.Event
+	list.Subscribe(func(e event.Event) { got = append(got, e) })
+
+	if err := list.Set(2, "two"); err != nil {
+		t.Fatal(err)
+	}
+	if values := list.IntoSlice(); !reflect.DeepEqual(values, []any{0, 1, "two", 3}) {
+		t.Errorf("Expected [0 1 two 3], got %v", values)
+	}
+	if len(got) != 1 || got[0] != (event.Updated{Index: 2, Old: 2, Value: "two"}) {
+		t.Errorf("Expected a single Updated event, got %#v", got)
+	}
+	for _, index := range []int{-1, 4} {
+		if err := list.Set(index, 0); !errors.Is(err, doubly.ErrIndexOutOfBounds) {
+			t.Errorf("Expected ErrIndexOutOfBounds for index %d, got %v", index, err)
+		}
+	}
+}
+
+// TestDoublyFinger mixes index operations, which move the finger, with every
+// other kind of mutation, and checks each index against the list's contents
+// after every step.
+func TestDoublyFinger(t *testing.T) {
+	rng := rand.New(rand.NewPCG(42, 0))
+	list := newDoubly(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	next := 10
+
+	mutations := []struct {
+		name string
+		fn   func()
+	}{
+		{"Append", func() { list.Append(next) }},
+		{"Prepend", func() { list.Prepend(next) }},
+		{"Insert", func() { list.Insert(next, rng.IntN(list.Size+1)) }},
+		{"Set", func() { list.Set(rng.IntN(list.Size), next) }},
+		{"DeleteAt", func() { list.DeleteAt(rng.IntN(list.Size)) }},
+		{"Delete", func() { v, _ := list.Get(rng.IntN(list.Size)); list.Delete(v) }},
+		{"Shift", func() { list.Shift() }},
+		{"Pop", func() { list.Pop() }},
+		{"Move", func() { list.Move(rng.IntN(list.Size), rng.IntN(list.Size)) }},
+		{"Reverse", func() { list.Reverse() }},
+		{"Sort", func() { list.Sort() }},
+		{"Unique", func() { list.Set(0, 0); list.Set(list.Size-1, 0); list.Unique() }},
+		{"InsertAfter", func() { list.InsertAfter(list.Head.Next, next) }},
+		{"Remove", func() { list.Remove(list.Tail.Prev) }},
+		{"MoveToBack", func() { list.MoveToBack(list.Head.Next) }},
+		{"Merge", func() { list.Merge(newDoubly(next, next+1)) }},
+		{"FromSlice", func() { list.FromSlice(list.IntoSlice()) }},
+		{"Repair", func() { list.Repair() }},
+		// Direct edits through the exported fields
+		{"UnlinkHead", func() { list.Head = list.Head.Next; list.Head.Prev = nil; list.Size-- }},
+		{"SwapValues", func() { list.Head.Value, list.Tail.Value = list.Tail.Value, list.Head.Value }},
+	}
+
+	for step := 0; step < 2000; step++ {
+		if list.Size < 6 {
+			list.FromSlice(append(list.IntoSlice(), next, next+1, next+2, next+3))
+			next += 4
+		}
+		// Leave the finger somewhere before mutating
+		list.Get(rng.IntN(list.Size))
+		m := mutations[rng.IntN(len(mutations))]
+		m.fn()
+		next++
+
+		if err := list.Validate(); err != nil {
+			t.Fatalf("Step %d (%s): %v", step, m.name, err)
+		}
+		want := list.IntoSlice()
+		indices := rng.Perm(len(want))
+		if step%2 == 0 {
+			// Sequential access walks from the finger every time
+			for i := range indices {
+				indices[i] = i
+			}
+		}
+		for _, i := range indices {
+			if got, err := list.Get(i); err != nil || got != want[i] {
+				t.Fatalf("Step %d (%s): Get(%d) = %v, %v; want %v in %v", step, m.name, i, got, err, want[i], want)
+			}
+		}
+	}
+}
+
+// TestDoublyFingerAfterUnique checks that the finger doesn't outlive Unique
+// when an append brings the list back to its old size.
+func TestDoublyFingerAfterUnique(t *testing.T) {
+	list := newDoubly(1, 1, 2, 3, 4)
+	list.Get(3) // Finger on 3
+	list.Unique()
+	list.Append(5)
+	if v, _ := list.Get(3); v != 4 {
+		t.Errorf("Expected 4 at index 3, got %v", v)
+	}
+}
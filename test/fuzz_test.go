@@ -0,0 +1,227 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/crdt"
+	"github.com/JustMrNone/ll/diff"
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/durable"
+	"github.com/JustMrNone/ll/filelist"
+	"github.com/JustMrNone/ll/listtest"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// The seed corpus for every target lives in testdata/fuzz. Run a target with
+// go test ./test -run '^$' -fuzz FuzzDoublyOps.
+
+func FuzzSinglyOps(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := listtest.DecodeOps(data)
+		if err := listtest.Replay(listtest.NewSingly, listtest.SinglyErrors, ops); err != nil {
+			t.Fatalf("%v\n\n%s", err, ops.GoString())
+		}
+	})
+}
+
+func FuzzDoublyOps(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := listtest.DecodeOps(data)
+		if err := listtest.Replay(listtest.NewDoubly, listtest.DoublyErrors, ops); err != nil {
+			t.Fatalf("%v\n\n%s", err, ops.GoString())
+		}
+	})
+}
+
+func FuzzIndexedOps(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := listtest.DecodeOps(data)
+		if err := listtest.Replay(listtest.NewIndexed, listtest.IndexedErrors, ops); err != nil {
+			t.Fatalf("%v\n\n%s", err, ops.GoString())
+		}
+	})
+}
+
+// fuzzValues turns bytes into a slice of mixed values.
+func fuzzValues(data []byte) []any {
+	values := make([]any, len(data))
+	for i, b := range data {
+		switch b % 3 {
+		case 0:
+			values[i] = int(b)
+		case 1:
+			values[i] = string(rune(b))
+		default:
+			values[i] = float64(b) / 2
+		}
+	}
+	return values
+}
+
+func FuzzSliceRoundTrip(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		values := fuzzValues(data)
+
+		s := singly.NewSinglyLinkedList()
+		s.Append("stale")
+		if err := s.FromSlice(values); err != nil {
+			t.Fatalf("singly FromSlice failed: %v", err)
+		}
+		d := doubly.NewDoublyLinkedList()
+		d.Append("stale")
+		if err := d.FromSlice(values); err != nil {
+			t.Fatalf("doubly FromSlice failed: %v", err)
+		}
+		for name, got := range map[string][]any{
+			"singly IntoSlice": s.IntoSlice(), "singly IntoArray": s.IntoArray(),
+			"doubly IntoSlice": d.IntoSlice(), "doubly IntoArray": d.IntoArray(),
+		} {
+			if len(got) != len(values) || (len(got) > 0 && !reflect.DeepEqual(got, values)) {
+				t.Fatalf("%s: expected %v, got %v", name, values, got)
+			}
+		}
+		if s.Size != len(values) || s.Validate() != nil || d.Size != len(values) || d.Validate() != nil {
+			t.Fatalf("Lists are inconsistent after FromSlice of %d values", len(values))
+		}
+
+		if err := d.FromArray(s.IntoArray()); err != nil || !reflect.DeepEqual(d.IntoArray(), s.IntoArray()) {
+			t.Fatalf("FromArray did not round-trip: %v", err)
+		}
+	})
+}
+
+func FuzzPatchJSON(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var patch diff.Patch
+		if err := json.Unmarshal(data, &patch); err != nil {
+			return
+		}
+
+		list := newDoubly(0.0, 1.0, 2.0)
+		if err := diff.Apply(list, patch); err == nil {
+			if err := list.Validate(); err != nil {
+				t.Fatalf("Apply left an invalid list: %v", err)
+			}
+		}
+		diff.Unified(patch, 1)
+
+		encoded, err := json.Marshal(patch)
+		if err != nil {
+			t.Fatalf("Marshal failed for a decoded patch: %v", err)
+		}
+		var decoded diff.Patch
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed for %s: %v", encoded, err)
+		}
+		if !reflect.DeepEqual(patch, decoded) {
+			t.Fatalf("Patch changed in a JSON round trip: %v became %v", patch, decoded)
+		}
+	})
+}
+
+func FuzzCRDTOps(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := crdt.NewRGA("fuzz")
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var op crdt.Op
+			if err := dec.Decode(&op); err != nil {
+				break
+			}
+			r.Apply(op)
+		}
+		if r.Len() != len(r.Values()) || r.Len() != len(r.IDs()) {
+			t.Fatalf("Len %d disagrees with %d values and %d IDs", r.Len(), len(r.Values()), len(r.IDs()))
+		}
+
+		// Local edits must keep working whatever arrived
+		if _, err := r.InsertAfter(crdt.ID{}, "local"); err != nil {
+			t.Fatalf("InsertAfter failed: %v", err)
+		}
+		if r.Values()[0] != "local" {
+			t.Fatalf("Expected the local insert at the front, got %v", r.Values())
+		}
+	})
+}
+
+// fuzzDurable writes data as the named durable file and checks that Open
+// either fails or yields a list that keeps working across a reopen.
+func fuzzDurable(t *testing.T, name string, data []byte) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := durable.Options{Sync: durable.SyncNever}
+	l, err := durable.Open(dir, opts)
+	if err != nil {
+		return
+	}
+	values := l.IntoSlice()
+	if len(values) != l.Len() {
+		t.Fatalf("Len %d disagrees with %d values", l.Len(), len(values))
+	}
+	if err := l.Append("next"); err != nil {
+		t.Fatalf("Append after recovery failed: %v", err)
+	}
+	l.Close()
+
+	reopened, err := durable.Open(dir, opts)
+	if err != nil {
+		t.Fatalf("Reopen after recovery failed: %v", err)
+	}
+	defer reopened.Close()
+	if got := reopened.IntoSlice(); !reflect.DeepEqual(got, append(values, "next")) {
+		t.Fatalf("Expected %v after reopen, got %v", append(values, "next"), got)
+	}
+}
+
+func FuzzDurableLog(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzDurable(t, "wal.log", data)
+	})
+}
+
+func FuzzDurableSnapshot(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzDurable(t, "snapshot", data)
+	})
+}
+
+func FuzzFileList(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		path := filepath.Join(t.TempDir(), "list")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		l, err := filelist.Open(path, filelist.Options{CacheSize: 4})
+		if err != nil {
+			return
+		}
+		defer l.Close()
+
+		// Damaged files only have to fail cleanly
+		valid := l.Validate() == nil
+		values, err := l.IntoSlice()
+		if !valid {
+			return
+		}
+		if err != nil {
+			// Records can be structurally sound but hold bytes the codec rejects
+			return
+		}
+		if len(values) != l.Len() {
+			t.Fatalf("Len %d disagrees with %d values in a valid file", l.Len(), len(values))
+		}
+		if err := l.Append(1); err != nil {
+			t.Fatalf("Append to a valid file failed: %v", err)
+		}
+		if err := l.Validate(); err != nil {
+			t.Fatalf("Append broke a valid file: %v", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/indexed"
+)
+
+// TestIndexedPositions checks a long run of positional operations at random
+// indices against a slice.
+func TestIndexedPositions(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 0))
+	list := indexed.NewIndexedList()
+	var want []any
+
+	for step := 0; step < 20000; step++ {
+		switch op := rng.IntN(5); {
+		case op < 2 || len(want) == 0:
+			index := rng.IntN(len(want) + 1)
+			if err := list.Insert(step, index); err != nil {
+				t.Fatalf("Step %d: Insert(%d, %d): %v", step, step, index, err)
+			}
+			want = slices.Insert(want, index, any(step))
+		case op == 2:
+			index := rng.IntN(len(want))
+			if err := list.DeleteAt(index); err != nil {
+				t.Fatalf("Step %d: DeleteAt(%d): %v", step, index, err)
+			}
+			want = slices.Delete(want, index, index+1)
+		case op == 3:
+			from, to := rng.IntN(len(want)), rng.IntN(len(want))
+			if err := list.Move(from, to); err != nil {
+				t.Fatalf("Step %d: Move(%d, %d): %v", step, from, to, err)
+			}
+			v := want[from]
+			want = slices.Insert(slices.Delete(want, from, from+1), to, v)
+		default:
+			index := rng.IntN(len(want))
+			if got, err := list.Get(index); err != nil || got != want[index] {
+				t.Fatalf("Step %d: Get(%d) = %v, %v; want %v", step, index, got, err, want[index])
+			}
+		}
+		if list.Len() != len(want) {
+			t.Fatalf("Step %d: expected length %d, got %d", step, len(want), list.Len())
+		}
+	}
+
+	if err := list.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := list.IntoSlice(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestIndexedSet(t *testing.T) {
+	list := indexed.NewIndexedList()
+	list.FromSlice([]any{0, 1, 2, 3})
+	var got []event.Event
+	list.Subscribe(func(e event.Event) { got = append(got, e) })
+
+	if err := list.Set(2, "two"); err != nil {
+		t.Fatal(err)
+	}
+	if values := list.IntoSlice(); !reflect.DeepEqual(values, []any{0, 1, "two", 3}) {
+		t.Errorf("Expected [0 1 two 3], got %v", values)
+	}
+	if len(got) != 1 || got[0] != (event.Updated{Index: 2, Old: 2, Value: "two"}) {
+		t.Errorf("Expected a single Updated event, got %#v", got)
+	}
+	for _, index := range []int{-1, 4} {
+		if err := list.Set(index, 0); !errors.Is(err, indexed.ErrIndexOutOfBounds) {
+			t.Errorf("Expected ErrIndexOutOfBounds for index %d, got %v", index, err)
+		}
+	}
+}
+
+func TestIndexedBackward(t *testing.T) {
+	list := indexed.NewIndexedList()
+	list.FromSlice([]any{"a", "b", "c", "d"})
+
+	var indices []int
+	var values []any
+	for i, v := range list.Backward() {
+		indices = append(indices, i)
+		values = append(values, v)
+		if v == "b" {
+			break
+		}
+	}
+	if !reflect.DeepEqual(indices, []int{3, 2, 1}) || !reflect.DeepEqual(values, []any{"d", "c", "b"}) {
+		t.Errorf("Expected indices [3 2 1] and values [d c b], got %v and %v", indices, values)
+	}
+}
+
+func TestIndexedEvents(t *testing.T) {
+	list := indexed.NewIndexedList()
+	var got []event.Event
+	list.Subscribe(func(e event.Event) { got = append(got, e) })
+
+	list.FromSlice([]any{1, 2})
+	list.Merge(list)
+	list.Unique()
+	list.Move(0, 1)
+	list.Reverse()
+
+	want := []event.Event{
+		event.Batch{Events: []event.Event{
+			event.Inserted{Index: 0, Value: 1},
+			event.Inserted{Index: 1, Value: 2},
+		}},
+		event.Batch{Events: []event.Event{
+			event.Inserted{Index: 2, Value: 1},
+			event.Inserted{Index: 3, Value: 2},
+		}},
+		event.Batch{Events: []event.Event{
+			event.Removed{Index: 2, Value: 1},
+			event.Removed{Index: 2, Value: 2},
+		}},
+		event.Moved{From: 0, To: 1, Value: 1},
+		event.Reordered{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %#v, got %#v", want, got)
+	}
+}
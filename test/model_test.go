@@ -0,0 +1,61 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/JustMrNone/ll/listtest"
+)
+
+func TestSinglyModel(t *testing.T) {
+	listtest.RunModel(t, listtest.NewSingly, listtest.SinglyErrors, &quick.Config{MaxCount: 500})
+}
+
+func TestDoublyModel(t *testing.T) {
+	listtest.RunModel(t, listtest.NewDoubly, listtest.DoublyErrors, &quick.Config{MaxCount: 500})
+}
+
+func TestIndexedModel(t *testing.T) {
+	listtest.RunModel(t, listtest.NewIndexed, listtest.IndexedErrors, &quick.Config{MaxCount: 500})
+}
+
+// appendingInsert reintroduces an old singly bug: Insert at index 0 appends.
+type appendingInsert struct {
+	listtest.Doubly
+}
+
+func (l appendingInsert) Insert(value any, index int) error {
+	if index == 0 {
+		return l.Append(value)
+	}
+	return l.Doubly.Insert(value, index)
+}
+
+func (l appendingInsert) Merge(other listtest.List) error {
+	return l.Doubly.Merge(other.(appendingInsert).Doubly)
+}
+
+func TestModelShrink(t *testing.T) {
+	newList := func() listtest.List { return appendingInsert{listtest.NewDoubly().(listtest.Doubly)} }
+	ops := listtest.Ops{
+		{Kind: listtest.OpAppend, Value: 3},
+		{Kind: listtest.OpPop},
+		{Kind: listtest.OpAppend, Value: 4},
+		{Kind: listtest.OpReverse},
+		{Kind: listtest.OpInsert, Value: "b", Index: 0},
+		{Kind: listtest.OpSort},
+	}
+	if listtest.Replay(newList, listtest.DoublyErrors, ops) == nil {
+		t.Fatal("Expected the buggy Insert to diverge from the model")
+	}
+
+	shrunk := listtest.Shrink(newList, listtest.DoublyErrors, ops)
+	want := "list := newList()\nlist.Append(0)\nlist.Insert(\"b\", 0)\n"
+	if got := shrunk.GoString(); got != want {
+		t.Errorf("Expected reproducer:\n%s\ngot:\n%s", want, got)
+	}
+	if err := listtest.Replay(newList, listtest.DoublyErrors, shrunk); err == nil || !strings.Contains(err.Error(), "step 1") {
+		t.Errorf("Expected the reproducer to fail at step 1, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JustMrNone/ll/event"
+)
+
+func TestDoublyMoveToBack(t *testing.T) {
+	list := newDoubly(1, 2, 3)
+	var events []event.Event
+	list.Subscribe(func(e event.Event) { events = append(events, e) })
+
+	list.MoveToBack(list.Head)
+	list.MoveToBack(list.Tail) // Already at the back
+	if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{2, 3, 1}) {
+		t.Errorf("Expected [2 3 1], got %v", got)
+	}
+	if err := list.Validate(); err != nil {
+		t.Errorf("List validation failed: %v", err)
+	}
+	if want := []event.Event{event.Moved{From: 0, To: 2, Value: 1}}; !reflect.DeepEqual(events, want) {
+		t.Errorf("Expected events %v, got %v", want, events)
+	}
+
+	quiet := newDoubly(1, 2, 3)
+	if allocs := testing.AllocsPerRun(100, func() { quiet.MoveToBack(quiet.Head) }); allocs != 0 {
+		t.Errorf("Expected MoveToBack not to allocate, got %v allocations", allocs)
+	}
+}
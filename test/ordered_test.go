@@ -0,0 +1,171 @@
+package test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JustMrNone/ll/ordered"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m ordered.Map[string, int] // The zero value is ready to use
+	for i, key := range []string{"c", "a", "b", "d"} {
+		m.Set(key, i)
+	}
+	m.Set("a", 10) // Keeps its position
+	if !m.Delete("b") || m.Delete("b") {
+		t.Errorf("Expected Delete to report presence once")
+	}
+	if got := m.Keys(); !slices.Equal(got, []string{"c", "a", "d"}) {
+		t.Errorf("Expected keys [c a d], got %v", got)
+	}
+	if got := m.Values(); !slices.Equal(got, []int{0, 10, 3}) {
+		t.Errorf("Expected values [0 10 3], got %v", got)
+	}
+	if v, ok := m.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %d, %v; want 10, true", v, ok)
+	}
+	if _, ok := m.Get("b"); ok || m.Has("b") {
+		t.Errorf("Deleted key is still present")
+	}
+
+	m.MoveToEnd("c")
+	var backward []string
+	for key := range m.Backward() {
+		backward = append(backward, key)
+	}
+	if !slices.Equal(backward, []string{"c", "d", "a"}) {
+		t.Errorf("Expected backward keys [c d a], got %v", backward)
+	}
+	if key, _, _ := m.First(); key != "a" {
+		t.Errorf("Expected first key a, got %q", key)
+	}
+	if key, _, _ := m.Last(); key != "c" {
+		t.Errorf("Expected last key c, got %q", key)
+	}
+
+	// Deleting the current key during iteration is allowed
+	for key := range m.All() {
+		m.Delete(key)
+	}
+	if m.Len() != 0 {
+		t.Errorf("Expected an empty map, got %d entries", m.Len())
+	}
+	if _, _, ok := m.First(); ok {
+		t.Errorf("Expected no first entry in an empty map")
+	}
+}
+
+func TestOrderedMapAccessOrder(t *testing.T) {
+	// An LRU cache of capacity 2
+	cache := ordered.NewAccessOrderMap[string, int]()
+	put := func(key string, value int) {
+		cache.Set(key, value)
+		if cache.Len() > 2 {
+			oldest, _, _ := cache.First()
+			cache.Delete(oldest)
+		}
+	}
+
+	put("a", 1)
+	put("b", 2)
+	cache.Get("a")
+	put("c", 3) // Evicts b, the least recently used
+	if got := cache.Keys(); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("Expected keys [a c], got %v", got)
+	}
+	if cache.Has("b") {
+		t.Errorf("Expected b to be evicted")
+	}
+	cache.Has("a") // Not an access
+	put("d", 4)
+	if got := cache.Keys(); !slices.Equal(got, []string{"c", "d"}) {
+		t.Errorf("Expected keys [c d], got %v", got)
+	}
+
+	// Moving a key to the end relinks its node in place
+	if allocs := testing.AllocsPerRun(100, func() { cache.Get("c") }); allocs != 0 {
+		t.Errorf("Expected Get to move the key without allocating, got %v allocations", allocs)
+	}
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	m := ordered.NewMap[string, any]()
+	m.Set("zebra", 1.0)
+	m.Set("apple", []any{"x"})
+	m.Set("mango", nil)
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `{"zebra":1,"apple":["x"],"mango":null}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	decoded := ordered.NewMap[string, any]()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Keys(), m.Keys()) || !reflect.DeepEqual(decoded.Values(), m.Values()) {
+		t.Errorf("Round trip changed the map: %v %v", decoded.Keys(), decoded.Values())
+	}
+
+	// Integer and TextMarshaler keys are encoded like encoding/json does
+	ints := ordered.NewMap[int, string]()
+	ints.Set(10, "ten")
+	ints.Set(-2, "minus two")
+	data, _ = json.Marshal(ints)
+	if want := `{"10":"ten","-2":"minus two"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+	var back ordered.Map[int, string]
+	if err := json.Unmarshal(data, &back); err != nil || !slices.Equal(back.Keys(), []int{10, -2}) {
+		t.Errorf("Unmarshal int keys = %v, %v", back.Keys(), err)
+	}
+	var addrs ordered.Map[netip.Addr, bool]
+	if err := json.Unmarshal([]byte(`{"10.0.0.2":true,"10.0.0.1":false}`), &addrs); err != nil {
+		t.Fatalf("Unmarshal address keys failed: %v", err)
+	}
+	if got := addrs.Keys(); len(got) != 2 || got[0] != netip.MustParseAddr("10.0.0.2") {
+		t.Errorf("Unexpected address keys %v", got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"x":1}`), &back); err == nil {
+		t.Errorf("Expected an error for a non-integer key")
+	}
+	if err := json.Unmarshal([]byte(`[1]`), &back); err == nil {
+		t.Errorf("Expected an error for a JSON array")
+	}
+}
+
+func TestOrderedSet(t *testing.T) {
+	s := ordered.NewSet(3, 1, 3, 2, 1)
+	if got := s.Values(); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Expected [3 1 2], got %v", got)
+	}
+	if s.Add(1) || !s.Add(4) {
+		t.Errorf("Expected Add to report only new values")
+	}
+	if !s.Remove(3) || s.Contains(3) {
+		t.Errorf("Expected 3 to be removed")
+	}
+	s.MoveToEnd(1)
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int{2, 4, 1}) {
+		t.Errorf("Expected [2 4 1], got %v", got)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != "[2,4,1]" {
+		t.Errorf("Marshal = %s, %v; want [2,4,1]", data, err)
+	}
+	var decoded ordered.Set[int]
+	if err := json.Unmarshal([]byte("[5,6,5,7]"), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got := decoded.Values(); !slices.Equal(got, []int{5, 6, 7}) {
+		t.Errorf("Expected [5 6 7], got %v", got)
+	}
+}
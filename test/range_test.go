@@ -0,0 +1,359 @@
+package test
+
+import (
+	"errors"
+	"iter"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/indexed"
+	"github.com/JustMrNone/ll/iters"
+	"github.com/JustMrNone/ll/ordered"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// sublist is what the singly, doubly and indexed sublists have in common.
+type sublist interface {
+	Len() int
+	Get(index int) (any, error)
+	Set(index int, value any) error
+	Insert(value any, index int) error
+	DeleteAt(index int) error
+	All() iter.Seq2[int, any]
+	IntoSlice() []any
+}
+
+// rangeList is what the lists have in common for ranges.
+type rangeList interface {
+	Append(value any) error
+	Prepend(value any) error
+	IntoSlice() []any
+	Range(from, to int) (sublist, error)
+	Page(cursor string, limit int) ([]any, string, error)
+}
+
+type doublyRange struct{ *doubly.LinkedList }
+
+func (l doublyRange) Range(from, to int) (sublist, error) {
+	s, err := l.LinkedList.Range(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// singlyRange papers over the singly list's Append and Prepend, which
+// return no error.
+type singlyRange struct{ *singly.LinkedList }
+
+func (l singlyRange) Append(value any) error {
+	l.LinkedList.Append(value)
+	return nil
+}
+
+func (l singlyRange) Prepend(value any) error {
+	l.LinkedList.Prepend(value)
+	return nil
+}
+
+func (l singlyRange) Range(from, to int) (sublist, error) {
+	s, err := l.LinkedList.Range(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type indexedRange struct{ *indexed.List }
+
+func (l indexedRange) Range(from, to int) (sublist, error) {
+	s, err := l.List.Range(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var rangeLists = []struct {
+	name         string
+	new          func(values ...any) rangeList
+	outOfBounds  error
+	badCursorErr error
+}{
+	{"Singly", func(values ...any) rangeList { return singlyRange{newSingly(values...)} }, singly.ErrIndexOutOfBounds, singly.ErrInvalidCursor},
+	{"Doubly", func(values ...any) rangeList { return doublyRange{newDoubly(values...)} }, doubly.ErrIndexOutOfBounds, doubly.ErrInvalidCursor},
+	{"Indexed", func(values ...any) rangeList { return indexedRange{newIndexed(values...)} }, indexed.ErrIndexOutOfBounds, indexed.ErrInvalidCursor},
+}
+
+// TestRangeEdits makes random edits through a sublist and checks both the
+// sublist and the whole list against slices.
+func TestRangeEdits(t *testing.T) {
+	for _, tc := range rangeLists {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(3, 0))
+			want := []any{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+			list := tc.new(want...)
+			from, to := 3, 7
+			s, err := list.Range(from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for step := 0; step < 500; step++ {
+				n := to - from
+				switch op := rng.IntN(3); {
+				case op == 0 || n == 0:
+					index := rng.IntN(n + 1)
+					if err := s.Insert(100+step, index); err != nil {
+						t.Fatalf("Step %d: Insert: %v", step, err)
+					}
+					want = slices.Insert(want, from+index, any(100+step))
+					to++
+				case op == 1:
+					index := rng.IntN(n)
+					if err := s.DeleteAt(index); err != nil {
+						t.Fatalf("Step %d: DeleteAt: %v", step, err)
+					}
+					want = slices.Delete(want, from+index, from+index+1)
+					to--
+				default:
+					index := rng.IntN(n)
+					if err := s.Set(index, -step); err != nil {
+						t.Fatalf("Step %d: Set: %v", step, err)
+					}
+					want[from+index] = -step
+				}
+				if got := s.IntoSlice(); !reflect.DeepEqual(got, want[from:to]) {
+					t.Fatalf("Step %d: expected sublist %v, got %v", step, want[from:to], got)
+				}
+				if got := list.IntoSlice(); !reflect.DeepEqual(got, want) {
+					t.Fatalf("Step %d: expected list %v, got %v", step, want, got)
+				}
+				if s.Len() > 0 {
+					index := rng.IntN(s.Len())
+					if got, err := s.Get(index); err != nil || got != want[from+index] {
+						t.Fatalf("Step %d: Get(%d) = %v, %v; want %v", step, index, got, err, want[from+index])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRangeLive(t *testing.T) {
+	for _, tc := range rangeLists {
+		t.Run(tc.name, func(t *testing.T) {
+			list := tc.new(0, 1, 2, 3, 4)
+			s, _ := list.Range(1, 4)
+
+			// Changes to the list show through, by position
+			list.Prepend("x")
+			if got := s.IntoSlice(); !reflect.DeepEqual(got, []any{0, 1, 2}) {
+				t.Errorf("Expected [0 1 2], got %v", got)
+			}
+			for i, v := range s.All() {
+				if i == 1 {
+					if v != 1 {
+						t.Errorf("Expected 1 at index 1, got %v", v)
+					}
+					break
+				}
+			}
+
+			if _, err := s.Get(3); !errors.Is(err, tc.outOfBounds) {
+				t.Errorf("Expected ErrIndexOutOfBounds, got %v", err)
+			}
+			if err := s.Insert(9, 4); !errors.Is(err, tc.outOfBounds) {
+				t.Errorf("Expected ErrIndexOutOfBounds, got %v", err)
+			}
+			for _, r := range [][2]int{{-1, 2}, {3, 2}, {0, 7}} {
+				if _, err := list.Range(r[0], r[1]); !errors.Is(err, tc.outOfBounds) {
+					t.Errorf("Range(%d, %d): expected ErrIndexOutOfBounds, got %v", r[0], r[1], err)
+				}
+			}
+			if empty, err := list.Range(6, 6); err != nil || empty.Len() != 0 || len(empty.IntoSlice()) != 0 {
+				t.Errorf("Range(6, 6) = %v, %v; want an empty sublist", empty, err)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	for _, tc := range rangeLists {
+		t.Run(tc.name, func(t *testing.T) {
+			list := tc.new()
+			for i := 0; i < 7; i++ {
+				list.Append(i)
+			}
+
+			var got [][]any
+			cursor := ""
+			for {
+				page, next, err := list.Page(cursor, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, page)
+				cursor = next
+				if len(page) < 3 {
+					break
+				}
+			}
+			if want := [][]any{{0, 1, 2}, {3, 4, 5}, {6}}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected pages %v, got %v", want, got)
+			}
+
+			// The last cursor picks up values appended after it was issued
+			list.Append(7)
+			list.Append(8)
+			page, _, err := list.Page(cursor, 3)
+			if err != nil || !reflect.DeepEqual(page, []any{7, 8}) {
+				t.Errorf("Page after appends = %v, %v; want [7 8]", page, err)
+			}
+
+			for _, bad := range []string{"not a cursor", "cDk5", "eDE"} {
+				if _, _, err := list.Page(bad, 3); !errors.Is(err, tc.badCursorErr) {
+					t.Errorf("Page(%q): expected ErrInvalidCursor, got %v", bad, err)
+				}
+			}
+			if _, _, err := list.Page("", 0); err == nil {
+				t.Errorf("Expected an error for a limit of 0")
+			}
+		})
+	}
+}
+
+// TestPageSharedCursor checks that every list accepts the cursors the
+// others issue and reports bad ones with the same error.
+func TestPageSharedCursor(t *testing.T) {
+	_, next, _ := newDoubly(0, 1, 2, 3).Page("", 3)
+	if page, _, err := newIndexed(0, 1, 2, 3).Page(next, 3); err != nil || !reflect.DeepEqual(page, []any{3}) {
+		t.Errorf("Page with a doubly cursor = %v, %v; want [3]", page, err)
+	}
+	if page, _, err := newSingly(0, 1, 2, 3).Page(next, 3); err != nil || !reflect.DeepEqual(page, []any{3}) {
+		t.Errorf("Page with a doubly cursor = %v, %v; want [3]", page, err)
+	}
+	if doubly.ErrInvalidCursor != singly.ErrInvalidCursor || doubly.ErrInvalidCursor != indexed.ErrInvalidCursor {
+		t.Errorf("Expected every list to share ErrInvalidCursor")
+	}
+}
+
+func TestIters(t *testing.T) {
+	list := newDoubly(0, 1, 2, 3, 4, 5, 6)
+
+	var chunks [][]any
+	for chunk := range iters.Chunk(list.All(), 3) {
+		chunks = append(chunks, chunk)
+	}
+	if want := [][]any{{0, 1, 2}, {3, 4, 5}, {6}}; !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Expected chunks %v, got %v", want, chunks)
+	}
+
+	windows := slices.Collect(iters.Window(list.All(), 5))
+	if want := [][]any{{0, 1, 2, 3, 4}, {1, 2, 3, 4, 5}, {2, 3, 4, 5, 6}}; !reflect.DeepEqual(windows, want) {
+		t.Errorf("Expected windows %v, got %v", want, windows)
+	}
+	if got := slices.Collect(iters.Window(list.All(), 8)); len(got) != 0 {
+		t.Errorf("Expected no windows longer than the list, got %v", got)
+	}
+
+	// Skip keeps the list's indices
+	var indices, values []any
+	for i, v := range iters.Take(iters.Skip(list.All(), 2), 3) {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(indices, []any{2, 3, 4}) || !reflect.DeepEqual(values, []any{2, 3, 4}) {
+		t.Errorf("Expected indices and values [2 3 4], got %v and %v", indices, values)
+	}
+	if got := slices.Collect(iters.Chunk(iters.Take(list.All(), 0), 2)); len(got) != 0 {
+		t.Errorf("Expected Take(0) to yield nothing, got %v", got)
+	}
+
+	// Take stops the underlying iterator as soon as it has enough
+	pulled := 0
+	counting := func(yield func(int, any) bool) {
+		for i := 0; ; i++ {
+			pulled++
+			if !yield(i, i) {
+				return
+			}
+		}
+	}
+	for range iters.Take(counting, 4) {
+	}
+	if pulled != 4 {
+		t.Errorf("Expected Take to pull 4 values, pulled %d", pulled)
+	}
+
+	// The helpers work with any key type
+	m := ordered.NewMap[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	if got := slices.Collect(iters.Chunk(iters.Skip(m.All(), 1), 5)); !reflect.DeepEqual(got, [][]int{{2, 3}}) {
+		t.Errorf("Expected [[2 3]], got %v", got)
+	}
+
+	defer func() {
+		if r := recover(); r != "iters.Chunk: n = 0, must be at least 1" {
+			t.Errorf("Expected Chunk to panic for a size of 0, got %v", r)
+		}
+	}()
+	iters.Chunk(list.All(), 0)
+}
+
+// TestIndexedRangeIteration checks that iterating a sublist of a large tree
+// starts at the right node for every starting index.
+func TestIndexedRangeIteration(t *testing.T) {
+	want := make([]any, 200)
+	for i := range want {
+		want[i] = i
+	}
+	list := newIndexed(want...)
+	for from := 0; from <= len(want); from++ {
+		to := from + (from*7)%(len(want)-from+1)
+		s, err := list.Range(from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.IntoSlice(); !reflect.DeepEqual(got, want[from:to]) {
+			t.Fatalf("Range(%d, %d): expected %v, got %v", from, to, want[from:to], got)
+		}
+	}
+}
+
+func newSingly(values ...any) *singly.LinkedList {
+	list := singly.NewSinglyLinkedList()
+	list.FromSlice(values)
+	return list
+}
+
+func TestSinglySet(t *testing.T) {
+	list := newSingly(0, 1, 2, 3)
+	var got []event.Event
+	list.Subscribe(func(e event.Event) { got = append(got, e) })
+
+	sub, _ := list.Range(1, 3)
+	if err := sub.Set(1, "two"); err != nil {
+		t.Fatal(err)
+	}
+	if values := list.IntoSlice(); !reflect.DeepEqual(values, []any{0, 1, "two", 3}) {
+		t.Errorf("Expected [0 1 two 3], got %v", values)
+	}
+	if len(got) != 1 || got[0] != (event.Updated{Index: 2, Old: 2, Value: "two"}) {
+		t.Errorf("Expected a single Updated event, got %#v", got)
+	}
+	for _, index := range []int{-1, 4} {
+		if err := list.Set(index, 0); !errors.Is(err, singly.ErrIndexOutOfBounds) {
+			t.Errorf("Expected ErrIndexOutOfBounds for index %d, got %v", index, err)
+		}
+	}
+	if err := sub.Set(2, 0); !errors.Is(err, singly.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds past the sublist, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// corruptDoubly builds a list of up to 15 nodes from the first byte of data
+// and then applies one pointer corruption per following byte triple: a field
+// selector, the node to change and the node (or nil) to point it at.
+func corruptDoubly(data []byte) *doubly.LinkedList {
+	list := doubly.NewDoublyLinkedList()
+	if len(data) == 0 {
+		return list
+	}
+	n := int(data[0] % 16)
+	nodes := make([]*doubly.Node, 0, n)
+	for i := 0; i < n; i++ {
+		list.Append(i)
+		nodes = append(nodes, list.Tail)
+	}
+
+	// target maps a byte to a node, with one extra value meaning nil
+	target := func(b byte) *doubly.Node {
+		if i := int(b) % (n + 1); i < n {
+			return nodes[i]
+		}
+		return nil
+	}
+	for data = data[1:]; len(data) >= 3 && n > 0; data = data[3:] {
+		node, to := nodes[int(data[1])%n], target(data[2])
+		switch data[0] % 5 {
+		case 0:
+			node.Next = to
+		case 1:
+			node.Prev = to
+		case 2:
+			list.Head = to
+		case 3:
+			list.Tail = to
+		case 4:
+			list.Size = int(data[2]) - 128
+		}
+	}
+	return list
+}
+
+func FuzzRepairDoubly(f *testing.F) {
+	f.Add([]byte{5})
+	f.Add([]byte{5, 0, 4, 1})          // cycle back to index 1
+	f.Add([]byte{5, 1, 0, 3})          // head.Prev set
+	f.Add([]byte{5, 2, 0, 5, 4, 0, 0}) // nil head, wrong size
+	f.Add([]byte{8, 3, 0, 2, 1, 6, 1})
+	f.Add([]byte{3, 2, 0, 3, 1, 0, 2})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		list := corruptDoubly(data)
+		list.Repair()
+		if err := list.Validate(); err != nil {
+			t.Fatalf("Validate failed after Repair: %v", err)
+		}
+		if report := list.Repair(); report.Changed() {
+			t.Fatalf("Expected a repaired list to need no fixes, got:\n%s", report)
+		}
+	})
+}
+
+func FuzzRepairSingly(f *testing.F) {
+	f.Add(uint8(5), uint8(4), uint8(1), int8(3))
+	f.Add(uint8(1), uint8(0), uint8(0), int8(-1))
+	f.Add(uint8(0), uint8(0), uint8(0), int8(7))
+	f.Fuzz(func(t *testing.T, n, from, to uint8, size int8) {
+		n %= 32
+		list := singly.NewSinglyLinkedList()
+		var nodes []*singly.Node
+		for i := 0; i < int(n); i++ {
+			list.Append(i)
+		}
+		for node := list.Head; node != nil; node = node.Next {
+			nodes = append(nodes, node)
+		}
+		if n > 0 {
+			nodes[int(from)%len(nodes)].Next = nodes[int(to)%len(nodes)]
+		}
+		list.Size = int(size)
+
+		list.Repair()
+		if err := list.Validate(); err != nil {
+			t.Fatalf("Validate failed after Repair: %v", err)
+		}
+		if report := list.Repair(); report.Changed() {
+			t.Fatalf("Expected a repaired list to need no fixes, got:\n%s", report)
+		}
+	})
+}
+
+func TestRepairReport(t *testing.T) {
+	list := newDoubly(0, 1, 2, 3, 4)
+	if report := list.Repair(); report.Changed() {
+		t.Fatalf("Expected no fixes on a healthy list, got:\n%s", report)
+	}
+
+	list.Tail.Next = list.Head.Next
+	list.Head.Next.Next.Prev = nil
+	list.Tail = list.Head
+	list.Size = 2
+
+	report := list.Repair()
+	want := []doubly.FixKind{doubly.FixCycle, doubly.FixPrev, doubly.FixTail, doubly.FixSize}
+	if len(report.Fixes) != len(want) {
+		t.Fatalf("Expected %d fixes, got:\n%s", len(want), report)
+	}
+	for i, fix := range report.Fixes {
+		if fix.Kind != want[i] {
+			t.Errorf("Fix %d: expected %s, got %s", i, want[i], fix)
+		}
+	}
+	if got := list.IntoSlice(); len(got) != 5 || got[4] != 4 {
+		t.Errorf("Expected all five values to survive, got %v", got)
+	}
+
+	// Without a head, the list is rebuilt from the prev chain
+	list.Head = nil
+	list.Tail.Prev.Next = nil
+	report = list.Repair()
+	if err := list.Validate(); err != nil || list.Size != 5 {
+		t.Errorf("Expected the prev chain to restore five nodes, got size %d (%v):\n%s", list.Size, err, report)
+	}
+}
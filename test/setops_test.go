@@ -0,0 +1,138 @@
+package test
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/setops"
+)
+
+type setFunc func(a, b *doubly.LinkedList, opts setops.Options) (*doubly.LinkedList, error)
+
+var setFuncs = []struct {
+	name string
+	fn   setFunc
+}{
+	{"Union", setops.Union},
+	{"Intersection", setops.Intersection},
+	{"Difference", setops.Difference},
+	{"SymmetricDifference", setops.SymmetricDifference},
+}
+
+func compareInts(a, b any) int {
+	return cmp.Compare(a.(int), b.(int))
+}
+
+func TestSetOpsHash(t *testing.T) {
+	a := newDoubly(3, 1, 3, 2, 3)
+	b := newDoubly(4, 3, 1, 4)
+	want := map[setops.Mode][][]any{
+		setops.Set: {
+			{3, 1, 2, 4},
+			{3, 1},
+			{2},
+			{2, 4},
+		},
+		setops.Multiset: {
+			{3, 1, 3, 2, 3, 4, 4},
+			{3, 1},
+			{3, 2, 3},
+			{3, 2, 3, 4, 4},
+		},
+	}
+
+	for mode, results := range want {
+		for i, f := range setFuncs {
+			got, err := f.fn(a, b, setops.Options{Mode: mode})
+			if err != nil {
+				t.Fatalf("%s (mode %d) failed: %v", f.name, mode, err)
+			}
+			if !reflect.DeepEqual(got.IntoSlice(), results[i]) {
+				t.Errorf("%s (mode %d): expected %v, got %v", f.name, mode, results[i], got.IntoSlice())
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("%s (mode %d) returned an invalid list: %v", f.name, mode, err)
+			}
+		}
+	}
+	if !reflect.DeepEqual(a.IntoSlice(), []any{3, 1, 3, 2, 3}) {
+		t.Errorf("Inputs were modified: %v", a.IntoSlice())
+	}
+}
+
+// TestSetOpsMerge checks that the merge path agrees with the hash path on
+// sorted inputs, once the hash path's result is sorted.
+func TestSetOpsMerge(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 0))
+	randomSorted := func() *doubly.LinkedList {
+		values := make([]any, rng.IntN(12))
+		for i := range values {
+			values[i] = rng.IntN(6)
+		}
+		slices.SortFunc(values, compareInts)
+		return newDoubly(values...)
+	}
+
+	for round := 0; round < 200; round++ {
+		a, b := randomSorted(), randomSorted()
+		for _, mode := range []setops.Mode{setops.Set, setops.Multiset} {
+			for _, f := range setFuncs {
+				merged, err := f.fn(a, b, setops.Options{Mode: mode, Compare: compareInts})
+				if err != nil {
+					t.Fatalf("%s failed: %v", f.name, err)
+				}
+				hashed, _ := f.fn(a, b, setops.Options{Mode: mode})
+				want := hashed.IntoSlice()
+				slices.SortFunc(want, compareInts)
+				if !reflect.DeepEqual(merged.IntoSlice(), want) {
+					t.Fatalf("%s (mode %d) of %v and %v: merge gave %v, hash gave %v",
+						f.name, mode, a.IntoSlice(), b.IntoSlice(), merged.IntoSlice(), want)
+				}
+			}
+		}
+	}
+
+	_, err := setops.Union(newDoubly(1, 3, 2), newDoubly(), setops.Options{Compare: compareInts})
+	if !errors.Is(err, setops.ErrUnsorted) {
+		t.Errorf("Expected ErrUnsorted, got %v", err)
+	}
+}
+
+func TestSetOpsKey(t *testing.T) {
+	type user struct {
+		ID   int
+		Tags []string // Makes user non-comparable
+	}
+	a := newDoubly(user{1, nil}, user{2, []string{"x"}})
+	b := newDoubly(user{2, []string{"y"}}, user{3, nil})
+
+	if _, err := setops.Union(a, b, setops.Options{}); !errors.Is(err, doubly.ErrNotComparable) {
+		t.Fatalf("Expected ErrNotComparable, got %v", err)
+	}
+
+	// A comparable type can still hold a value that isn't, such as an
+	// interface field holding a slice
+	type boxed struct{ V any }
+	boxes := newDoubly(boxed{1}, boxed{[]int{2}})
+	if _, err := setops.Union(boxes, newDoubly(), setops.Options{}); !errors.Is(err, doubly.ErrNotComparable) {
+		t.Fatalf("Expected ErrNotComparable for a boxed slice, got %v", err)
+	}
+
+	byID := setops.Options{Key: func(v any) any { return v.(user).ID }}
+	got, err := setops.Intersection(a, b, byID)
+	if err != nil {
+		t.Fatalf("Intersection failed: %v", err)
+	}
+	if want := []any{user{2, []string{"x"}}}; !reflect.DeepEqual(got.IntoSlice(), want) {
+		t.Errorf("Expected %v, got %v", want, got.IntoSlice())
+	}
+
+	if _, err := setops.Union(a, nil, byID); err == nil {
+		t.Errorf("Expected an error for a nil list")
+	}
+}
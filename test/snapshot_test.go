@@ -0,0 +1,220 @@
+package test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/JustMrNone/ll/indexed"
+)
+
+// TestSnapshotIsolation takes snapshots in the middle of random mutations and
+// checks that every one of them still holds what the list held at the time.
+func TestSnapshotIsolation(t *testing.T) {
+	rng := rand.New(rand.NewPCG(9, 0))
+	list := indexed.NewIndexedList()
+	var want []any
+	type snapshot struct {
+		view *indexed.View
+		want []any
+	}
+	var snapshots []snapshot
+
+	for step := 0; step < 5000; step++ {
+		switch op := rng.IntN(10); {
+		case op < 3 || len(want) == 0:
+			index := rng.IntN(len(want) + 1)
+			list.Insert(step%50, index)
+			want = slices.Insert(want, index, any(step%50))
+		case op == 3:
+			index := rng.IntN(len(want))
+			list.DeleteAt(index)
+			want = slices.Delete(want, index, index+1)
+		case op == 4:
+			index := rng.IntN(len(want))
+			list.Set(index, -step)
+			want[index] = -step
+		case op == 5:
+			from, to := rng.IntN(len(want)), rng.IntN(len(want))
+			list.Move(from, to)
+			v := want[from]
+			want = slices.Insert(slices.Delete(want, from, from+1), to, v)
+		case op == 6:
+			list.Reverse()
+			slices.Reverse(want)
+		case op == 7:
+			list.Merge(list)
+			want = append(want, want...)
+		case op == 8 && step%20 == 0:
+			list.Sort()
+			slices.SortStableFunc(want, compareInts)
+		case op == 8:
+			list.Unique()
+			seen := make(map[any]bool)
+			want = slices.DeleteFunc(want, func(v any) bool {
+				dup := seen[v]
+				seen[v] = true
+				return dup
+			})
+		default:
+			snapshots = append(snapshots, snapshot{list.Snapshot(), slices.Clone(want)})
+		}
+		if got := list.IntoSlice(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Step %d: expected %v, got %v", step, want, got)
+		}
+	}
+	if err := list.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, s := range snapshots {
+		if got := s.view.IntoSlice(); !reflect.DeepEqual(got, s.want) {
+			t.Fatalf("Snapshot %d changed: expected %v, got %v", i, s.want, got)
+		}
+		if s.view.Len() != len(s.want) {
+			t.Fatalf("Snapshot %d: expected length %d, got %d", i, len(s.want), s.view.Len())
+		}
+		if len(s.want) > 0 {
+			index := len(s.want) / 2
+			if got, err := s.view.Get(index); err != nil || got != s.want[index] {
+				t.Fatalf("Snapshot %d: Get(%d) = %v, %v; want %v", i, index, got, err, s.want[index])
+			}
+			if !s.view.Contains(s.want[0]) {
+				t.Fatalf("Snapshot %d: expected it to contain %v", i, s.want[0])
+			}
+		}
+	}
+}
+
+func TestSnapshotView(t *testing.T) {
+	list := newIndexed(1, 2, 3)
+	view := list.Snapshot()
+	list.Set(0, 10)
+	list.Append(4)
+
+	var backward []any
+	for i, v := range view.Backward() {
+		if want := i + 1; v != want {
+			t.Errorf("Expected %d at index %d, got %v", want, i, v)
+		}
+		backward = append(backward, v)
+	}
+	if !reflect.DeepEqual(backward, []any{3, 2, 1}) {
+		t.Errorf("Expected [3 2 1], got %v", backward)
+	}
+	if view.Contains(10) || view.Contains([]int{1}) {
+		t.Errorf("Expected the view not to contain 10 or a slice")
+	}
+	if _, err := view.Get(3); !errors.Is(err, indexed.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds, got %v", err)
+	}
+	if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{10, 2, 3, 4}) {
+		t.Errorf("Expected [10 2 3 4], got %v", got)
+	}
+	if empty := indexed.NewIndexedList().Snapshot(); empty.Len() != 0 || len(empty.IntoSlice()) != 0 {
+		t.Errorf("Expected an empty view")
+	}
+}
+
+// TestSnapshotConcurrentReads reads views from other goroutines while the
+// list keeps changing. Run it with -race.
+func TestSnapshotConcurrentReads(t *testing.T) {
+	list := indexed.NewIndexedList()
+	for i := 0; i < 1000; i++ {
+		list.Append(i)
+	}
+
+	var wg sync.WaitGroup
+	for round := 0; round < 20; round++ {
+		view := list.Snapshot()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sum := 0
+			for _, v := range view.All() {
+				sum += v.(int)
+			}
+			if sum != 1000*999/2 {
+				t.Errorf("Expected the view to sum to %d, got %d", 1000*999/2, sum)
+			}
+		}()
+		for i := 0; i < 50; i++ {
+			list.Move(i, 999-i)
+			list.Set(i, mustGet(t, list, i)) // Copies the path to i
+		}
+		list.Reverse()
+	}
+	wg.Wait()
+}
+
+func TestFreeze(t *testing.T) {
+	list := newIndexed(3, 1, 2)
+	list.Freeze()
+	if !list.Frozen() {
+		t.Fatal("Expected the list to be frozen")
+	}
+
+	mutations := map[string]func() error{
+		"Append":     func() error { return list.Append(4) },
+		"Prepend":    func() error { return list.Prepend(4) },
+		"Insert":     func() error { return list.Insert(4, 1) },
+		"Set":        func() error { return list.Set(0, 4) },
+		"DeleteAt":   func() error { return list.DeleteAt(0) },
+		"Delete":     func() error { return list.Delete(1) },
+		"DeleteFunc": func() error { return list.DeleteFunc(func(any) bool { return true }) },
+		"Shift":      func() error { return list.Shift() },
+		"Pop":        func() error { return list.Pop() },
+		"Move":       func() error { return list.Move(0, 2) },
+		"FromSlice":  func() error { return list.FromSlice([]any{4}) },
+		"FromArray":  func() error { return list.FromArray([]any{4}) },
+		"Merge":      func() error { return list.Merge(newIndexed(4)) },
+		"Reverse":    func() error { return list.Reverse() },
+		"Unique":     func() error { return list.Unique() },
+		"Sort":       func() error { return list.Sort() },
+	}
+	for name, fn := range mutations {
+		if err := fn(); !errors.Is(err, indexed.ErrFrozen) {
+			t.Errorf("%s: expected ErrFrozen, got %v", name, err)
+		}
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != indexed.ErrFrozen {
+				t.Errorf("Expected Clear to panic with ErrFrozen, got %v", r)
+			}
+		}()
+		list.Clear()
+	}()
+	if got := list.IntoSlice(); !reflect.DeepEqual(got, []any{3, 1, 2}) {
+		t.Errorf("Expected the frozen list to be unchanged, got %v", got)
+	}
+
+	// Reads and snapshots still work, and clones are not frozen
+	if v, err := list.Get(1); err != nil || v != 1 {
+		t.Errorf("Get(1) = %v, %v; want 1", v, err)
+	}
+	if view := list.Snapshot(); !reflect.DeepEqual(view.IntoSlice(), []any{3, 1, 2}) {
+		t.Errorf("Unexpected snapshot %v", view.IntoSlice())
+	}
+	if clone := list.Clone(); clone.Frozen() || clone.Append(4) != nil {
+		t.Errorf("Expected the clone to be mutable")
+	}
+}
+
+func newIndexed(values ...any) *indexed.List {
+	list := indexed.NewIndexedList()
+	list.FromSlice(values)
+	return list
+}
+
+func mustGet(t *testing.T, list *indexed.List, index int) any {
+	t.Helper()
+	v, err := list.Get(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
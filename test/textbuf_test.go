@@ -0,0 +1,152 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/JustMrNone/ll/textbuf"
+)
+
+// TestTextBufEdits checks random inserts and deletes against a rune slice,
+// with chunks small enough that most edits cross chunk boundaries.
+func TestTextBufEdits(t *testing.T) {
+	rng := rand.New(rand.NewPCG(11, 0))
+	buf := textbuf.NewBufferSize("", 4)
+	var want []rune
+	pieces := []string{"a", "bc", "héllo", "\n", "x\ny\n", "日本語", "0123456789"}
+
+	for step := 0; step < 5000; step++ {
+		if rng.IntN(3) > 0 || len(want) == 0 {
+			pos, s := rng.IntN(len(want)+1), pieces[rng.IntN(len(pieces))]
+			if err := buf.Insert(pos, s); err != nil {
+				t.Fatalf("Step %d: Insert(%d, %q): %v", step, pos, s, err)
+			}
+			want = append(want[:pos], append([]rune(s), want[pos:]...)...)
+		} else {
+			pos := rng.IntN(len(want))
+			n := rng.IntN(min(len(want)-pos, 12) + 1)
+			if err := buf.Delete(pos, n); err != nil {
+				t.Fatalf("Step %d: Delete(%d, %d): %v", step, pos, n, err)
+			}
+			want = append(want[:pos], want[pos+n:]...)
+		}
+		if buf.Len() != len(want) {
+			t.Fatalf("Step %d: expected length %d, got %d", step, len(want), buf.Len())
+		}
+		if step%50 == 0 {
+			if got := buf.String(); got != string(want) {
+				t.Fatalf("Step %d: expected %q, got %q", step, string(want), got)
+			}
+			from := rng.IntN(len(want) + 1)
+			to := from + rng.IntN(len(want)-from+1)
+			if got, err := buf.Slice(from, to); err != nil || got != string(want[from:to]) {
+				t.Fatalf("Step %d: Slice(%d, %d) = %q, %v; want %q", step, from, to, got, err, string(want[from:to]))
+			}
+		}
+	}
+	if got, want := buf.LineCount(), strings.Count(string(want), "\n")+1; got != want {
+		t.Errorf("Expected %d lines, got %d", want, got)
+	}
+}
+
+func TestTextBufLineColumn(t *testing.T) {
+	text := "first\nsecond line\n\nλast"
+	buf := textbuf.NewBufferSize(text, 3)
+
+	// Every offset must round-trip through its line and column
+	line, col := 0, 0
+	for offset, r := range []rune(text) {
+		gotLine, gotCol, err := buf.Position(offset)
+		if err != nil || gotLine != line || gotCol != col {
+			t.Fatalf("Position(%d) = %d, %d, %v; want %d, %d", offset, gotLine, gotCol, err, line, col)
+		}
+		if got, err := buf.Offset(line, col); err != nil || got != offset {
+			t.Fatalf("Offset(%d, %d) = %d, %v; want %d", line, col, got, err, offset)
+		}
+		if r == '\n' {
+			line, col = line+1, 0
+		} else {
+			col++
+		}
+	}
+	if got, err := buf.Offset(3, 4); err != nil || got != buf.Len() {
+		t.Errorf("Offset(3, 4) = %d, %v; want the end of the text", got, err)
+	}
+
+	for _, pos := range [][2]int{{0, 6}, {2, 1}, {3, 5}, {4, 0}, {-1, 0}} {
+		if _, err := buf.Offset(pos[0], pos[1]); !errors.Is(err, textbuf.ErrOutOfRange) {
+			t.Errorf("Offset(%d, %d): expected ErrOutOfRange, got %v", pos[0], pos[1], err)
+		}
+	}
+	if _, _, err := buf.Position(buf.Len() + 1); !errors.Is(err, textbuf.ErrOutOfRange) {
+		t.Errorf("Position past the end: expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestTextBufCursors(t *testing.T) {
+	buf := textbuf.NewBufferSize("hello world", 4)
+	start, _ := buf.NewCursor(0)
+	word, _ := buf.NewCursor(6)
+	end, _ := buf.NewCursor(buf.Len())
+
+	// Typing at a cursor moves it and every cursor after it
+	if err := word.Insert("big "); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if buf.String() != "hello big world" || word.Offset() != 10 || end.Offset() != 15 || start.Offset() != 0 {
+		t.Fatalf("After insert: %q, cursors at %d, %d, %d", buf.String(), start.Offset(), word.Offset(), end.Offset())
+	}
+
+	// Cursors inside a deleted range collapse to its start
+	if err := buf.Delete(4, 8); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if buf.String() != "hellrld" || word.Offset() != 4 || end.Offset() != 7 {
+		t.Fatalf("After delete: %q, cursors at %d, %d", buf.String(), word.Offset(), end.Offset())
+	}
+
+	end.Close()
+	buf.Insert(0, "\n")
+	if end.Offset() != 7 {
+		t.Errorf("Closed cursor moved to %d", end.Offset())
+	}
+	if line, col := word.Position(); line != 1 || col != 4 {
+		t.Errorf("Expected cursor at line 1, column 4, got %d, %d", line, col)
+	}
+	if err := end.Seek(0); err == nil {
+		t.Errorf("Expected an error seeking a closed cursor")
+	}
+	if _, err := buf.NewCursor(-1); !errors.Is(err, textbuf.ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestTextBufReader(t *testing.T) {
+	text := strings.Repeat("ascii, ünïcödé and 漢字\n", 40)
+	buf := textbuf.NewBufferSize(text, 7)
+
+	// One byte at a time splits every multi-byte rune across reads
+	got, err := io.ReadAll(iotest.OneByteReader(buf.NewReader()))
+	if err != nil || string(got) != text {
+		t.Errorf("ReadAll = %q, %v; want the whole text", got, err)
+	}
+	if err := iotest.TestReader(buf.NewReader(), []byte(text)); err != nil {
+		t.Error(err)
+	}
+
+	var out bytes.Buffer
+	n, err := buf.WriteTo(&out)
+	if err != nil || n != int64(len(text)) || out.String() != text {
+		t.Errorf("WriteTo wrote %d bytes, %v; want %d", n, err, len(text))
+	}
+
+	empty := textbuf.NewBuffer("")
+	if got, err := io.ReadAll(empty.NewReader()); err != nil || len(got) != 0 {
+		t.Errorf("Reading an empty buffer = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JustMrNone/ll/singly"
+	"github.com/JustMrNone/ll/viz"
+)
+
+func TestVizHealthyList(t *testing.T) {
+	list := newDoubly(1, 2, 3)
+	g := viz.Doubly(list)
+	if anomalies := g.Anomalies(); len(anomalies) != 0 {
+		t.Errorf("Expected no anomalies, got %v", anomalies)
+	}
+
+	var dot bytes.Buffer
+	g.WriteDOT(&dot)
+	for _, want := range []string{"head -> n0;", "tail -> n2;", `n1 -> n2 [label="next"];`, `n2 -> n1 [label="prev", style=dashed];`} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("Expected DOT output to contain %q, got:\n%s", want, dot.String())
+		}
+	}
+}
+
+func TestVizCorruptLists(t *testing.T) {
+	t.Run("Broken Prev", func(t *testing.T) {
+		list := newDoubly(1, 2, 3)
+		list.Tail.Prev = list.Head
+		anomalies := viz.Doubly(list).Anomalies()
+		if len(anomalies) != 1 || !strings.Contains(anomalies[0], "prev does not point back") {
+			t.Errorf("Expected one broken prev link, got %v", anomalies)
+		}
+	})
+
+	t.Run("Doubly Cycle", func(t *testing.T) {
+		list := newDoubly(1, 2, 3)
+		list.Tail.Next = list.Head
+		g := viz.Doubly(list)
+		found := strings.Join(g.Anomalies(), "\n")
+		if !strings.Contains(found, "cycle back-edge") || !strings.Contains(found, "tail is not the last node") {
+			t.Errorf("Expected the cycle and tail to be flagged, got %v", found)
+		}
+
+		var mermaid bytes.Buffer
+		g.WriteMermaid(&mermaid)
+		if !strings.Contains(mermaid.String(), "linkStyle") {
+			t.Errorf("Expected anomalous Mermaid edges to be styled, got:\n%s", mermaid.String())
+		}
+	})
+
+	t.Run("Singly Cycle", func(t *testing.T) {
+		list := singly.NewSinglyLinkedList()
+		list.FromSlice([]any{1, 2, 3})
+		list.Head.Next.Next.Next = list.Head.Next
+		anomalies := viz.Singly(list).Anomalies()
+		if len(anomalies) != 1 || anomalies[0] != "next n2 -> n1: cycle back-edge" {
+			t.Errorf("Expected one cycle back-edge, got %v", anomalies)
+		}
+	})
+
+	t.Run("Stray Tail", func(t *testing.T) {
+		list := newDoubly(1, 2)
+		stray := newDoubly(9).Head
+		stray.Prev = list.Tail
+		list.Tail = stray
+		g := viz.Doubly(list)
+		found := strings.Join(g.Anomalies(), "\n")
+		if !strings.Contains(found, "unreachable from Head") {
+			t.Errorf("Expected the stray tail node to be flagged, got %v", found)
+		}
+	})
+}
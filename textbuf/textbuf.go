@@ -0,0 +1,452 @@
+// Package textbuf implements a text buffer for editors. The text is stored
+// as a doubly linked list of bounded-size rune chunks, so an edit only copies
+// the chunk it touches rather than the whole text.
+package textbuf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/JustMrNone/ll/doubly"
+)
+
+// DefaultChunkSize is the maximum number of runes per chunk used by
+// NewBuffer.
+const DefaultChunkSize = 512
+
+// ErrOutOfRange is returned for offsets, lengths and line/column positions
+// that fall outside the text. It may be wrapped with more detail, so check
+// for it with errors.Is.
+var ErrOutOfRange = errors.New("position out of range")
+
+// chunk is what the underlying list stores: a piece of the text and the
+// number of newlines in it.
+type chunk struct {
+	runes []rune
+	lines int
+}
+
+// Buffer is a mutable piece of text addressed by rune offset. Positions are
+// rune offsets from the start of the text, and lines and columns count from
+// zero, with columns in runes. Invalid UTF-8 in inserted strings is stored as
+// utf8.RuneError. A Buffer is not safe for concurrent use.
+type Buffer struct {
+	chunks    *doubly.LinkedList // *chunk values in order; none is empty
+	chunkSize int                // Maximum runes per chunk
+	length    int                // Number of runes
+	lines     int                // Number of newlines
+	cursors   []*Cursor          // Open cursors, kept in step with edits
+}
+
+// NewBuffer creates a buffer holding text, with chunks of up to
+// DefaultChunkSize runes.
+func NewBuffer(text string) *Buffer {
+	return NewBufferSize(text, DefaultChunkSize)
+}
+
+// NewBufferSize creates a buffer holding text, with chunks of up to
+// chunkSize runes. Smaller chunks make edits cheaper and offset lookups
+// dearer. A chunkSize below 1 means DefaultChunkSize.
+func NewBufferSize(text string, chunkSize int) *Buffer {
+	if chunkSize < 1 {
+		chunkSize = DefaultChunkSize
+	}
+	b := &Buffer{chunks: doubly.NewDoublyLinkedList(), chunkSize: chunkSize}
+	b.Insert(0, text)
+	return b
+}
+
+// Len returns the number of runes in the buffer.
+func (b *Buffer) Len() int {
+	return b.length
+}
+
+// LineCount returns the number of lines, which is one more than the number
+// of newlines. An empty buffer has one empty line.
+func (b *Buffer) LineCount() int {
+	return b.lines + 1
+}
+
+// String returns the whole text.
+func (b *Buffer) String() string {
+	s, _ := b.Slice(0, b.length)
+	return s
+}
+
+// Insert inserts s at offset pos. Cursors at pos or after it move along
+// with the text that follows them, so they end up after the inserted text.
+func (b *Buffer) Insert(pos int, s string) error {
+	if pos < 0 || pos > b.length {
+		return fmt.Errorf("%w: offset %d, length %d", ErrOutOfRange, pos, b.length)
+	}
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return nil
+	}
+
+	node, off := b.locate(pos)
+	if node == nil {
+		b.place(nil, runes)
+	} else {
+		c := node.Value.(*chunk)
+		merged := make([]rune, 0, len(c.runes)+len(runes))
+		merged = append(merged, c.runes[:off]...)
+		merged = append(merged, runes...)
+		merged = append(merged, c.runes[off:]...)
+		prev := node.Prev
+		b.chunks.Remove(node)
+		b.place(prev, merged)
+	}
+
+	b.length += len(runes)
+	b.lines += countLines(runes)
+	for _, c := range b.cursors {
+		if c.offset >= pos {
+			c.offset += len(runes)
+		}
+	}
+	return nil
+}
+
+// Delete removes n runes starting at offset pos. Cursors inside the deleted
+// range move to pos.
+func (b *Buffer) Delete(pos, n int) error {
+	if pos < 0 || n < 0 || pos+n > b.length {
+		return fmt.Errorf("%w: delete %d runes at offset %d, length %d", ErrOutOfRange, n, pos, b.length)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	node, off := b.locate(pos)
+	for left := n; left > 0; {
+		c := node.Value.(*chunk)
+		end := min(len(c.runes), off+left)
+		removed := countLines(c.runes[off:end])
+		c.runes = append(c.runes[:off:off], c.runes[end:]...)
+		c.lines -= removed
+		b.lines -= removed
+		left -= end - off
+
+		next := node.Next
+		if len(c.runes) == 0 {
+			b.chunks.Remove(node)
+		}
+		node, off = next, 0
+	}
+	b.length -= n
+
+	// Merge the chunks on either side of the cut, so that deletes do not
+	// leave the list full of tiny chunks
+	if node, _ := b.locate(pos); node != nil {
+		if node.Prev != nil {
+			node = node.Prev
+		}
+		b.join(node)
+		if node.Next != nil {
+			b.join(node.Next)
+		}
+	}
+
+	for _, c := range b.cursors {
+		switch {
+		case c.offset >= pos+n:
+			c.offset -= n
+		case c.offset > pos:
+			c.offset = pos
+		}
+	}
+	return nil
+}
+
+// Slice returns the text between offsets from and to.
+func (b *Buffer) Slice(from, to int) (string, error) {
+	if from < 0 || to < from || to > b.length {
+		return "", fmt.Errorf("%w: slice [%d:%d], length %d", ErrOutOfRange, from, to, b.length)
+	}
+	out := make([]rune, 0, to-from)
+	node, off := b.locate(from)
+	for left := to - from; left > 0; node, off = node.Next, 0 {
+		c := node.Value.(*chunk)
+		end := min(len(c.runes), off+left)
+		out = append(out, c.runes[off:end]...)
+		left -= end - off
+	}
+	return string(out), nil
+}
+
+// Offset converts a line and column to an offset. The column may be the
+// length of the line, which is the offset of its newline, or of the end of
+// the text on the last line.
+func (b *Buffer) Offset(line, col int) (int, error) {
+	if line < 0 || line > b.lines || col < 0 {
+		return 0, fmt.Errorf("%w: line %d, column %d", ErrOutOfRange, line, col)
+	}
+
+	// Skip the chunks that end before the line starts
+	offset, node := 0, b.chunks.Head
+	for ; node != nil && node.Value.(*chunk).lines < line; node = node.Next {
+		c := node.Value.(*chunk)
+		line -= c.lines
+		offset += len(c.runes)
+	}
+
+	// Find the start of the line, then walk col runes without passing a
+	// newline
+	for ; node != nil; node = node.Next {
+		for _, r := range node.Value.(*chunk).runes {
+			switch {
+			case line > 0:
+				if r == '\n' {
+					line--
+				}
+			case col == 0:
+				return offset, nil
+			case r == '\n':
+				return 0, fmt.Errorf("%w: column %d is past the end of the line", ErrOutOfRange, col)
+			default:
+				col--
+			}
+			offset++
+		}
+	}
+	if col > 0 {
+		return 0, fmt.Errorf("%w: column %d is past the end of the line", ErrOutOfRange, col)
+	}
+	return offset, nil
+}
+
+// Position converts an offset to a line and column.
+func (b *Buffer) Position(offset int) (line, col int, err error) {
+	if offset < 0 || offset > b.length {
+		return 0, 0, fmt.Errorf("%w: offset %d, length %d", ErrOutOfRange, offset, b.length)
+	}
+	for node := b.chunks.Head; node != nil && offset > 0; node = node.Next {
+		c := node.Value.(*chunk)
+		if offset >= len(c.runes) {
+			// The whole chunk comes before offset
+			if c.lines > 0 {
+				line += c.lines
+				col = len(c.runes) - lastNewline(c.runes) - 1
+			} else {
+				col += len(c.runes)
+			}
+			offset -= len(c.runes)
+			continue
+		}
+		for _, r := range c.runes[:offset] {
+			if r == '\n' {
+				line++
+				col = 0
+			} else {
+				col++
+			}
+		}
+		break
+	}
+	return line, col, nil
+}
+
+// WriteTo writes the whole text to w as UTF-8. It implements io.WriterTo.
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	var buf []byte
+	for node := b.chunks.Head; node != nil; node = node.Next {
+		buf = buf[:0]
+		for _, r := range node.Value.(*chunk).runes {
+			buf = utf8.AppendRune(buf, r)
+		}
+		n, err := w.Write(buf)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// NewReader returns a reader over the whole text as UTF-8. The buffer must
+// not be edited until the reader is done with it.
+func (b *Buffer) NewReader() *Reader {
+	return &Reader{node: b.chunks.Head}
+}
+
+// Reader reads the text of a Buffer as UTF-8. It implements io.Reader.
+type Reader struct {
+	node    *doubly.Node // Chunk being read
+	index   int          // Next rune to read in the chunk
+	pending []byte       // Rest of a rune that did not fit in the last read
+}
+
+// Read reads up to len(p) bytes of the text into p.
+func (r *Reader) Read(p []byte) (int, error) {
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	for n < len(p) && r.node != nil {
+		runes := r.node.Value.(*chunk).runes
+		if r.index == len(runes) {
+			r.node, r.index = r.node.Next, 0
+			continue
+		}
+		rn := runes[r.index]
+		r.index++
+		if size := utf8.RuneLen(rn); size > 0 && size <= len(p)-n {
+			n += utf8.EncodeRune(p[n:], rn)
+			continue
+		}
+		// Only part of the rune fits, keep the rest for the next read
+		encoded := utf8.AppendRune(nil, rn)
+		copied := copy(p[n:], encoded)
+		n += copied
+		r.pending = encoded[copied:]
+	}
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Cursor is a position in a Buffer that stays on the same text across
+// edits. Text inserted at the cursor goes before it.
+type Cursor struct {
+	buf    *Buffer
+	offset int
+	closed bool
+}
+
+// NewCursor creates a cursor at offset. Close it when it is no longer needed,
+// so edits stop updating it.
+func (b *Buffer) NewCursor(offset int) (*Cursor, error) {
+	if offset < 0 || offset > b.length {
+		return nil, fmt.Errorf("%w: offset %d, length %d", ErrOutOfRange, offset, b.length)
+	}
+	c := &Cursor{buf: b, offset: offset}
+	b.cursors = append(b.cursors, c)
+	return c, nil
+}
+
+// Offset returns the cursor's offset.
+func (c *Cursor) Offset() int {
+	return c.offset
+}
+
+// Position returns the cursor's line and column. A closed cursor that the
+// text has shrunk past reports the end of the text.
+func (c *Cursor) Position() (line, col int) {
+	line, col, _ = c.buf.Position(min(c.offset, c.buf.length))
+	return line, col
+}
+
+// Seek moves the cursor to offset.
+func (c *Cursor) Seek(offset int) error {
+	if c.closed {
+		return fmt.Errorf("cursor is closed")
+	}
+	if offset < 0 || offset > c.buf.length {
+		return fmt.Errorf("%w: offset %d, length %d", ErrOutOfRange, offset, c.buf.length)
+	}
+	c.offset = offset
+	return nil
+}
+
+// Insert inserts s at the cursor, which ends up after it, like typing.
+func (c *Cursor) Insert(s string) error {
+	if c.closed {
+		return fmt.Errorf("cursor is closed")
+	}
+	return c.buf.Insert(c.offset, s)
+}
+
+// Close detaches the cursor from its buffer. Its offset no longer changes.
+func (c *Cursor) Close() {
+	if c.closed {
+		return
+	}
+	cursors := c.buf.cursors
+	for i, other := range cursors {
+		if other == c {
+			c.buf.cursors = append(cursors[:i], cursors[i+1:]...)
+			break
+		}
+	}
+	c.closed = true
+}
+
+// locate returns the chunk holding offset pos and the index of pos within
+// it. The end of the text is located at the end of the last chunk, and an
+// empty buffer has no chunk at all. It walks from whichever end is closer.
+func (b *Buffer) locate(pos int) (*doubly.Node, int) {
+	if pos*2 <= b.length {
+		node := b.chunks.Head
+		for node != nil {
+			n := len(node.Value.(*chunk).runes)
+			if pos < n || node.Next == nil {
+				break
+			}
+			pos -= n
+			node = node.Next
+		}
+		return node, pos
+	}
+	node, rest := b.chunks.Tail, b.length-pos
+	for {
+		n := len(node.Value.(*chunk).runes)
+		if rest <= n {
+			// Offsets on a chunk boundary belong to the later chunk
+			if rest == 0 && node.Next != nil {
+				return node.Next, 0
+			}
+			return node, n - rest
+		}
+		rest -= n
+		node = node.Prev
+	}
+}
+
+// place inserts runes as one or more chunks after prev, or at the start when
+// prev is nil, splitting them into pieces of similar size.
+func (b *Buffer) place(prev *doubly.Node, runes []rune) {
+	pieces := (len(runes) + b.chunkSize - 1) / b.chunkSize
+	for i := 0; i < pieces; i++ {
+		piece := runes[len(runes)*i/pieces : len(runes)*(i+1)/pieces]
+		prev = b.chunks.InsertAfter(prev, &chunk{runes: piece, lines: countLines(piece)})
+	}
+}
+
+// join merges node's chunk with the next one when they fit in one chunk.
+func (b *Buffer) join(node *doubly.Node) {
+	next := node.Next
+	if next == nil {
+		return
+	}
+	c, n := node.Value.(*chunk), next.Value.(*chunk)
+	if len(c.runes)+len(n.runes) > b.chunkSize {
+		return
+	}
+	c.runes = append(c.runes, n.runes...)
+	c.lines += n.lines
+	b.chunks.Remove(next)
+}
+
+// countLines returns the number of newlines in runes.
+func countLines(runes []rune) int {
+	count := 0
+	for _, r := range runes {
+		if r == '\n' {
+			count++
+		}
+	}
+	return count
+}
+
+// lastNewline returns the index of the last newline in runes, or -1.
+func lastNewline(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] == '\n' {
+			return i
+		}
+	}
+	return -1
+}
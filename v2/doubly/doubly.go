@@ -0,0 +1,813 @@
+// Package doubly implements a doubly linked list whose structure can only be
+// changed through its methods. Nodes are exposed as read-only Element handles.
+package doubly
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// Errors returned by list operations. They may be wrapped with more
+// detail, so check for them with errors.Is.
+var (
+	ErrEmpty            = errors.New("list is empty")
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrNotFound         = errors.New("value not found")
+	ErrMismatchedTypes  = errors.New("mismatched types in list")
+	ErrUnsupportedType  = errors.New("unsupported type for sorting")
+	ErrCorrupt          = errors.New("corrupt list")
+	ErrNotInList        = errors.New("element is not in this list")
+	ErrNotComparable    = errors.New("value is not comparable")
+)
+
+// Element is a handle to a node in a doubly linked list.
+type Element struct {
+	value any
+	next  *Element
+	prev  *Element
+	list  *LinkedList // List the element belongs to, nil once removed
+}
+
+// Value returns the value stored in the element.
+func (e *Element) Value() any {
+	return e.value
+}
+
+// Next returns the following element, or nil at the end of the list.
+func (e *Element) Next() *Element {
+	if e.list == nil {
+		return nil
+	}
+	return e.next
+}
+
+// Prev returns the preceding element, or nil at the start of the list.
+func (e *Element) Prev() *Element {
+	if e.list == nil {
+		return nil
+	}
+	return e.prev
+}
+
+// List returns the list the element belongs to, or nil once it is removed.
+func (e *Element) List() *LinkedList {
+	return e.list
+}
+
+// LinkedList represents a doubly linked list data structure.
+type LinkedList struct {
+	head *Element // First element in the list
+	tail *Element // Last element in the list
+	size int      // Number of elements in the list
+
+	observers *event.Hub // Subscribers to change events, created on demand
+}
+
+// NewDoublyLinkedList creates and returns an empty doubly linked list.
+func NewDoublyLinkedList() *LinkedList {
+	return &LinkedList{}
+}
+
+// Front returns the first element of the list, or nil if it is empty.
+func (ll *LinkedList) Front() *Element {
+	return ll.head
+}
+
+// Back returns the last element of the list, or nil if it is empty.
+func (ll *LinkedList) Back() *Element {
+	return ll.tail
+}
+
+// Len returns the number of elements in the list.
+func (ll *LinkedList) Len() int {
+	return ll.size
+}
+
+// Prepend adds a new element with the given value at the beginning of the list.
+func (ll *LinkedList) Prepend(value any) error {
+	ll.linkBefore(&Element{value: value}, ll.head)
+	ll.emit(event.Inserted{Index: 0, Value: value})
+	return nil
+}
+
+// Append adds a new element with the given value at the end of the list.
+func (ll *LinkedList) Append(value any) error {
+	ll.linkBefore(&Element{value: value}, nil)
+	ll.emit(event.Inserted{Index: ll.size - 1, Value: value})
+	return nil
+}
+
+// Print displays the list elements from head to tail.
+func (ll *LinkedList) Print() {
+	for current := ll.head; current != nil; current = current.next {
+		fmt.Print(current.value)
+		if current.next != nil {
+			fmt.Print(" <-> ")
+		}
+	}
+	fmt.Print("\n")
+}
+
+// IntoSlice converts the list into a slice.
+func (ll *LinkedList) IntoSlice() []any {
+	var retSlice []any
+	for current := ll.head; current != nil; current = current.next {
+		retSlice = append(retSlice, current.value)
+	}
+	return retSlice
+}
+
+// FromSlice creates a list from the given slice.
+func (ll *LinkedList) FromSlice(slice []any) error {
+	if slice == nil {
+		return fmt.Errorf("cannot create list from nil slice")
+	}
+	ll.beginBatch()
+	defer ll.endBatch()
+	ll.Clear()
+	for _, value := range slice {
+		ll.Append(value)
+	}
+	return nil
+}
+
+// IntoArray converts the list into a fixed-size array.
+func (ll *LinkedList) IntoArray() []any {
+	arr := make([]any, ll.size)
+	for i, current := 0, ll.head; current != nil; i, current = i+1, current.next {
+		arr[i] = current.value
+	}
+	return arr
+}
+
+// FromArray creates a list from the given array.
+func (ll *LinkedList) FromArray(arr []any) error {
+	if arr == nil {
+		return fmt.Errorf("cannot create list from nil array")
+	}
+	return ll.FromSlice(arr)
+}
+
+// Search finds the first occurrence of a value in the list and returns its
+// index. Values such as slices and maps can't be compared with ==, so
+// searching for one returns ErrNotComparable; use SearchFunc instead.
+func (ll *LinkedList) Search(value any) (int, error) {
+	if !equality.Comparable(value) {
+		return -1, fmt.Errorf("%w: %T; use SearchFunc", ErrNotComparable, value)
+	}
+	return ll.SearchFunc(func(v any) bool { return v == value })
+}
+
+// SearchFunc returns the index of the first value for which match returns
+// true, or ErrNotFound.
+func (ll *LinkedList) SearchFunc(match func(any) bool) (int, error) {
+	if index := ll.IndexFunc(match); index >= 0 {
+		return index, nil
+	}
+	return -1, ErrNotFound
+}
+
+// IndexFunc returns the index of the first value for which match returns
+// true, or -1.
+func (ll *LinkedList) IndexFunc(match func(any) bool) int {
+	for i, v := range ll.All() {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexFunc returns the index of the last value for which match returns
+// true, or -1. It walks from the back.
+func (ll *LinkedList) LastIndexFunc(match func(any) bool) int {
+	for i, v := range ll.Backward() {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Shift removes the first element from the list.
+func (ll *LinkedList) Shift() error {
+	if ll.head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	return ll.Remove(ll.head)
+}
+
+// Pop removes the last element from the list.
+func (ll *LinkedList) Pop() error {
+	if ll.head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	return ll.Remove(ll.tail)
+}
+
+// Delete removes the first occurrence of the specified value from the list.
+// Deleting a value that can't be compared with ==, such as a slice, returns
+// ErrNotComparable; use DeleteFunc instead.
+func (ll *LinkedList) Delete(value any) error {
+	if !equality.Comparable(value) {
+		return fmt.Errorf("%w: %T; use DeleteFunc", ErrNotComparable, value)
+	}
+	return ll.DeleteFunc(func(v any) bool { return v == value })
+}
+
+// DeleteFunc removes the first value for which match returns true.
+func (ll *LinkedList) DeleteFunc(match func(any) bool) error {
+	if ll.head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	for current := ll.head; current != nil; current = current.next {
+		if match(current.value) {
+			return ll.Remove(current)
+		}
+	}
+	return fmt.Errorf("%w in the list", ErrNotFound)
+}
+
+// IsEmpty returns true if the list has no elements.
+func (ll *LinkedList) IsEmpty() bool {
+	return ll.size == 0
+}
+
+// Clear removes all elements from the list.
+func (ll *LinkedList) Clear() {
+	count := ll.size
+	for current := ll.head; current != nil; current = current.next {
+		current.list = nil
+	}
+	ll.head = nil
+	ll.tail = nil
+	ll.size = 0
+	if count > 0 {
+		ll.emit(event.Cleared{Count: count})
+	}
+}
+
+// Get returns the value at the specified index.
+func (ll *LinkedList) Get(index int) (any, error) {
+	if index < 0 || index >= ll.size {
+		return nil, fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	return ll.at(index).value, nil
+}
+
+// Set replaces the value at the specified index.
+func (ll *LinkedList) Set(index int, value any) error {
+	if index < 0 || index >= ll.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	element := ll.at(index)
+	old := element.value
+	element.value = value
+	ll.emit(event.Updated{Index: index, Old: old, Value: value})
+	return nil
+}
+
+// GetMiddle returns the middle element of the list. For an even number of
+// elements it returns the first of the two middle ones.
+func (ll *LinkedList) GetMiddle() (any, error) {
+	if ll.head == nil {
+		return nil, ErrEmpty
+	}
+	front, back := ll.head, ll.tail
+	for front != back && front.next != back {
+		front = front.next
+		back = back.prev
+	}
+	return front.value, nil
+}
+
+// Insert adds a new value at the specified index.
+func (ll *LinkedList) Insert(value any, index int) error {
+	if index < 0 || index > ll.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	var at *Element
+	if index < ll.size {
+		at = ll.at(index)
+	}
+	ll.linkBefore(&Element{value: value}, at)
+	ll.emit(event.Inserted{Index: index, Value: value})
+	return nil
+}
+
+// DeleteAt removes the element at the specified index.
+func (ll *LinkedList) DeleteAt(index int) error {
+	if index < 0 || index >= ll.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	element := ll.at(index)
+	ll.unlink(element)
+	ll.emit(event.Removed{Index: index, Value: element.value})
+	return nil
+}
+
+// Reverse reverses the order of elements in the list.
+func (ll *LinkedList) Reverse() error {
+	if ll.head == nil {
+		return fmt.Errorf("cannot reverse: %w", ErrEmpty)
+	}
+	if ll.size <= 1 {
+		return nil
+	}
+	for current := ll.head; current != nil; current = current.prev {
+		current.next, current.prev = current.prev, current.next
+	}
+	ll.head, ll.tail = ll.tail, ll.head
+	ll.emit(event.Reordered{})
+	return nil
+}
+
+// All returns an iterator over the indices and values of the list from head
+// to tail.
+func (ll *LinkedList) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		index := 0
+		for current := ll.head; current != nil; current = current.next {
+			if !yield(index, current.value) {
+				return
+			}
+			index++
+		}
+	}
+}
+
+// Backward returns an iterator over the indices and values of the list from
+// tail to head.
+func (ll *LinkedList) Backward() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		index := ll.size - 1
+		for current := ll.tail; current != nil; current = current.prev {
+			if !yield(index, current.value) {
+				return
+			}
+			index--
+		}
+	}
+}
+
+// Contains checks if a value exists in the list. A value that can't be
+// compared with ==, such as a slice, is never found; use ContainsFunc
+// instead.
+func (ll *LinkedList) Contains(value any) bool {
+	_, err := ll.Search(value)
+	return err == nil
+}
+
+// ContainsFunc reports whether match returns true for any value.
+func (ll *LinkedList) ContainsFunc(match func(any) bool) bool {
+	return ll.IndexFunc(match) >= 0
+}
+
+// PrintReverse displays the list elements from tail to head.
+func (ll *LinkedList) PrintReverse() {
+	for current := ll.tail; current != nil; current = current.prev {
+		fmt.Print(current.value)
+		if current.prev != nil {
+			fmt.Print(" <-> ")
+		}
+	}
+	fmt.Print("\n")
+}
+
+// Clone returns a shallow copy of the list: a new list holding the same
+// values, without the subscribers.
+func (ll *LinkedList) Clone() *LinkedList {
+	return ll.CloneFunc(func(v any) any { return v })
+}
+
+// CloneFunc returns a copy of the list holding copy(v) for every value v,
+// for deep copies of values such as slices and pointers.
+func (ll *LinkedList) CloneFunc(copy func(any) any) *LinkedList {
+	clone := NewDoublyLinkedList()
+	for current := ll.head; current != nil; current = current.next {
+		clone.linkBefore(&Element{value: copy(current.value)}, nil)
+	}
+	return clone
+}
+
+// Equal reports whether other holds the same values in the same order,
+// compared with ==. Values that can't be compared with ==, such as slices,
+// are never equal; use EqualFunc for them. A nil list counts as empty.
+func (ll *LinkedList) Equal(other *LinkedList) bool {
+	return ll.EqualFunc(other, equality.Equal)
+}
+
+// EqualFunc reports whether other holds the same number of values as the
+// list and eq returns true for every pair of values at the same index. A nil
+// list counts as empty.
+func (ll *LinkedList) EqualFunc(other *LinkedList, eq func(a, b any) bool) bool {
+	if other == nil {
+		return ll.size == 0
+	}
+	if ll.size != other.size {
+		return false
+	}
+	for a, b := ll.head, other.head; a != nil && b != nil; a, b = a.next, b.next {
+		if !eq(a.value, b.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares the list with other lexicographically, using cmp to
+// compare values. It returns the result of cmp for the first pair of values
+// that differ, or, when one list is a prefix of the other, -1 if the list is
+// the shorter one and +1 if other is. It returns 0 if the lists are equal. A
+// nil list counts as empty.
+func (ll *LinkedList) Compare(other *LinkedList, cmp func(a, b any) int) int {
+	a := ll.head
+	var b *Element
+	if other != nil {
+		b = other.head
+	}
+	for ; a != nil && b != nil; a, b = a.next, b.next {
+		if c := cmp(a.value, b.value); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a != nil:
+		return 1
+	case b != nil:
+		return -1
+	}
+	return 0
+}
+
+// Merge appends the values of another list to this one.
+func (ll *LinkedList) Merge(list *LinkedList) error {
+	if list == nil {
+		return fmt.Errorf("cannot merge with nil list")
+	}
+	ll.beginBatch()
+	defer ll.endBatch()
+	for _, value := range list.IntoSlice() {
+		ll.Append(value)
+	}
+	return nil
+}
+
+// Unique removes duplicate values from the list. If any value can't be
+// compared with ==, such as a slice, it returns ErrNotComparable and leaves
+// the list unchanged; use UniqueBy instead.
+func (ll *LinkedList) Unique() error {
+	return ll.UniqueBy(nil)
+}
+
+// UniqueBy removes every value whose key, as returned by key, matches the
+// key of an earlier value. Keys must be comparable with ==; a nil key
+// function uses the values themselves.
+func (ll *LinkedList) UniqueBy(key func(any) any) error {
+	if ll.head == nil {
+		return ErrEmpty
+	}
+	keys, err := ll.keysOf(key)
+	if err != nil {
+		return err
+	}
+
+	ll.beginBatch()
+	defer ll.endBatch()
+
+	visited := make(map[any]bool)
+	index := 0
+	for i, current := 0, ll.head; current != nil; i++ {
+		next := current.next
+		if visited[keys[i]] {
+			ll.unlink(current)
+			ll.emit(event.Removed{Index: index, Value: current.value})
+		} else {
+			visited[keys[i]] = true
+			index++
+		}
+		current = next
+	}
+	return nil
+}
+
+// Sort orders the elements in the list (supports int, string, float64).
+func (ll *LinkedList) Sort() error {
+	changed := false
+	defer func() {
+		if changed {
+			ll.emit(event.Reordered{})
+		}
+	}()
+
+	for swapped := ll.size > 1; swapped; {
+		swapped = false
+		for current := ll.head; current.next != nil; current = current.next {
+			greater, err := greater(current.value, current.next.value)
+			if err != nil {
+				return err
+			}
+			if greater {
+				current.value, current.next.value = current.next.value, current.value
+				swapped = true
+				changed = true
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the integrity of the list structure. Only a bug in this
+// package can make it fail.
+func (ll *LinkedList) Validate() error {
+	if ll.head == nil || ll.tail == nil {
+		if ll.head != ll.tail || ll.size != 0 {
+			return fmt.Errorf("%w: inconsistent empty list", ErrCorrupt)
+		}
+		return nil
+	}
+	if ll.head.prev != nil || ll.tail.next != nil {
+		return fmt.Errorf("%w: list ends are linked to other elements", ErrCorrupt)
+	}
+
+	count := 0
+	var last *Element
+	for current := ll.head; current != nil; current = current.next {
+		count++
+		if count > ll.size {
+			return fmt.Errorf("%w: list contains more elements than its length", ErrCorrupt)
+		}
+		if current.list != ll {
+			return fmt.Errorf("%w: element at index %d belongs to another list", ErrCorrupt, count-1)
+		}
+		if current.next != nil && current.next.prev != current {
+			return fmt.Errorf("%w: broken bidirectional link found", ErrCorrupt)
+		}
+		last = current
+	}
+	if count != ll.size {
+		return fmt.Errorf("%w: actual element count (%d) differs from length (%d)", ErrCorrupt, count, ll.size)
+	}
+	if last != ll.tail {
+		return fmt.Errorf("%w: tail does not point to last element", ErrCorrupt)
+	}
+	return nil
+}
+
+// Move relocates the element at index from so that it ends up at index to.
+func (ll *LinkedList) Move(from, to int) error {
+	if from < 0 || from >= ll.size || to < 0 || to >= ll.size {
+		return ErrIndexOutOfBounds
+	}
+	if from == to {
+		return nil
+	}
+
+	element := ll.at(from)
+	ll.unlink(element)
+
+	// Find the element that will follow it once moved
+	var at *Element
+	if to < ll.size {
+		at = ll.at(to)
+	}
+	ll.linkBefore(element, at)
+	ll.emit(event.Moved{From: from, To: to, Value: element.value})
+	return nil
+}
+
+// InsertAfter adds a new element with the given value right after e and
+// returns it. A nil e inserts at the beginning of the list.
+func (ll *LinkedList) InsertAfter(e *Element, value any) (*Element, error) {
+	at := ll.head
+	if e != nil {
+		if e.list != ll {
+			return nil, ErrNotInList
+		}
+		at = e.next
+	}
+	return ll.insertBefore(at, value), nil
+}
+
+// InsertBefore adds a new element with the given value right before e and
+// returns it. A nil e inserts at the end of the list.
+func (ll *LinkedList) InsertBefore(e *Element, value any) (*Element, error) {
+	if e != nil && e.list != ll {
+		return nil, ErrNotInList
+	}
+	return ll.insertBefore(e, value), nil
+}
+
+// Remove detaches e from the list.
+func (ll *LinkedList) Remove(e *Element) error {
+	if e == nil {
+		return fmt.Errorf("cannot remove nil element")
+	}
+	if e.list != ll {
+		return ErrNotInList
+	}
+	index := -1
+	if ll.observers.Active() {
+		index = ll.indexOf(e)
+	}
+	ll.unlink(e)
+	if index >= 0 {
+		ll.emit(event.Removed{Index: index, Value: e.value})
+	}
+	return nil
+}
+
+// MoveToBack relinks e at the end of the list without allocating.
+func (ll *LinkedList) MoveToBack(e *Element) error {
+	if e == nil || e.list != ll {
+		return ErrNotInList
+	}
+	if e == ll.tail {
+		return nil
+	}
+	from := -1
+	if ll.observers.Active() {
+		from = ll.indexOf(e)
+	}
+	ll.unlink(e)
+	ll.linkBefore(e, nil)
+	if from >= 0 {
+		ll.emit(event.Moved{From: from, To: ll.size - 1, Value: e.value})
+	}
+	return nil
+}
+
+// Subscribe registers fn to be called after every change to the list and
+// returns a function that unsubscribes it. Bulk operations such as FromSlice,
+// Merge and Unique deliver their changes as a single event.Batch.
+//
+// Subscribe creates the list's event hub on first use, so like the other
+// methods it must not run concurrently with changes to the list. The
+// function it returns may be called from any goroutine.
+func (ll *LinkedList) Subscribe(fn func(event.Event)) (unsubscribe func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.Subscribe(fn)
+}
+
+// SubscribeChan is like Subscribe but delivers events on a channel with the
+// given buffer size. Mutations block while the buffer is full. Unsubscribing
+// closes the channel.
+func (ll *LinkedList) SubscribeChan(buffer int) (<-chan event.Event, func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.SubscribeChan(buffer)
+}
+
+// insertBefore links a new element in front of at and emits the insertion.
+func (ll *LinkedList) insertBefore(at *Element, value any) *Element {
+	element := &Element{value: value}
+	ll.linkBefore(element, at)
+	if ll.observers.Active() {
+		ll.emit(event.Inserted{Index: ll.indexOf(element), Value: value})
+	}
+	return element
+}
+
+// at returns the element at index, which must be in range, walking from
+// whichever end is closer.
+func (ll *LinkedList) at(index int) *Element {
+	if index < ll.size/2 {
+		current := ll.head
+		for i := 0; i < index; i++ {
+			current = current.next
+		}
+		return current
+	}
+	current := ll.tail
+	for i := ll.size - 1; i > index; i-- {
+		current = current.prev
+	}
+	return current
+}
+
+// unlink detaches e from the list without emitting an event.
+func (ll *LinkedList) unlink(e *Element) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		ll.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		ll.tail = e.prev
+	}
+	e.next = nil
+	e.prev = nil
+	e.list = nil
+	ll.size--
+}
+
+// linkBefore attaches e in front of at, or at the end of the list when at is
+// nil, without emitting an event.
+func (ll *LinkedList) linkBefore(e, at *Element) {
+	e.list = ll
+	if at == nil {
+		e.prev = ll.tail
+		e.next = nil
+		if ll.tail != nil {
+			ll.tail.next = e
+		} else {
+			ll.head = e
+		}
+		ll.tail = e
+	} else {
+		e.prev = at.prev
+		e.next = at
+		if at.prev != nil {
+			at.prev.next = e
+		} else {
+			ll.head = e
+		}
+		at.prev = e
+	}
+	ll.size++
+}
+
+// indexOf returns the position of e in the list, or -1 if it is not there.
+func (ll *LinkedList) indexOf(e *Element) int {
+	index := 0
+	for current := ll.head; current != nil; current = current.next {
+		if current == e {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// keysOf returns the key of every value in the list, or ErrNotComparable if
+// one of them can't be used as a map key. A nil key function uses the values
+// themselves.
+func (ll *LinkedList) keysOf(key func(any) any) ([]any, error) {
+	keys := make([]any, 0, ll.size)
+	for current := ll.head; current != nil; current = current.next {
+		k := current.value
+		if key != nil {
+			k = key(k)
+		}
+		if !equality.Comparable(k) {
+			return nil, fmt.Errorf("%w: %T at index %d", ErrNotComparable, k, len(keys))
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// greater reports whether a sorts after b. Both must be the same supported type.
+func greater(a, b any) (bool, error) {
+	switch x := a.(type) {
+	case int:
+		y, ok := b.(int)
+		if !ok {
+			return false, ErrMismatchedTypes
+		}
+		return x > y, nil
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return false, ErrMismatchedTypes
+		}
+		return x > y, nil
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return false, ErrMismatchedTypes
+		}
+		return x > y, nil
+	}
+	return false, ErrUnsupportedType
+}
+
+// emit sends e to subscribers, if there are any.
+func (ll *LinkedList) emit(e event.Event) {
+	if ll.observers.Active() {
+		ll.observers.Emit(e)
+	}
+}
+
+// beginBatch starts collecting events for a bulk operation.
+func (ll *LinkedList) beginBatch() {
+	if ll.observers != nil {
+		ll.observers.Begin()
+	}
+}
+
+// endBatch delivers the events collected since beginBatch.
+func (ll *LinkedList) endBatch() {
+	if ll.observers != nil {
+		ll.observers.End()
+	}
+}
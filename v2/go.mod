@@ -0,0 +1,9 @@
+module github.com/JustMrNone/ll/v2
+
+go 1.23.6
+
+require github.com/JustMrNone/ll v1.0.0
+
+// The event and listtest packages come from v1, which lives one directory up.
+// Consumers fetch the tagged v1.0.0 release instead.
+replace github.com/JustMrNone/ll => ../
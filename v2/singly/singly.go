@@ -0,0 +1,742 @@
+// Package singly implements a singly linked list whose structure can only be
+// changed through its methods. Nodes are exposed as read-only Element handles.
+package singly
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/internal/equality"
+)
+
+// Errors returned by list operations. They may be wrapped with more
+// detail, so check for them with errors.Is.
+var (
+	ErrEmpty            = errors.New("list is empty")
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrNotFound         = errors.New("value not found")
+	ErrMismatchedTypes  = errors.New("mismatched types in list")
+	ErrUnsupportedType  = errors.New("unsupported type for sorting")
+	ErrCorrupt          = errors.New("corrupt list")
+	ErrNotInList        = errors.New("element is not in this list")
+	ErrNotComparable    = errors.New("value is not comparable")
+)
+
+// Element is a handle to a node in a singly linked list.
+type Element struct {
+	value any
+	next  *Element
+	list  *LinkedList // List the element belongs to, nil once removed
+}
+
+// Value returns the value stored in the element.
+func (e *Element) Value() any {
+	return e.value
+}
+
+// Next returns the following element, or nil at the end of the list.
+func (e *Element) Next() *Element {
+	if e.list == nil {
+		return nil
+	}
+	return e.next
+}
+
+// Prev returns the preceding element, or nil at the start of the list. Nodes
+// only link forward, so it walks from the head and takes linear time.
+func (e *Element) Prev() *Element {
+	if e.list == nil {
+		return nil
+	}
+	var prev *Element
+	for current := e.list.head; current != e; current = current.next {
+		prev = current
+	}
+	return prev
+}
+
+// List returns the list the element belongs to, or nil once it is removed.
+func (e *Element) List() *LinkedList {
+	return e.list
+}
+
+// LinkedList represents a singly linked list data structure.
+type LinkedList struct {
+	head *Element // First element in the list
+	size int      // Number of elements in the list
+
+	observers *event.Hub // Subscribers to change events, created on demand
+}
+
+// NewSinglyLinkedList creates and returns an empty singly linked list.
+func NewSinglyLinkedList() *LinkedList {
+	return &LinkedList{}
+}
+
+// Front returns the first element of the list, or nil if it is empty.
+func (ll *LinkedList) Front() *Element {
+	return ll.head
+}
+
+// Len returns the number of elements in the list.
+func (ll *LinkedList) Len() int {
+	return ll.size
+}
+
+// Print displays the list elements from head to tail.
+func (ll *LinkedList) Print() {
+	for current := ll.head; current != nil; current = current.next {
+		fmt.Print(current.value)
+		if current.next != nil {
+			fmt.Print(" -> ")
+		}
+	}
+	fmt.Print("\n")
+}
+
+// Length returns the number of elements in the list.
+func (ll *LinkedList) Length() int {
+	return ll.size
+}
+
+// IsEmpty returns true if the list has no elements.
+func (ll *LinkedList) IsEmpty() bool {
+	return ll.size == 0
+}
+
+// Clear removes all elements from the list.
+func (ll *LinkedList) Clear() {
+	count := ll.size
+	for current := ll.head; current != nil; current = current.next {
+		current.list = nil
+	}
+	ll.head = nil
+	ll.size = 0
+	if count > 0 {
+		ll.emit(event.Cleared{Count: count})
+	}
+}
+
+// Prepend adds a new element with the given value at the beginning of the list.
+func (ll *LinkedList) Prepend(value any) {
+	ll.head = &Element{value: value, next: ll.head, list: ll}
+	ll.size++
+	ll.emit(event.Inserted{Index: 0, Value: value})
+}
+
+// Append adds a new element with the given value at the end of the list.
+func (ll *LinkedList) Append(value any) {
+	element := &Element{value: value, list: ll}
+	if ll.head == nil {
+		ll.head = element
+	} else {
+		last := ll.head
+		for last.next != nil {
+			last = last.next
+		}
+		last.next = element
+	}
+	ll.size++
+	ll.emit(event.Inserted{Index: ll.size - 1, Value: value})
+}
+
+// InsertAfter adds a new element with the given value right after e and
+// returns it. A nil e inserts at the beginning of the list.
+func (ll *LinkedList) InsertAfter(e *Element, value any) (*Element, error) {
+	if e == nil {
+		ll.Prepend(value)
+		return ll.head, nil
+	}
+	if e.list != ll {
+		return nil, ErrNotInList
+	}
+	element := &Element{value: value, next: e.next, list: ll}
+	e.next = element
+	ll.size++
+	if ll.observers.Active() {
+		ll.emit(event.Inserted{Index: ll.indexOf(element), Value: value})
+	}
+	return element, nil
+}
+
+// Remove detaches e from the list. It takes linear time because the
+// preceding element has to be found from the head.
+func (ll *LinkedList) Remove(e *Element) error {
+	if e == nil {
+		return fmt.Errorf("cannot remove nil element")
+	}
+	if e.list != ll {
+		return ErrNotInList
+	}
+	index := 0
+	if ll.head == e {
+		ll.head = e.next
+	} else {
+		prev := ll.head
+		for index = 1; prev.next != e; index++ {
+			prev = prev.next
+		}
+		prev.next = e.next
+	}
+	ll.release(e)
+	ll.emit(event.Removed{Index: index, Value: e.value})
+	return nil
+}
+
+// IntoSlice converts the list into a slice.
+func (ll *LinkedList) IntoSlice() []any {
+	var retSlice []any
+	for current := ll.head; current != nil; current = current.next {
+		retSlice = append(retSlice, current.value)
+	}
+	return retSlice
+}
+
+// FromSlice creates a list from the given slice.
+func (ll *LinkedList) FromSlice(slice []any) error {
+	if slice == nil {
+		return fmt.Errorf("cannot create list from nil slice")
+	}
+	ll.beginBatch()
+	defer ll.endBatch()
+	ll.Clear()
+	ll.appendAll(slice)
+	return nil
+}
+
+// IntoArray converts the list into a fixed-size array.
+func (ll *LinkedList) IntoArray() []any {
+	arr := make([]any, ll.size)
+	for i, current := 0, ll.head; current != nil; i, current = i+1, current.next {
+		arr[i] = current.value
+	}
+	return arr
+}
+
+// FromArray creates a list from the given array.
+func (ll *LinkedList) FromArray(arr []any) error {
+	if arr == nil {
+		return fmt.Errorf("cannot create list from nil array")
+	}
+	ll.beginBatch()
+	defer ll.endBatch()
+	ll.Clear()
+	ll.appendAll(arr)
+	return nil
+}
+
+// Search finds the first occurrence of a value in the list and returns its
+// index, or -1. A value that can't be compared with ==, such as a slice, is
+// never found; use SearchFunc instead.
+func (ll *LinkedList) Search(value any) int {
+	if !equality.Comparable(value) {
+		return -1
+	}
+	return ll.SearchFunc(func(v any) bool { return v == value })
+}
+
+// SearchFunc returns the index of the first value for which match returns
+// true, or -1. It is the same as IndexFunc, named to go with Search.
+func (ll *LinkedList) SearchFunc(match func(any) bool) int {
+	return ll.IndexFunc(match)
+}
+
+// IndexFunc returns the index of the first value for which match returns
+// true, or -1.
+func (ll *LinkedList) IndexFunc(match func(any) bool) int {
+	for i, v := range ll.All() {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexFunc returns the index of the last value for which match returns
+// true, or -1. It always walks the whole list.
+func (ll *LinkedList) LastIndexFunc(match func(any) bool) int {
+	last := -1
+	for i, v := range ll.All() {
+		if match(v) {
+			last = i
+		}
+	}
+	return last
+}
+
+// Shift removes the first element from the list.
+func (ll *LinkedList) Shift() error {
+	if ll.head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	return ll.Remove(ll.head)
+}
+
+// Pop removes the last element from the list.
+func (ll *LinkedList) Pop() error {
+	if ll.head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	last := ll.head
+	for last.next != nil {
+		last = last.next
+	}
+	return ll.Remove(last)
+}
+
+// Delete removes the first occurrence of the specified value from the list.
+// Deleting a value that can't be compared with ==, such as a slice, returns
+// ErrNotComparable; use DeleteFunc instead.
+func (ll *LinkedList) Delete(value any) error {
+	if !equality.Comparable(value) {
+		return fmt.Errorf("%w: %T; use DeleteFunc", ErrNotComparable, value)
+	}
+	return ll.DeleteFunc(func(v any) bool { return v == value })
+}
+
+// DeleteFunc removes the first value for which match returns true.
+func (ll *LinkedList) DeleteFunc(match func(any) bool) error {
+	if ll.head == nil {
+		return fmt.Errorf("%w, nothing to delete", ErrEmpty)
+	}
+	for current := ll.head; current != nil; current = current.next {
+		if match(current.value) {
+			return ll.Remove(current)
+		}
+	}
+	return fmt.Errorf("%w in the list", ErrNotFound)
+}
+
+// Insert adds a new value at the specified index.
+func (ll *LinkedList) Insert(value any, index int) error {
+	if index < 0 || index > ll.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	if index == 0 {
+		ll.Prepend(value)
+		return nil
+	}
+	prev := ll.at(index - 1)
+	prev.next = &Element{value: value, next: prev.next, list: ll}
+	ll.size++
+	ll.emit(event.Inserted{Index: index, Value: value})
+	return nil
+}
+
+// DeleteAt removes the element at the specified index.
+func (ll *LinkedList) DeleteAt(index int) error {
+	if index < 0 || index >= ll.size {
+		return fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	return ll.Remove(ll.at(index))
+}
+
+// Get returns the value at the specified index.
+func (ll *LinkedList) Get(index int) (any, error) {
+	if index < 0 || index >= ll.size {
+		return nil, fmt.Errorf("%w: index %d, size %d", ErrIndexOutOfBounds, index, ll.size)
+	}
+	return ll.at(index).value, nil
+}
+
+// Reverse reverses the order of elements in the list.
+func (ll *LinkedList) Reverse() {
+	var prev *Element
+	current := ll.head
+	for current != nil {
+		next := current.next
+		current.next = prev
+		prev = current
+		current = next
+	}
+	ll.head = prev
+	if ll.size > 1 {
+		ll.emit(event.Reordered{})
+	}
+}
+
+// All returns an iterator over the indices and values of the list from head
+// to tail.
+func (ll *LinkedList) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		index := 0
+		for current := ll.head; current != nil; current = current.next {
+			if !yield(index, current.value) {
+				return
+			}
+			index++
+		}
+	}
+}
+
+// Contains checks if a value exists in the list. A value that can't be
+// compared with ==, such as a slice, is never found; use ContainsFunc
+// instead.
+func (ll *LinkedList) Contains(value any) bool {
+	return ll.Search(value) >= 0
+}
+
+// ContainsFunc reports whether match returns true for any value.
+func (ll *LinkedList) ContainsFunc(match func(any) bool) bool {
+	return ll.IndexFunc(match) >= 0
+}
+
+// Unique removes duplicate values from the list. If any value can't be
+// compared with ==, such as a slice, it returns ErrNotComparable and leaves
+// the list unchanged; use UniqueBy instead.
+func (ll *LinkedList) Unique() error {
+	return ll.UniqueBy(nil)
+}
+
+// UniqueBy removes every value whose key, as returned by key, matches the
+// key of an earlier value. Keys must be comparable with ==; a nil key
+// function uses the values themselves.
+func (ll *LinkedList) UniqueBy(key func(any) any) error {
+	if ll.head == nil {
+		return ErrEmpty
+	}
+	keys, err := ll.keysOf(key)
+	if err != nil {
+		return err
+	}
+
+	ll.beginBatch()
+	defer ll.endBatch()
+
+	visited := map[any]bool{keys[0]: true}
+	current := ll.head
+	index := 1
+	for i := 1; current.next != nil; i++ {
+		if visited[keys[i]] {
+			removed := current.next
+			current.next = removed.next
+			ll.release(removed)
+			ll.emit(event.Removed{Index: index, Value: removed.value})
+		} else {
+			visited[keys[i]] = true
+			current = current.next
+			index++
+		}
+	}
+	return nil
+}
+
+// Clone returns a shallow copy of the list: a new list holding the same
+// values, without the subscribers.
+func (ll *LinkedList) Clone() *LinkedList {
+	return ll.CloneFunc(func(v any) any { return v })
+}
+
+// CloneFunc returns a copy of the list holding copy(v) for every value v,
+// for deep copies of values such as slices and pointers.
+func (ll *LinkedList) CloneFunc(copy func(any) any) *LinkedList {
+	clone := NewSinglyLinkedList()
+	values := ll.IntoSlice()
+	for i, v := range values {
+		values[i] = copy(v)
+	}
+	clone.appendAll(values)
+	return clone
+}
+
+// Equal reports whether other holds the same values in the same order,
+// compared with ==. Values that can't be compared with ==, such as slices,
+// are never equal; use EqualFunc for them. A nil list counts as empty.
+func (ll *LinkedList) Equal(other *LinkedList) bool {
+	return ll.EqualFunc(other, equality.Equal)
+}
+
+// EqualFunc reports whether other holds the same number of values as the
+// list and eq returns true for every pair of values at the same index. A nil
+// list counts as empty.
+func (ll *LinkedList) EqualFunc(other *LinkedList, eq func(a, b any) bool) bool {
+	if other == nil {
+		return ll.size == 0
+	}
+	if ll.size != other.size {
+		return false
+	}
+	for a, b := ll.head, other.head; a != nil && b != nil; a, b = a.next, b.next {
+		if !eq(a.value, b.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares the list with other lexicographically, using cmp to
+// compare values. It returns the result of cmp for the first pair of values
+// that differ, or, when one list is a prefix of the other, -1 if the list is
+// the shorter one and +1 if other is. It returns 0 if the lists are equal. A
+// nil list counts as empty.
+func (ll *LinkedList) Compare(other *LinkedList, cmp func(a, b any) int) int {
+	a := ll.head
+	var b *Element
+	if other != nil {
+		b = other.head
+	}
+	for ; a != nil && b != nil; a, b = a.next, b.next {
+		if c := cmp(a.value, b.value); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a != nil:
+		return 1
+	case b != nil:
+		return -1
+	}
+	return 0
+}
+
+// Merge appends the values of another list to this one.
+func (ll *LinkedList) Merge(list *LinkedList) error {
+	if list == nil {
+		return fmt.Errorf("cannot merge with nil list")
+	}
+	ll.beginBatch()
+	defer ll.endBatch()
+	ll.appendAll(list.IntoSlice())
+	return nil
+}
+
+// PrintReverse displays the list elements from tail to head.
+func (ll *LinkedList) PrintReverse() {
+	values := ll.IntoSlice()
+	for i := len(values) - 1; i >= 0; i-- {
+		fmt.Print(values[i])
+		if i > 0 {
+			fmt.Print(" -> ")
+		}
+	}
+	fmt.Print("\n")
+}
+
+// Sort orders the elements in the list (supports int, string, float64).
+func (ll *LinkedList) Sort() error {
+	changed := false
+	defer func() {
+		if changed {
+			ll.emit(event.Reordered{})
+		}
+	}()
+
+	for swapped := ll.size > 1; swapped; {
+		swapped = false
+		for current := ll.head; current.next != nil; current = current.next {
+			greater, err := greater(current.value, current.next.value)
+			if err != nil {
+				return err
+			}
+			if greater {
+				current.value, current.next.value = current.next.value, current.value
+				swapped = true
+				changed = true
+			}
+		}
+	}
+	return nil
+}
+
+// GetMiddle returns the middle element of the list. For an even number of
+// elements it returns the first of the two middle ones.
+func (ll *LinkedList) GetMiddle() (any, error) {
+	if ll.head == nil {
+		return nil, ErrEmpty
+	}
+	slow, fast := ll.head, ll.head
+	for fast.next != nil && fast.next.next != nil {
+		slow = slow.next
+		fast = fast.next.next
+	}
+	return slow.value, nil
+}
+
+// Validate checks the integrity of the list structure. Only a bug in this
+// package can make it fail.
+func (ll *LinkedList) Validate() error {
+	count := 0
+	for current := ll.head; current != nil; current = current.next {
+		count++
+		if count > ll.size {
+			return fmt.Errorf("%w: list contains more elements than its length", ErrCorrupt)
+		}
+		if current.list != ll {
+			return fmt.Errorf("%w: element at index %d belongs to another list", ErrCorrupt, count-1)
+		}
+	}
+	if count != ll.size {
+		return fmt.Errorf("%w: actual element count (%d) differs from length (%d)", ErrCorrupt, count, ll.size)
+	}
+	return nil
+}
+
+// Move relocates the element at index from so that it ends up at index to.
+func (ll *LinkedList) Move(from, to int) error {
+	if from < 0 || from >= ll.size || to < 0 || to >= ll.size {
+		return ErrIndexOutOfBounds
+	}
+	if from == to {
+		return nil
+	}
+
+	// Unlink the element at from
+	var element *Element
+	if from == 0 {
+		element = ll.head
+		ll.head = element.next
+	} else {
+		prev := ll.at(from - 1)
+		element = prev.next
+		prev.next = element.next
+	}
+
+	// Link it back in at to
+	if to == 0 {
+		element.next = ll.head
+		ll.head = element
+	} else {
+		prev := ll.at(to - 1)
+		element.next = prev.next
+		prev.next = element
+	}
+	ll.emit(event.Moved{From: from, To: to, Value: element.value})
+	return nil
+}
+
+// Subscribe registers fn to be called after every change to the list and
+// returns a function that unsubscribes it. Bulk operations such as FromSlice,
+// Merge and Unique deliver their changes as a single event.Batch.
+//
+// Subscribe creates the list's event hub on first use, so like the other
+// methods it must not run concurrently with changes to the list. The
+// function it returns may be called from any goroutine.
+func (ll *LinkedList) Subscribe(fn func(event.Event)) (unsubscribe func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.Subscribe(fn)
+}
+
+// SubscribeChan is like Subscribe but delivers events on a channel with the
+// given buffer size. Mutations block while the buffer is full. Unsubscribing
+// closes the channel.
+func (ll *LinkedList) SubscribeChan(buffer int) (<-chan event.Event, func()) {
+	if ll.observers == nil {
+		ll.observers = &event.Hub{}
+	}
+	return ll.observers.SubscribeChan(buffer)
+}
+
+// at returns the element at index, which must be in range.
+func (ll *LinkedList) at(index int) *Element {
+	current := ll.head
+	for i := 0; i < index; i++ {
+		current = current.next
+	}
+	return current
+}
+
+// appendAll appends values in a single pass over the list.
+func (ll *LinkedList) appendAll(values []any) {
+	var last *Element
+	if ll.head != nil {
+		last = ll.at(ll.size - 1)
+	}
+	for _, value := range values {
+		element := &Element{value: value, list: ll}
+		if last == nil {
+			ll.head = element
+		} else {
+			last.next = element
+		}
+		last = element
+		ll.size++
+		ll.emit(event.Inserted{Index: ll.size - 1, Value: value})
+	}
+}
+
+// release marks e as removed from the list and updates the length.
+func (ll *LinkedList) release(e *Element) {
+	e.next = nil
+	e.list = nil
+	ll.size--
+}
+
+// indexOf returns the position of e in the list, or -1 if it is not there.
+func (ll *LinkedList) indexOf(e *Element) int {
+	index := 0
+	for current := ll.head; current != nil; current = current.next {
+		if current == e {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// keysOf returns the key of every value in the list, or ErrNotComparable if
+// one of them can't be used as a map key. A nil key function uses the values
+// themselves.
+func (ll *LinkedList) keysOf(key func(any) any) ([]any, error) {
+	keys := make([]any, 0, ll.size)
+	for current := ll.head; current != nil; current = current.next {
+		k := current.value
+		if key != nil {
+			k = key(k)
+		}
+		if !equality.Comparable(k) {
+			return nil, fmt.Errorf("%w: %T at index %d", ErrNotComparable, k, len(keys))
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// greater reports whether a sorts after b. Both must be the same supported type.
+func greater(a, b any) (bool, error) {
+	switch x := a.(type) {
+	case int:
+		y, ok := b.(int)
+		if !ok {
+			return false, ErrMismatchedTypes
+		}
+		return x > y, nil
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return false, ErrMismatchedTypes
+		}
+		return x > y, nil
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return false, ErrMismatchedTypes
+		}
+		return x > y, nil
+	}
+	return false, ErrUnsupportedType
+}
+
+// emit sends e to subscribers, if there are any.
+func (ll *LinkedList) emit(e event.Event) {
+	if ll.observers.Active() {
+		ll.observers.Emit(e)
+	}
+}
+
+// beginBatch starts collecting events for a bulk operation.
+func (ll *LinkedList) beginBatch() {
+	if ll.observers != nil {
+		ll.observers.Begin()
+	}
+}
+
+// endBatch delivers the events collected since beginBatch.
+func (ll *LinkedList) endBatch() {
+	if ll.observers != nil {
+		ll.observers.End()
+	}
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JustMrNone/ll/listtest"
+	"github.com/JustMrNone/ll/v2/doubly"
+	"github.com/JustMrNone/ll/v2/singly"
+)
+
+// singlyList adapts a v2 singly list to listtest.List.
+type singlyList struct {
+	*singly.LinkedList
+}
+
+func (l singlyList) Append(value any) error {
+	l.LinkedList.Append(value)
+	return nil
+}
+
+func (l singlyList) Prepend(value any) error {
+	l.LinkedList.Prepend(value)
+	return nil
+}
+
+func (l singlyList) Search(value any) (int, error) {
+	if index := l.LinkedList.Search(value); index >= 0 {
+		return index, nil
+	}
+	return -1, singly.ErrNotFound
+}
+
+func (l singlyList) SearchFunc(match func(any) bool) (int, error) {
+	if index := l.LinkedList.SearchFunc(match); index >= 0 {
+		return index, nil
+	}
+	return -1, singly.ErrNotFound
+}
+
+func (l singlyList) Reverse() error {
+	l.LinkedList.Reverse()
+	return nil
+}
+
+func (l singlyList) Merge(other listtest.List) error {
+	if other == nil {
+		return l.LinkedList.Merge(nil)
+	}
+	return l.LinkedList.Merge(other.(singlyList).LinkedList)
+}
+
+// doublyList adapts a v2 doubly list to listtest.List.
+type doublyList struct {
+	*doubly.LinkedList
+}
+
+func (l doublyList) Merge(other listtest.List) error {
+	if other == nil {
+		return l.LinkedList.Merge(nil)
+	}
+	return l.LinkedList.Merge(other.(doublyList).LinkedList)
+}
+
+var singlyErrors = listtest.Errors{
+	Empty:            singly.ErrEmpty,
+	IndexOutOfBounds: singly.ErrIndexOutOfBounds,
+	NotFound:         singly.ErrNotFound,
+	MismatchedTypes:  singly.ErrMismatchedTypes,
+	UnsupportedType:  singly.ErrUnsupportedType,
+	NotComparable:    singly.ErrNotComparable,
+
+	SearchIgnoresNotComparable: true,
+}
+
+var doublyErrors = listtest.Errors{
+	Empty:            doubly.ErrEmpty,
+	IndexOutOfBounds: doubly.ErrIndexOutOfBounds,
+	NotFound:         doubly.ErrNotFound,
+	MismatchedTypes:  doubly.ErrMismatchedTypes,
+	UnsupportedType:  doubly.ErrUnsupportedType,
+	NotComparable:    doubly.ErrNotComparable,
+}
+
+func newSingly() listtest.List {
+	return singlyList{singly.NewSinglyLinkedList()}
+}
+
+func newDoubly() listtest.List {
+	return doublyList{doubly.NewDoublyLinkedList()}
+}
+
+func TestSinglyConformance(t *testing.T) {
+	listtest.Run(t, newSingly, singlyErrors)
+}
+
+func TestDoublyConformance(t *testing.T) {
+	listtest.Run(t, newDoubly, doublyErrors)
+}
+
+func TestSinglyModel(t *testing.T) {
+	listtest.RunModel(t, newSingly, singlyErrors, nil)
+}
+
+func TestDoublyModel(t *testing.T) {
+	listtest.RunModel(t, newDoubly, doublyErrors, nil)
+}
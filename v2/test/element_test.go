@@ -0,0 +1,194 @@
+package test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/JustMrNone/ll/event"
+	"github.com/JustMrNone/ll/v2/doubly"
+	"github.com/JustMrNone/ll/v2/singly"
+)
+
+func TestDoublyElements(t *testing.T) {
+	list := doubly.NewDoublyLinkedList()
+	list.FromSlice([]any{1, 2, 3})
+
+	var forward, backward []any
+	for e := list.Front(); e != nil; e = e.Next() {
+		forward = append(forward, e.Value())
+	}
+	for e := list.Back(); e != nil; e = e.Prev() {
+		backward = append(backward, e.Value())
+	}
+	if !slices.Equal(forward, []any{1, 2, 3}) || !slices.Equal(backward, []any{3, 2, 1}) {
+		t.Errorf("Unexpected traversal: forward %v, backward %v", forward, backward)
+	}
+
+	middle := list.Front().Next()
+	if _, err := list.InsertBefore(middle, 1.5); err != nil {
+		t.Fatalf("InsertBefore failed: %v", err)
+	}
+	if _, err := list.InsertAfter(middle, 2.5); err != nil {
+		t.Fatalf("InsertAfter failed: %v", err)
+	}
+	if err := list.Remove(middle); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if middle.List() != nil || middle.Next() != nil || middle.Prev() != nil {
+		t.Error("Expected a removed element to be detached")
+	}
+	if got := list.IntoSlice(); !slices.Equal(got, []any{1, 1.5, 2.5, 3}) || list.Len() != 4 {
+		t.Errorf("Expected [1 1.5 2.5 3], got %v (len %d)", got, list.Len())
+	}
+
+	// Handles from another list, or removed ones, are rejected
+	other := doubly.NewDoublyLinkedList()
+	other.Append(9)
+	if err := list.Remove(other.Front()); !errors.Is(err, doubly.ErrNotInList) {
+		t.Errorf("Expected ErrNotInList for a foreign element, got %v", err)
+	}
+	if err := list.Remove(middle); !errors.Is(err, doubly.ErrNotInList) {
+		t.Errorf("Expected ErrNotInList for a removed element, got %v", err)
+	}
+	if err := list.Validate(); err != nil {
+		t.Errorf("List validation failed: %v", err)
+	}
+
+	if err := list.Set(3, "three"); err != nil || list.Back().Value() != "three" {
+		t.Errorf("Expected Set to replace the last value, got %v (err %v)", list.Back().Value(), err)
+	}
+	if err := list.Set(4, 0); !errors.Is(err, doubly.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds for Set(Len()), got %v", err)
+	}
+
+	t.Run("MoveToBack", func(t *testing.T) {
+		list := doubly.NewDoublyLinkedList()
+		list.FromSlice([]any{1, 2, 3})
+		if err := list.MoveToBack(list.Front()); err != nil {
+			t.Fatalf("MoveToBack failed: %v", err)
+		}
+		if got := list.IntoSlice(); !slices.Equal(got, []any{2, 3, 1}) {
+			t.Errorf("Expected [2 3 1], got %v", got)
+		}
+		if err := list.MoveToBack(list.Back()); err != nil || !slices.Equal(list.IntoSlice(), []any{2, 3, 1}) {
+			t.Errorf("Expected moving the back element to change nothing, got %v (err %v)", list.IntoSlice(), err)
+		}
+		if err := list.MoveToBack(other.Front()); !errors.Is(err, doubly.ErrNotInList) {
+			t.Errorf("Expected ErrNotInList for a foreign element, got %v", err)
+		}
+		if err := list.MoveToBack(nil); !errors.Is(err, doubly.ErrNotInList) {
+			t.Errorf("Expected ErrNotInList for nil, got %v", err)
+		}
+		if err := list.Validate(); err != nil {
+			t.Errorf("List validation failed: %v", err)
+		}
+	})
+}
+
+func TestSinglyElements(t *testing.T) {
+	list := singly.NewSinglyLinkedList()
+	list.FromSlice([]any{1, 2, 3})
+
+	second := list.Front().Next()
+	if second.Prev() != list.Front() || list.Front().Prev() != nil {
+		t.Error("Expected Prev to find the preceding element")
+	}
+	if _, err := list.InsertAfter(second, 2.5); err != nil {
+		t.Fatalf("InsertAfter failed: %v", err)
+	}
+	if err := list.Remove(second); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if second.List() != nil {
+		t.Error("Expected a removed element to be detached")
+	}
+	if got := list.IntoSlice(); !slices.Equal(got, []any{1, 2.5, 3}) || list.Len() != 3 {
+		t.Errorf("Expected [1 2.5 3], got %v (len %d)", got, list.Len())
+	}
+	if _, err := list.InsertAfter(second, 4); !errors.Is(err, singly.ErrNotInList) {
+		t.Errorf("Expected ErrNotInList for a removed element, got %v", err)
+	}
+
+	// The index-based API matches v1, with the Insert and Get edge cases fixed
+	list.Insert(0, 0)
+	if v, _ := list.Get(0); v != 0 {
+		t.Errorf("Expected Insert at 0 to prepend, got %v", list.IntoSlice())
+	}
+	if _, err := list.Get(list.Len()); !errors.Is(err, singly.ErrIndexOutOfBounds) {
+		t.Errorf("Expected ErrIndexOutOfBounds for Get(Len()), got %v", err)
+	}
+	if err := list.Validate(); err != nil {
+		t.Errorf("List validation failed: %v", err)
+	}
+}
+
+func TestListOperations(t *testing.T) {
+	s := singly.NewSinglyLinkedList()
+	d := doubly.NewDoublyLinkedList()
+	for _, v := range []any{3, 1, 3, 2} {
+		s.Append(v)
+		d.Append(v)
+	}
+	s.Unique()
+	d.Unique()
+	s.Sort()
+	d.Sort()
+	s.Reverse()
+	d.Reverse()
+	s.Move(0, 2)
+	d.Move(0, 2)
+	want := []any{2, 1, 3}
+	if got := s.IntoSlice(); !slices.Equal(got, want) {
+		t.Errorf("singly: expected %v, got %v", want, got)
+	}
+	if got := d.IntoSlice(); !slices.Equal(got, want) {
+		t.Errorf("doubly: expected %v, got %v", want, got)
+	}
+	if s.Validate() != nil || d.Validate() != nil {
+		t.Error("Expected both lists to validate")
+	}
+}
+
+func TestCloneEqual(t *testing.T) {
+	d := doubly.NewDoublyLinkedList()
+	d.FromSlice([]any{1, 2, 3})
+	clone := d.Clone()
+	if !d.Equal(clone) || clone.Front().List() != clone {
+		t.Fatalf("Clone should be an equal list with its own elements")
+	}
+	clone.Set(2, 4)
+	if d.Equal(clone) || d.Compare(clone, func(a, b any) int { return a.(int) - b.(int) }) >= 0 {
+		t.Errorf("Expected [1 2 3] to sort before [1 2 4]")
+	}
+
+	s := singly.NewSinglyLinkedList()
+	s.FromSlice([]any{1, 2})
+	sc := s.CloneFunc(func(v any) any { return v.(int) * 10 })
+	if !slices.Equal(sc.IntoSlice(), []any{10, 20}) || sc.Len() != 2 {
+		t.Errorf("CloneFunc gave %v", sc.IntoSlice())
+	}
+	if s.Equal(sc) || !s.Equal(s.Clone()) {
+		t.Errorf("Equal gave the wrong result")
+	}
+}
+
+func TestElementEvents(t *testing.T) {
+	list := doubly.NewDoublyLinkedList()
+	var got []event.Event
+	unsubscribe := list.Subscribe(func(e event.Event) { got = append(got, e) })
+	list.Append(1)
+	list.Append(2)
+	list.MoveToBack(list.Front())
+	unsubscribe()
+	list.Append(3)
+
+	want := []event.Event{
+		event.Inserted{Index: 0, Value: 1},
+		event.Inserted{Index: 1, Value: 2},
+		event.Moved{From: 0, To: 1, Value: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected events %v, got %v", want, got)
+	}
+}
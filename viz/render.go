@@ -0,0 +1,111 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT renders the graph in the Graphviz DOT language. Anomalous edges
+// are drawn in red and nodes unreachable from Head are dashed.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", g.kind)
+	fmt.Fprintf(bw, "  rankdir=LR;\n")
+	fmt.Fprintf(bw, "  label=%s;\n", dotQuote(fmt.Sprintf("%s list, Size = %d", g.kind, g.size)))
+	fmt.Fprintf(bw, "  node [shape=box];\n")
+	fmt.Fprintf(bw, "  %s [shape=plaintext, label=\"Head\"];\n", headID)
+	if g.hasTail {
+		fmt.Fprintf(bw, "  %s [shape=plaintext, label=\"Tail\"];\n", tailID)
+	}
+	if g.usesNil() {
+		fmt.Fprintf(bw, "  %s [shape=point];\n", nilID)
+	}
+	for _, n := range g.nodes {
+		attrs := "label=" + dotQuote(n.label)
+		if n.stray {
+			attrs += ", style=dashed, color=red"
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", n.id, attrs)
+	}
+	for _, e := range g.edges {
+		var attrs []string
+		switch e.kind {
+		case edgeNext:
+			attrs = append(attrs, `label="next"`)
+		case edgePrev:
+			attrs = append(attrs, `label="prev"`, "style=dashed")
+		}
+		if e.anomaly != "" {
+			attrs = append(attrs, "color=red", "fontcolor=red", "penwidth=2",
+				"xlabel="+dotQuote(e.anomaly))
+		}
+		fmt.Fprintf(bw, "  %s -> %s", e.from, e.to)
+		if len(attrs) > 0 {
+			fmt.Fprintf(bw, " [%s]", strings.Join(attrs, ", "))
+		}
+		fmt.Fprintf(bw, ";\n")
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart. Anomalous edges
+// are drawn in red and nodes unreachable from Head are dashed.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "flowchart LR\n")
+	fmt.Fprintf(bw, "  %%%% %s list, Size = %d\n", g.kind, g.size)
+	fmt.Fprintf(bw, "  %s([Head])\n", headID)
+	if g.hasTail {
+		fmt.Fprintf(bw, "  %s([Tail])\n", tailID)
+	}
+	if g.usesNil() {
+		fmt.Fprintf(bw, "  %s((nil))\n", nilID)
+	}
+	for _, n := range g.nodes {
+		fmt.Fprintf(bw, "  %s[%s]\n", n.id, mermaidQuote(n.label))
+		if n.stray {
+			fmt.Fprintf(bw, "  style %s stroke:red,stroke-dasharray:5 5\n", n.id)
+		}
+	}
+
+	var bad []string
+	for i, e := range g.edges {
+		label := e.kind
+		if e.anomaly != "" {
+			label += ": " + e.anomaly
+			bad = append(bad, fmt.Sprint(i))
+		}
+		arrow := "-->"
+		if e.kind == edgePrev {
+			arrow = "-.->"
+		}
+		if e.kind == edgeHead || e.kind == edgeTail {
+			if e.anomaly == "" {
+				fmt.Fprintf(bw, "  %s %s %s\n", e.from, arrow, e.to)
+				continue
+			}
+			label = e.anomaly
+		}
+		fmt.Fprintf(bw, "  %s %s|%s| %s\n", e.from, arrow, mermaidQuote(label), e.to)
+	}
+	if len(bad) > 0 {
+		fmt.Fprintf(bw, "  linkStyle %s stroke:red,stroke-width:3px\n", strings.Join(bad, ","))
+	}
+	return bw.Flush()
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// mermaidQuote quotes s as a Mermaid label, escaping characters that would
+// end it early.
+func mermaidQuote(s string) string {
+	r := strings.NewReplacer(`"`, "#quot;", "\n", " ", "|", "#124;")
+	return `"` + r.Replace(s) + `"`
+}
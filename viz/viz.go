@@ -0,0 +1,201 @@
+// Package viz renders the node structure of singly and doubly linked lists as
+// Graphviz DOT or Mermaid diagrams. It follows the raw Head, Tail, Next and
+// Prev pointers rather than trusting Size, so it can draw corrupted lists:
+// cycles, Prev pointers that don't point back and nodes only reachable
+// through Tail are highlighted instead of looping forever.
+package viz
+
+import (
+	"fmt"
+
+	"github.com/JustMrNone/ll/doubly"
+	"github.com/JustMrNone/ll/singly"
+)
+
+// Edge kinds.
+const (
+	edgeNext = "next"
+	edgePrev = "prev"
+	edgeHead = "Head"
+	edgeTail = "Tail"
+)
+
+// IDs of the marker and nil nodes.
+const (
+	headID = "head"
+	tailID = "tail"
+	nilID  = "nil"
+)
+
+// node is a list node in the diagram.
+type node struct {
+	id    string
+	label string
+	stray bool // Not reachable from Head through Next
+}
+
+// edge is a pointer in the diagram.
+type edge struct {
+	from, to string
+	kind     string
+	anomaly  string // Why the pointer is wrong, empty if it is fine
+}
+
+// Graph is the diagram of a list's structure, ready to render.
+type Graph struct {
+	kind    string
+	size    int
+	hasTail bool
+	nodes   []node
+	edges   []edge
+}
+
+// Singly builds the diagram of a singly linked list.
+func Singly(l *singly.LinkedList) *Graph {
+	g := &Graph{kind: "singly", size: l.Size}
+	ids := make(map[*singly.Node]string)
+
+	prevID := ""
+	for current := l.Head; current != nil; current = current.Next {
+		id := g.addNode(fmt.Sprint(current.Value), false)
+		ids[current] = id
+		if prevID != "" {
+			g.addEdge(prevID, id, edgeNext, "")
+		}
+		prevID = id
+		if nextID, ok := ids[current.Next]; ok {
+			g.addEdge(id, nextID, edgeNext, "cycle back-edge")
+			break
+		}
+	}
+
+	if l.Head == nil {
+		g.addEdge(headID, nilID, edgeHead, "")
+	} else {
+		g.addEdge(headID, ids[l.Head], edgeHead, "")
+	}
+	return g
+}
+
+// Doubly builds the diagram of a doubly linked list.
+func Doubly(l *doubly.LinkedList) *Graph {
+	g := &Graph{kind: "doubly", size: l.Size, hasTail: true}
+	ids := make(map[*doubly.Node]string)
+	var order []*doubly.Node
+
+	// Follow Next from Head
+	for current := l.Head; current != nil; current = current.Next {
+		id := g.addNode(fmt.Sprint(current.Value), false)
+		ids[current] = id
+		if len(order) > 0 {
+			g.addEdge(ids[order[len(order)-1]], id, edgeNext, "")
+		}
+		order = append(order, current)
+		if nextID, ok := ids[current.Next]; ok {
+			g.addEdge(id, nextID, edgeNext, "cycle back-edge")
+			break
+		}
+	}
+
+	// Nodes that are only reachable backwards from Tail
+	var strays []*doubly.Node
+	for current := l.Tail; current != nil; current = current.Prev {
+		if _, ok := ids[current]; ok {
+			break
+		}
+		ids[current] = g.addNode(fmt.Sprint(current.Value), true)
+		strays = append(strays, current)
+	}
+	for _, n := range strays {
+		if nextID, ok := ids[n.Next]; ok {
+			g.addEdge(ids[n], nextID, edgeNext, "unreachable from Head")
+		}
+	}
+
+	// Prev pointers of the Head chain must mirror Next
+	for i, n := range order {
+		var expected *doubly.Node
+		if i > 0 {
+			expected = order[i-1]
+		}
+		if n.Prev == expected {
+			if n.Prev != nil {
+				g.addEdge(ids[n], ids[n.Prev], edgePrev, "")
+			}
+			continue
+		}
+		if n.Prev == nil {
+			g.addEdge(ids[n], nilID, edgePrev, "prev is nil but should point back")
+			continue
+		}
+		prevID, ok := ids[n.Prev]
+		if !ok {
+			prevID = g.addNode(fmt.Sprint(n.Prev.Value), true)
+			ids[n.Prev] = prevID
+		}
+		g.addEdge(ids[n], prevID, edgePrev, "prev does not point back")
+	}
+	for _, n := range strays {
+		if n.Prev != nil {
+			g.addEdge(ids[n], ids[n.Prev], edgePrev, "unreachable from Head")
+		}
+	}
+
+	// Markers
+	switch {
+	case l.Head == nil && l.Tail != nil:
+		g.addEdge(headID, nilID, edgeHead, "head is nil but tail is not")
+	case l.Head == nil:
+		g.addEdge(headID, nilID, edgeHead, "")
+	default:
+		g.addEdge(headID, ids[l.Head], edgeHead, "")
+	}
+	var last *doubly.Node
+	if len(order) > 0 {
+		last = order[len(order)-1]
+	}
+	switch {
+	case l.Tail == nil && last != nil:
+		g.addEdge(tailID, nilID, edgeTail, "tail is nil but head is not")
+	case l.Tail == nil:
+		g.addEdge(tailID, nilID, edgeTail, "")
+	case l.Tail != last || last.Next != nil:
+		g.addEdge(tailID, ids[l.Tail], edgeTail, "tail is not the last node")
+	default:
+		g.addEdge(tailID, ids[l.Tail], edgeTail, "")
+	}
+	return g
+}
+
+// Anomalies returns a description of every suspicious pointer in the graph.
+func (g *Graph) Anomalies() []string {
+	var found []string
+	for _, e := range g.edges {
+		if e.anomaly != "" {
+			found = append(found, fmt.Sprintf("%s %s -> %s: %s", e.kind, e.from, e.to, e.anomaly))
+		}
+	}
+	return found
+}
+
+// addNode adds a list node and returns its ID.
+func (g *Graph) addNode(label string, stray bool) string {
+	id := fmt.Sprintf("n%d", len(g.nodes))
+	g.nodes = append(g.nodes, node{id: id, label: label, stray: stray})
+	return id
+}
+
+// addEdge adds a pointer between two nodes.
+func (g *Graph) addEdge(from, to, kind, anomaly string) {
+	g.edges = append(g.edges, edge{from: from, to: to, kind: kind, anomaly: anomaly})
+}
+
+// usesNil reports whether any edge points at nil.
+func (g *Graph) usesNil() bool {
+	for _, e := range g.edges {
+		if e.to == nilID {
+			return true
+		}
+	}
+	return false
+}